@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/org/ranas-bdi-backend/internal/adapters/db/postgres"
+	"github.com/org/ranas-bdi-backend/internal/adapters/export"
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	platformdb "github.com/org/ranas-bdi-backend/internal/platform/db"
+)
+
+// runExport implements `server export`, writing one dataset to a file or
+// stdout with the same schema as GET /exports/:dataset.
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	datasetFlag := fs.String("dataset", "", "sessions, matches, moves or match_stats")
+	formatFlag := fs.String("format", string(export.CSV), "csv or parquet")
+	from := fs.String("from", "", "include matches started at or after this time (RFC 3339 or YYYY-MM-DD)")
+	to := fs.String("to", "", "include matches started before this time (RFC 3339 or YYYY-MM-DD)")
+	playerID := fs.String("player", "", "only this player ID")
+	difficultyID := fs.Int("difficulty", 0, "only this difficulty ID")
+	experiment := fs.String("experiment", "", "only matches tagged with an arm of this experiment key")
+	arm := fs.String("arm", "", "only matches played under this arm (needs -experiment)")
+	out := fs.String("o", "-", "output file, - for stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	dataset, err := export.ParseDataset(*datasetFlag)
+	if err != nil {
+		return err
+	}
+	format, err := export.ParseFormat(*formatFlag)
+	if err != nil {
+		return err
+	}
+	var filter entity.ExportFilter
+	if filter.From, err = parseFlagTime("from", *from); err != nil {
+		return err
+	}
+	if filter.To, err = parseFlagTime("to", *to); err != nil {
+		return err
+	}
+	if *playerID != "" {
+		filter.PlayerID = playerID
+	}
+	if *difficultyID != 0 {
+		filter.DifficultyID = difficultyID
+	}
+	if *experiment != "" {
+		filter.Experiment = experiment
+	}
+	if *arm != "" {
+		filter.Arm = arm
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := platformdb.InitFromEnv(ctx); err != nil {
+		return fmt.Errorf("failed to init database: %w", err)
+	}
+	defer platformdb.Close()
+	svc := usecase.NewExportService(postgres.NewExportRepository(platformdb.MustGet()))
+	if err := svc.Validate(filter); err != nil {
+		return err
+	}
+
+	f := os.Stdout
+	if *out != "-" {
+		if f, err = os.Create(*out); err != nil {
+			return err
+		}
+		defer f.Close()
+	}
+	w := bufio.NewWriter(f)
+	if err := export.Write(ctx, svc, w, dataset, format, filter); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	// Only files are synced: stdout is often a pipe, where fsync fails with
+	// EINVAL.
+	if *out == "-" {
+		return nil
+	}
+	return f.Sync()
+}
+
+func parseFlagTime(name, v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("-%s must be an RFC 3339 timestamp or YYYY-MM-DD", name)
+}
@@ -2,23 +2,71 @@ package main
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 
 	"github.com/org/ranas-bdi-backend/internal/adapters/db/postgres"
 	httpadapter "github.com/org/ranas-bdi-backend/internal/adapters/http"
+	"github.com/org/ranas-bdi-backend/internal/adapters/lrs"
+	"github.com/org/ranas-bdi-backend/internal/adapters/objectstore"
+	"github.com/org/ranas-bdi-backend/internal/adapters/realtime"
+	"github.com/org/ranas-bdi-backend/internal/adapters/webhook"
 	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+	"github.com/org/ranas-bdi-backend/internal/platform/buildinfo"
 	platformdb "github.com/org/ranas-bdi-backend/internal/platform/db"
+	"github.com/org/ranas-bdi-backend/internal/platform/httpserver"
+	"github.com/org/ranas-bdi-backend/internal/platform/logging"
+	"github.com/org/ranas-bdi-backend/internal/platform/metrics"
+	"github.com/org/ranas-bdi-backend/internal/platform/tracing"
+	"github.com/org/ranas-bdi-backend/internal/platform/worker"
 )
 
 func main() {
 	_ = godotenv.Load()
 
-	ctx := context.Background()
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		// Data goes to stdout, so logs must not.
+		slog.SetDefault(logging.New(os.Stderr, logging.LevelFromEnv()))
+		if err := runExport(os.Args[2:]); err != nil {
+			slog.Error("export failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	slog.SetDefault(logging.New(os.Stdout, logging.LevelFromEnv()))
+
+	if err := run(); err != nil {
+		slog.Error("server exited", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	adminTokens, err := httpadapter.ParseAdminTokens(os.Getenv("ADMIN_TOKENS"))
+	if err != nil {
+		return fmt.Errorf("invalid ADMIN_TOKENS: %w", err)
+	}
+
+	traceCfg := tracing.ConfigFromEnv()
+	shutdownTracing, err := tracing.Init(ctx, traceCfg)
+	if err != nil {
+		return err
+	}
+
 	if err := platformdb.InitFromEnv(ctx); err != nil {
-		log.Fatalf("failed to init database: %v", err)
+		return fmt.Errorf("failed to init database: %w", err)
 	}
 	defer platformdb.Close()
 
@@ -28,21 +76,177 @@ func main() {
 	matchRepo := postgres.NewMatchRepository(pool)
 	moveRepo := postgres.NewMoveRepository(pool)
 	difficultyRepo := postgres.NewDifficultyRepository(pool)
+	matchStatsRepo := postgres.NewMatchStatsRepository(pool)
+	matchMetricsRepo := postgres.NewMatchMetricsRepository(pool)
+	hintRepo := postgres.NewHintRepository(pool)
+	pauseRepo := postgres.NewMatchPauseRepository(pool)
+	curriculumRepo := postgres.NewCurriculumRepository(pool)
+	experimentRepo := postgres.NewExperimentRepository(pool)
+	consentRepo := postgres.NewConsentRepository(pool)
+	privacyRepo := postgres.NewPrivacyRepository(pool)
+	outboxRepo := postgres.NewOutboxRepository(pool)
+	analyticsRepo := postgres.NewAnalyticsRepository(pool)
+	reportRepo := postgres.NewReportRepository(pool)
+	cohortRepo := postgres.NewCohortRepository(pool)
+	exportRepo := postgres.NewExportRepository(pool)
+	xapiQueue := postgres.NewXAPIQueue(pool)
+	webhookRepo := postgres.NewWebhookRepository(pool)
+	archiveRepo := postgres.NewArchiveRepository(pool)
+	auditRepo := postgres.NewAuditRepository(pool)
+
+	workers := worker.NewGroup(context.Background())
+
+	hub := realtime.NewHub(realtime.DefaultBuffer)
+	var events ports.EventPublisher = hub
+	if os.Getenv("EVENT_BUS") == "postgres" {
+		bus := postgres.NewNotifyBus(pool, hub)
+		workers.Go(bus.Run)
+		events = bus
+	}
+
+	registry := metrics.New()
+	registry.RegisterPool(platformdb.Get)
 
 	sessionService := usecase.NewSessionService(sessionRepo)
-	matchService := usecase.NewMatchService(matchRepo)
-	moveService := usecase.NewMoveService(moveRepo)
+	matchService := usecase.NewMatchService(matchRepo, pauseRepo, registry, events)
+	moveService := usecase.NewMoveService(moveRepo, pauseRepo, registry, events)
 	difficultyService := usecase.NewDifficultyService(difficultyRepo)
+	healthService := usecase.NewHealthService(postgres.NewHealthProbe(platformdb.Get))
+	streamService := usecase.NewMatchStreamService(matchRepo, moveRepo, difficultyRepo, matchStatsRepo, hub)
+	replayService := usecase.NewReplayService(matchRepo, moveRepo, difficultyRepo, hintRepo)
+	matchMetricsService := usecase.NewMatchMetricsService(matchRepo, moveRepo, difficultyRepo, matchStatsRepo, matchMetricsRepo, hintRepo)
+	hintService := usecase.NewHintService(matchRepo, hintRepo)
+	curriculumService := usecase.NewCurriculumService(curriculumRepo, sessionRepo, matchRepo, difficultyRepo)
+	experimentService := usecase.NewExperimentService(experimentRepo)
+	consentService := usecase.NewConsentService(consentRepo)
+	privacyService := usecase.NewPrivacyService(privacyRepo, exportRepo, cohortRepo, consentRepo)
+	analyticsService := usecase.NewAnalyticsService(analyticsRepo)
+	reportService := usecase.NewReportService(reportRepo, cohortRepo)
+	exportService := usecase.NewExportService(exportRepo)
+	auditService := usecase.NewAuditService(auditRepo)
+
+	archiveDir := os.Getenv("ARCHIVE_DIR")
+	if archiveDir == "" {
+		archiveDir = "archive"
+	}
+	retentionService := usecase.NewRetentionService(archiveRepo, moveRepo, matchRepo, objectstore.NewFS(archiveDir))
+	if err := retentionFromEnv(retentionService); err != nil {
+		return err
+	}
 
-	handler := httpadapter.NewHandler(sessionService, matchService, moveService, difficultyService)
+	webhookService := usecase.NewWebhookService(webhookRepo, webhook.NewSender(nil))
+	workers.Go(webhookService.Run)
+	workers.Go(matchService.Run)
+	workers.Go(retentionService.Run)
+
+	lrsCfg := lrs.ConfigFromEnv()
+	var lrsClient ports.LRSClient
+	if lrsCfg.Endpoint != "" {
+		lrsClient = lrs.NewClient(lrsCfg, nil)
+	}
+	xapiService := usecase.NewXAPIService(
+		sessionRepo, matchRepo, moveRepo, hintRepo, difficultyRepo, matchStatsRepo,
+		xapiQueue, lrsClient, lrsCfg.Builder(),
+	)
+
+	// Handlers must be registered before the relay starts.
+	outboxRelay := usecase.NewOutboxRelay(outboxRepo)
+	if err := outboxFromEnv(outboxRelay); err != nil {
+		return err
+	}
+	outboxRelay.Register("match_metrics", matchMetricsService.Refresh, usecase.MatchMetricsEventTypes...)
+	outboxRelay.Register("hints", hintService.Resolve, usecase.HintEventTypes...)
+	outboxRelay.Register("webhooks", webhookService.Enqueue, usecase.WebhookEventTypes...)
+	if lrsClient != nil {
+		outboxRelay.Register("xapi", xapiService.Enqueue, usecase.XAPIEventTypes...)
+		workers.Go(xapiService.Run)
+	}
+	workers.Go(outboxRelay.Run)
+
+	handlerOpts := []httpadapter.Option{
+		httpadapter.WithHealth(healthService),
+		httpadapter.WithMetrics(registry),
+		httpadapter.WithStreams(streamService),
+		httpadapter.WithReplays(replayService),
+		httpadapter.WithMatchMetrics(matchMetricsService),
+		httpadapter.WithHints(hintService),
+		httpadapter.WithCurricula(curriculumService),
+		httpadapter.WithExperiments(experimentService),
+		httpadapter.WithConsents(consentService),
+		httpadapter.WithPrivacy(privacyService),
+		httpadapter.WithRetention(retentionService),
+		httpadapter.WithAudit(auditService),
+		httpadapter.WithAnalytics(analyticsService),
+		httpadapter.WithReports(reportService),
+		httpadapter.WithExports(exportService),
+		httpadapter.WithXAPI(xapiService),
+		httpadapter.WithAdminTokens(adminTokens),
+		httpadapter.WithWebhooks(webhookService),
+	}
+	if traceCfg.Exporter != tracing.ExporterNone {
+		handlerOpts = append(handlerOpts, httpadapter.WithTracing(traceCfg.ServiceName))
+	}
+	handler := httpadapter.NewHandler(sessionService, matchService, moveService, difficultyService, handlerOpts...)
 	router := handler.Router()
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	srvCfg := httpserver.ConfigFromEnv()
+	srv := httpserver.New(srvCfg, router)
+
+	info := buildinfo.Get()
+	slog.Info("listening", "addr", srv.Addr, "version", info.Version, "commit", info.Commit)
+	serveErr := httpserver.Run(ctx, srv, srvCfg.ShutdownTimeout)
+	slog.Info("http server stopped, stopping background workers")
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), srvCfg.ShutdownTimeout)
+	defer cancel()
+	if err := workers.Stop(stopCtx); err != nil {
+		slog.Warn("background workers did not stop cleanly", "error", err)
+	}
+	if err := shutdownTracing(stopCtx); err != nil {
+		slog.Warn("failed to flush traces", "error", err)
 	}
 
-	if err := router.Run(":" + port); err != nil {
-		log.Fatalf("server exited: %v", err)
+	return serveErr
+}
+
+// outboxFromEnv reads OUTBOX_RETENTION, how long delivered events are kept
+// (a Go duration; 0 keeps them forever), and OUTBOX_MAX_ATTEMPTS, the
+// attempts after which a failing event is left as a dead letter.
+func outboxFromEnv(relay *usecase.OutboxRelay) error {
+	if raw := os.Getenv("OUTBOX_RETENTION"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid OUTBOX_RETENTION %q", raw)
+		}
+		relay.DeliveredRetention = d
+	}
+	if raw := os.Getenv("OUTBOX_MAX_ATTEMPTS"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid OUTBOX_MAX_ATTEMPTS %q", raw)
+		}
+		relay.MaxAttempts = n
+	}
+	return nil
+}
+
+// retentionFromEnv reads RETENTION_MOVES_DAYS, the age in days after which the
+// moves of finished matches are archived (unset or 0 keeps them forever), and
+// RETENTION_INTERVAL, how often the retention worker runs.
+func retentionFromEnv(svc *usecase.RetentionService) error {
+	if raw := os.Getenv("RETENTION_MOVES_DAYS"); raw != "" {
+		days, err := strconv.Atoi(raw)
+		if err != nil || days < 0 {
+			return fmt.Errorf("invalid RETENTION_MOVES_DAYS %q", raw)
+		}
+		svc.MoveRetention = time.Duration(days) * 24 * time.Hour
+	}
+	if raw := os.Getenv("RETENTION_INTERVAL"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid RETENTION_INTERVAL %q", raw)
+		}
+		svc.PollInterval = d
 	}
+	return nil
 }
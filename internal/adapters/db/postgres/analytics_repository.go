@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+// playerMatches numbers a player's finished matches per difficulty in the
+// order they were played. It takes the player ID as $1 and keeps only the
+// matches of experiment $2 and arm $3 when they are set.
+const playerMatches = `
+        SELECT m.id, m.session_id, m.difficulty_id, m.started_at, m.outcome,
+               ms.total_moves, ms.errors, ms.avg_time_ms,
+               row_number() OVER (PARTITION BY m.difficulty_id ORDER BY m.started_at, m.id) AS attempt
+        FROM matches m
+        JOIN sessions s ON s.id = m.session_id
+        JOIN match_stats ms ON ms.match_id = m.id
+        WHERE s.player_id = $1 AND NOT m.is_active
+            AND ($2::text IS NULL OR m.meta->'experiments'->>$2 IS NOT NULL)
+            AND ($3::text IS NULL OR m.meta->'experiments'->>$2 = $3)
+`
+
+type AnalyticsRepository struct {
+	pool pgxQuerier
+}
+
+var _ ports.AnalyticsRepo = (*AnalyticsRepository)(nil)
+
+func NewAnalyticsRepository(pool pgxQuerier) *AnalyticsRepository {
+	return &AnalyticsRepository{pool: traced(pool)}
+}
+
+func (r *AnalyticsRepository) PlayerTotals(ctx context.Context, playerID string, filter entity.ProgressFilter) (int, int, error) {
+	ctx = withQuery(ctx, "analytics.player_totals", playerIDAttr(playerID))
+	query := `
+        SELECT COUNT(DISTINCT s.id),
+               COUNT(ms.match_id) FILTER (
+                   WHERE NOT m.is_active
+                       AND ($2::text IS NULL OR m.meta->'experiments'->>$2 IS NOT NULL)
+                       AND ($3::text IS NULL OR m.meta->'experiments'->>$2 = $3)
+               )
+        FROM sessions s
+        LEFT JOIN matches m ON m.session_id = s.id
+        LEFT JOIN match_stats ms ON ms.match_id = m.id
+        WHERE s.player_id = $1
+    `
+	var sessions, matches int
+	if err := r.pool.QueryRow(ctx, query, playerID, filter.Experiment, filter.Arm).Scan(&sessions, &matches); err != nil {
+		return 0, 0, err
+	}
+	return sessions, matches, nil
+}
+
+func (r *AnalyticsRepository) PlayerTrend(ctx context.Context, playerID string, filter entity.ProgressFilter) ([]entity.ProgressPoint, error) {
+	ctx = withQuery(ctx, "analytics.player_trend", playerIDAttr(playerID))
+	query := `
+        WITH pm AS (` + playerMatches + `)
+        SELECT id, session_id, difficulty_id, attempt, started_at, outcome,
+               total_moves, errors, avg_time_ms,
+               (AVG(errors) OVER w)::float8, (AVG(avg_time_ms) OVER w)::float8
+        FROM pm
+        -- moving averages over the last five attempts
+        WINDOW w AS (PARTITION BY difficulty_id ORDER BY attempt ROWS BETWEEN 4 PRECEDING AND CURRENT ROW)
+        ORDER BY started_at, id
+    `
+	rows, err := r.pool.Query(ctx, query, playerID, filter.Experiment, filter.Arm)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []entity.ProgressPoint
+	for rows.Next() {
+		var (
+			p       entity.ProgressPoint
+			outcome sql.NullString
+		)
+		if err := rows.Scan(
+			&p.MatchID,
+			&p.SessionID,
+			&p.DifficultyID,
+			&p.Attempt,
+			&p.StartedAt,
+			&outcome,
+			&p.TotalMoves,
+			&p.Errors,
+			&p.AvgTimeMs,
+			&p.ErrorsMovingAvg,
+			&p.AvgTimeMovingAvg,
+		); err != nil {
+			return nil, err
+		}
+		p.Outcome = stringPtrFromNull(outcome)
+		points = append(points, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+func (r *AnalyticsRepository) PlayerDifficulties(ctx context.Context, playerID string, filter entity.ProgressFilter) ([]entity.DifficultyProgress, error) {
+	ctx = withQuery(ctx, "analytics.player_difficulties", playerIDAttr(playerID))
+	query := `
+        WITH pm AS (` + playerMatches + `),
+        best AS (
+            SELECT DISTINCT ON (difficulty_id) difficulty_id, id, total_moves
+            FROM pm
+            WHERE outcome = 'win'
+            ORDER BY difficulty_id, total_moves, started_at
+        )
+        SELECT d.id, d.name,
+               COUNT(*),
+               COUNT(*) FILTER (WHERE pm.outcome = 'win'),
+               best.total_moves, best.id,
+               regr_slope(pm.errors::float8, pm.attempt::float8),
+               regr_slope(pm.avg_time_ms::float8, pm.attempt::float8)
+        FROM pm
+        JOIN difficulty d ON d.id = pm.difficulty_id
+        LEFT JOIN best ON best.difficulty_id = pm.difficulty_id
+        GROUP BY d.id, d.name, best.total_moves, best.id
+        ORDER BY d.id
+    `
+	rows, err := r.pool.Query(ctx, query, playerID, filter.Experiment, filter.Arm)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var progress []entity.DifficultyProgress
+	for rows.Next() {
+		var (
+			p            entity.DifficultyProgress
+			bestMoves    sql.NullInt64
+			bestMatchID  sql.NullString
+			errorsSlope  sql.NullFloat64
+			avgTimeSlope sql.NullFloat64
+		)
+		if err := rows.Scan(
+			&p.DifficultyID,
+			&p.DifficultyName,
+			&p.Matches,
+			&p.Wins,
+			&bestMoves,
+			&bestMatchID,
+			&errorsSlope,
+			&avgTimeSlope,
+		); err != nil {
+			return nil, err
+		}
+		p.BestSolveMoves = intPtrFromNull(bestMoves)
+		p.BestSolveMatchID = stringPtrFromNull(bestMatchID)
+		p.ErrorsSlope = floatPtrFromNull(errorsSlope)
+		p.AvgTimeSlope = floatPtrFromNull(avgTimeSlope)
+		progress = append(progress, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
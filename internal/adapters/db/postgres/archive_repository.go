@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+type ArchiveRepository struct {
+	pool pgxQuerier
+}
+
+var _ ports.ArchiveRepo = (*ArchiveRepository)(nil)
+
+func NewArchiveRepository(pool pgxQuerier) *ArchiveRepository {
+	return &ArchiveRepository{pool: traced(pool)}
+}
+
+func (r *ArchiveRepository) ListArchivable(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	ctx = withQuery(ctx, "match_archives.list_archivable")
+	query := `
+        SELECT m.id
+        FROM matches m
+        WHERE NOT m.is_active
+            AND COALESCE(m.ended_at, m.started_at) < $1
+            AND EXISTS (SELECT 1 FROM moves mv WHERE mv.match_id = m.id)
+            AND NOT EXISTS (
+                SELECT 1 FROM match_archives a
+                WHERE a.match_id = m.id AND (a.restored_at IS NULL OR a.restored_at >= $1)
+            )
+        ORDER BY COALESCE(m.ended_at, m.started_at), m.id
+        LIMIT $2
+    `
+	rows, err := r.pool.Query(ctx, query, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Archive upserts the archive record and deletes the archived moves in one
+// statement. The moves trigger sees the record and leaves match_stats as it
+// was.
+func (r *ArchiveRepository) Archive(ctx context.Context, archive entity.MatchArchive) (int, error) {
+	ctx = withQuery(ctx, "match_archives.archive", matchIDAttr(archive.MatchID))
+	query := `
+        WITH archive AS (
+            INSERT INTO match_archives (match_id, object_key, moves, last_seq)
+            VALUES ($1, $2, $3, $4)
+            ON CONFLICT (match_id) DO UPDATE
+                SET object_key = EXCLUDED.object_key,
+                    moves = EXCLUDED.moves,
+                    last_seq = EXCLUDED.last_seq,
+                    archived_at = now(),
+                    restored_at = NULL,
+                    clear_boards = FALSE
+            RETURNING match_id
+        ), deleted AS (
+            DELETE FROM moves
+            WHERE match_id = (SELECT match_id FROM archive) AND seq <= $4
+            RETURNING id
+        )
+        SELECT COUNT(*) FROM deleted
+    `
+	var deleted int
+	row := r.pool.QueryRow(ctx, query, archive.MatchID, archive.ObjectKey, archive.Moves, archive.LastSeq)
+	if err := row.Scan(&deleted); err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+func (r *ArchiveRepository) Get(ctx context.Context, matchID string) (entity.MatchArchive, error) {
+	ctx = withQuery(ctx, "match_archives.get", matchIDAttr(matchID))
+	query := `
+        SELECT match_id, object_key, moves, last_seq, archived_at, restored_at
+        FROM match_archives
+        WHERE match_id = $1 AND restored_at IS NULL
+    `
+	var archive entity.MatchArchive
+	if err := scanMatchArchive(r.pool.QueryRow(ctx, query, matchID), &archive); err != nil {
+		return entity.MatchArchive{}, err
+	}
+	return archive, nil
+}
+
+// Restore inserts the moves through jsonb_populate_recordset, whose field
+// names are the JSON names of entity.Move, and marks the archive restored.
+// It returns pgx.ErrNoRows when the match is not archived.
+func (r *ArchiveRepository) Restore(ctx context.Context, matchID string, moves []entity.Move) (int, error) {
+	ctx = withQuery(ctx, "match_archives.restore", matchIDAttr(matchID))
+	payload, err := json.Marshal(moves)
+	if err != nil {
+		return 0, err
+	}
+	query := `
+        WITH archive AS (
+            UPDATE match_archives
+            SET restored_at = now()
+            WHERE match_id = $1 AND restored_at IS NULL
+            RETURNING match_id
+        ), restored AS (
+            INSERT INTO moves (id, match_id, seq, occurred_at, elapsed_ms, from_idx, to_idx,
+                               move_kind, frog_side, is_correct, interruption,
+                               board_before, board_after, branching_factor, buclicidad)
+            SELECT mv.id, mv.match_id, mv.seq, mv.occurred_at, mv.elapsed_ms, mv.from_idx, mv.to_idx,
+                   mv.move_kind, mv.frog_side, mv.is_correct, mv.interruption,
+                   mv.board_before, mv.board_after, mv.branching_factor, mv.buclicidad
+            FROM jsonb_populate_recordset(NULL::moves, $2::jsonb) AS mv
+            WHERE mv.match_id IN (SELECT match_id FROM archive)
+            ON CONFLICT (match_id, seq) DO NOTHING
+            RETURNING id
+        )
+        SELECT (SELECT COUNT(*) FROM restored)
+        FROM archive
+    `
+	var restored int
+	if err := r.pool.QueryRow(ctx, query, matchID, payload).Scan(&restored); err != nil {
+		return 0, err
+	}
+	return restored, nil
+}
+
+func (r *ArchiveRepository) ListOrphaned(ctx context.Context, limit int) ([]entity.MatchArchive, error) {
+	ctx = withQuery(ctx, "match_archives.list_orphaned")
+	query := `
+        SELECT a.match_id, a.object_key, a.moves, a.last_seq, a.archived_at, a.restored_at
+        FROM match_archives a
+        WHERE NOT EXISTS (SELECT 1 FROM matches m WHERE m.id = a.match_id)
+        ORDER BY a.archived_at
+        LIMIT $1
+    `
+	return r.list(ctx, query, limit)
+}
+
+func (r *ArchiveRepository) ListBoardsToClear(ctx context.Context, limit int) ([]entity.MatchArchive, error) {
+	ctx = withQuery(ctx, "match_archives.list_boards_to_clear")
+	query := `
+        SELECT match_id, object_key, moves, last_seq, archived_at, restored_at
+        FROM match_archives
+        WHERE clear_boards
+        ORDER BY archived_at
+        LIMIT $1
+    `
+	return r.list(ctx, query, limit)
+}
+
+func (r *ArchiveRepository) list(ctx context.Context, query string, args ...any) ([]entity.MatchArchive, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var archives []entity.MatchArchive
+	for rows.Next() {
+		var archive entity.MatchArchive
+		if err := scanMatchArchive(rows, &archive); err != nil {
+			return nil, err
+		}
+		archives = append(archives, archive)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return archives, nil
+}
+
+func (r *ArchiveRepository) Delete(ctx context.Context, matchID string) error {
+	ctx = withQuery(ctx, "match_archives.delete", matchIDAttr(matchID))
+	query := `
+        DELETE FROM match_archives
+        WHERE match_id = $1
+    `
+	return exec(ctx, r.pool, query, matchID)
+}
+
+func (r *ArchiveRepository) MarkBoardsCleared(ctx context.Context, matchID string) error {
+	ctx = withQuery(ctx, "match_archives.mark_boards_cleared", matchIDAttr(matchID))
+	query := `
+        UPDATE match_archives SET clear_boards = FALSE
+        WHERE match_id = $1
+    `
+	return exec(ctx, r.pool, query, matchID)
+}
+
+func scanMatchArchive(row pgx.Row, archive *entity.MatchArchive) error {
+	var restoredAt sql.NullTime
+	if err := row.Scan(
+		&archive.MatchID,
+		&archive.ObjectKey,
+		&archive.Moves,
+		&archive.LastSeq,
+		&archive.ArchivedAt,
+		&restoredAt,
+	); err != nil {
+		return err
+	}
+	archive.RestoredAt = timePtrFromNull(restoredAt)
+	return nil
+}
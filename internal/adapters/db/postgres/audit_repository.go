@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+	"github.com/org/ranas-bdi-backend/internal/platform/logging"
+)
+
+type AuditRepository struct {
+	pool pgxQuerier
+}
+
+var _ ports.AuditRepo = (*AuditRepository)(nil)
+
+func NewAuditRepository(pool pgxQuerier) *AuditRepository {
+	return &AuditRepository{pool: traced(pool)}
+}
+
+func (r *AuditRepository) Record(ctx context.Context, entry entity.AuditEntry) (entity.AuditEntry, error) {
+	ctx = withQuery(ctx, "audit_log.record")
+	query := `
+        INSERT INTO audit_log (actor, action, entity_type, entity_id, changes, request_id)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, actor, action, entity_type, entity_id, changes, request_id, created_at
+    `
+	var recorded entity.AuditEntry
+	row := r.pool.QueryRow(ctx, query,
+		entry.Actor,
+		entry.Action,
+		entry.EntityType,
+		nullableString(entry.EntityID),
+		nullableBytes(entry.Changes),
+		nullableString(entry.RequestID),
+	)
+	if err := scanAuditEntry(row, &recorded); err != nil {
+		return entity.AuditEntry{}, err
+	}
+	return recorded, nil
+}
+
+func (r *AuditRepository) List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error) {
+	ctx = withQuery(ctx, "audit_log.list")
+	query := `
+        SELECT id, actor, action, entity_type, entity_id, changes, request_id, created_at
+        FROM audit_log
+        WHERE ($1::text IS NULL OR actor = $1)
+            AND ($2::text IS NULL OR action = $2)
+            AND ($3::text IS NULL OR entity_type = $3)
+            AND ($4::text IS NULL OR entity_id = $4)
+            AND ($5::text IS NULL OR request_id = $5)
+            AND ($6::timestamptz IS NULL OR created_at >= $6)
+            AND ($7::timestamptz IS NULL OR created_at < $7)
+        ORDER BY created_at DESC, id DESC
+        LIMIT $8
+    `
+	rows, err := r.pool.Query(ctx, query,
+		nullableString(filter.Actor),
+		nullableString(filter.Action),
+		nullableString(filter.EntityType),
+		nullableString(filter.EntityID),
+		nullableString(filter.RequestID),
+		nullableTime(filter.From),
+		nullableTime(filter.To),
+		filter.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []entity.AuditEntry
+	for rows.Next() {
+		var entry entity.AuditEntry
+		if err := scanAuditEntry(rows, &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// auditContext returns the actor and request ID the repository hooks record
+// for a change made with ctx. Work outside a request is done by the system.
+func auditContext(ctx context.Context) (actor string, requestID any) {
+	actor = logging.Actor(ctx)
+	if actor == "" {
+		actor = entity.AuditActorSystem
+	}
+	if id := logging.RequestID(ctx); id != "" {
+		requestID = id
+	}
+	return actor, requestID
+}
+
+func scanAuditEntry(row pgx.Row, entry *entity.AuditEntry) error {
+	var (
+		entityID  sql.NullString
+		changes   []byte
+		requestID sql.NullString
+	)
+	if err := row.Scan(
+		&entry.ID,
+		&entry.Actor,
+		&entry.Action,
+		&entry.EntityType,
+		&entityID,
+		&changes,
+		&requestID,
+		&entry.CreatedAt,
+	); err != nil {
+		return err
+	}
+	entry.EntityID = stringPtrFromNull(entityID)
+	entry.RequestID = stringPtrFromNull(requestID)
+	if len(changes) > 0 {
+		entry.Changes = make([]byte, len(changes))
+		copy(entry.Changes, changes)
+	} else {
+		entry.Changes = nil
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+type CohortRepository struct {
+	pool pgxQuerier
+}
+
+var _ ports.CohortRepo = (*CohortRepository)(nil)
+
+func NewCohortRepository(pool pgxQuerier) *CohortRepository {
+	return &CohortRepository{pool: traced(pool)}
+}
+
+func (r *CohortRepository) SetPlayerCohorts(ctx context.Context, playerID string, cohorts []string) error {
+	ctx = withQuery(ctx, "player_cohorts.set", playerIDAttr(playerID))
+	query := `
+        WITH removed AS (
+            DELETE FROM player_cohorts
+            WHERE player_id = $1 AND cohort <> ALL($2::text[])
+        )
+        INSERT INTO player_cohorts (player_id, cohort)
+        SELECT $1::uuid, unnest($2::text[])
+        ON CONFLICT (player_id, cohort) DO NOTHING
+    `
+	return exec(ctx, r.pool, query, playerID, cohorts)
+}
+
+func (r *CohortRepository) PlayerCohorts(ctx context.Context, playerID string) ([]string, error) {
+	ctx = withQuery(ctx, "player_cohorts.get", playerIDAttr(playerID))
+	query := `
+        SELECT cohort
+        FROM player_cohorts
+        WHERE player_id = $1
+        ORDER BY cohort
+    `
+	rows, err := r.pool.Query(ctx, query, playerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cohorts []string
+	for rows.Next() {
+		var cohort string
+		if err := rows.Scan(&cohort); err != nil {
+			return nil, err
+		}
+		cohorts = append(cohorts, cohort)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return cohorts, nil
+}
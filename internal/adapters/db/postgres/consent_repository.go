@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+type ConsentRepository struct {
+	pool pgxQuerier
+}
+
+var _ ports.ConsentRepo = (*ConsentRepository)(nil)
+
+func NewConsentRepository(pool pgxQuerier) *ConsentRepository {
+	return &ConsentRepository{pool: traced(pool)}
+}
+
+func (r *ConsentRepository) Create(ctx context.Context, consent entity.Consent) (entity.Consent, error) {
+	ctx = withQuery(ctx, "player_consents.create", playerIDAttr(consent.PlayerID))
+	query := `
+        INSERT INTO player_consents (player_id, form_version, research_consent, guardian_consent, guardian_name)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, player_id, form_version, research_consent, guardian_consent, guardian_name,
+                  given_at, withdrawn_at, withdrawal_action
+    `
+	var created entity.Consent
+	row := r.pool.QueryRow(ctx, query,
+		consent.PlayerID,
+		consent.FormVersion,
+		consent.ResearchConsent,
+		consent.GuardianConsent,
+		nullableString(consent.GuardianName),
+	)
+	if err := scanConsent(row, &created); err != nil {
+		return entity.Consent{}, err
+	}
+	return created, nil
+}
+
+func (r *ConsentRepository) ListByPlayer(ctx context.Context, playerID string) ([]entity.Consent, error) {
+	ctx = withQuery(ctx, "player_consents.list_by_player", playerIDAttr(playerID))
+	query := `
+        SELECT id, player_id, form_version, research_consent, guardian_consent, guardian_name,
+               given_at, withdrawn_at, withdrawal_action
+        FROM player_consents
+        WHERE player_id = $1
+        ORDER BY given_at DESC, id DESC
+    `
+	rows, err := r.pool.Query(ctx, query, playerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var consents []entity.Consent
+	for rows.Next() {
+		var consent entity.Consent
+		if err := scanConsent(rows, &consent); err != nil {
+			return nil, err
+		}
+		consents = append(consents, consent)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return consents, nil
+}
+
+// Withdraw runs as a single statement so the withdrawal is never recorded
+// without its effect on the data and its audit record. Anonymizing also
+// clears the recorded boards, which were only kept under research consent.
+func (r *ConsentRepository) Withdraw(ctx context.Context, audit entity.PrivacyAudit) (entity.Consent, entity.PrivacyAudit, error) {
+	ctx = withQuery(ctx, "player_consents.withdraw", playerIDAttr(audit.PlayerID))
+	operation := deletePlayerData
+	if audit.Action == entity.PrivacyAnonymize {
+		operation = anonymizePlayerData + "," + clearPlayerBoards
+	}
+	query := `
+        WITH withdrawn AS (
+            UPDATE player_consents
+            SET withdrawn_at = now(), withdrawal_action = $2::text
+            WHERE id = (
+                SELECT id FROM player_consents
+                WHERE player_id = $1::uuid
+                ORDER BY given_at DESC, id DESC
+                LIMIT 1
+            ) AND withdrawn_at IS NULL
+            RETURNING id, player_id, form_version, research_consent, guardian_consent, guardian_name,
+                      given_at, withdrawn_at, withdrawal_action
+        ), subject AS (
+            SELECT player_id FROM withdrawn
+        ),` + operation + `,` + recordPrivacyAudit + `
+        SELECT w.id, w.player_id, w.form_version, w.research_consent, w.guardian_consent, w.guardian_name,
+               w.given_at, w.withdrawn_at, w.withdrawal_action,
+               a.id, a.player_id, a.action, a.reason, a.actor, a.request_id, a.sessions_affected, a.created_at
+        FROM withdrawn w, audit a
+    `
+	var (
+		consent   entity.Consent
+		recorded  entity.PrivacyAudit
+		requestID sql.NullString
+	)
+	row := r.pool.QueryRow(ctx, query, privacyAuditArgs(audit)...)
+	err := scanConsent(row, &consent,
+		&recorded.ID,
+		&recorded.PlayerID,
+		&recorded.Action,
+		&recorded.Reason,
+		&recorded.Actor,
+		&requestID,
+		&recorded.SessionsAffected,
+		&recorded.CreatedAt,
+	)
+	if err != nil {
+		return entity.Consent{}, entity.PrivacyAudit{}, err
+	}
+	recorded.RequestID = stringPtrFromNull(requestID)
+	return consent, recorded, nil
+}
+
+// scanConsent scans the consent columns followed by the extra destinations.
+func scanConsent(row pgx.Row, consent *entity.Consent, extra ...any) error {
+	var (
+		guardianName sql.NullString
+		withdrawnAt  sql.NullTime
+		action       sql.NullString
+	)
+	dest := append([]any{
+		&consent.ID,
+		&consent.PlayerID,
+		&consent.FormVersion,
+		&consent.ResearchConsent,
+		&consent.GuardianConsent,
+		&guardianName,
+		&consent.GivenAt,
+		&withdrawnAt,
+		&action,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+	consent.GuardianName = stringPtrFromNull(guardianName)
+	consent.WithdrawnAt = timePtrFromNull(withdrawnAt)
+	consent.WithdrawalAction = stringPtrFromNull(action)
+	return nil
+}
@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+type CurriculumRepository struct {
+	pool pgxQuerier
+}
+
+var _ ports.CurriculumRepo = (*CurriculumRepository)(nil)
+
+func NewCurriculumRepository(pool pgxQuerier) *CurriculumRepository {
+	return &CurriculumRepository{pool: traced(pool)}
+}
+
+// Create stores a curriculum and its levels, numbered from 1 in the given
+// order.
+func (r *CurriculumRepository) Create(ctx context.Context, curriculum entity.Curriculum) (entity.Curriculum, error) {
+	ctx = withQuery(ctx, "curricula.create")
+	difficultyIDs := make([]int32, len(curriculum.Levels))
+	for i, level := range curriculum.Levels {
+		difficultyIDs[i] = int32(level.DifficultyID)
+	}
+	var created entity.Curriculum
+	query := `
+        WITH created AS (
+            INSERT INTO curricula (name, advance_after, step_back_after)
+            VALUES ($1, $2, $3)
+            RETURNING id, name, advance_after, step_back_after, is_default, created_at
+        ), levels AS (
+            INSERT INTO curriculum_levels (curriculum_id, level_n, difficulty_id)
+            SELECT created.id, l.level_n, l.difficulty_id
+            FROM created, unnest($4::int[]) WITH ORDINALITY AS l(difficulty_id, level_n)
+        )
+        SELECT id, name, advance_after, step_back_after, is_default, created_at
+        FROM created
+    `
+	row := r.pool.QueryRow(ctx, query,
+		curriculum.Name,
+		curriculum.AdvanceAfter,
+		nullableInt(curriculum.StepBackAfter),
+		difficultyIDs,
+	)
+	if err := scanCurriculum(row, &created); err != nil {
+		return entity.Curriculum{}, err
+	}
+	created.Levels = make([]entity.CurriculumLevel, len(curriculum.Levels))
+	for i, level := range curriculum.Levels {
+		created.Levels[i] = entity.CurriculumLevel{LevelN: i + 1, DifficultyID: level.DifficultyID}
+	}
+	return created, nil
+}
+
+func (r *CurriculumRepository) Get(ctx context.Context, id int) (entity.Curriculum, error) {
+	ctx = withQuery(ctx, "curricula.get")
+	query := `
+        SELECT id, name, advance_after, step_back_after, is_default, created_at
+        FROM curricula
+        WHERE id = $1
+    `
+	return r.get(ctx, query, id)
+}
+
+func (r *CurriculumRepository) GetDefault(ctx context.Context) (entity.Curriculum, error) {
+	ctx = withQuery(ctx, "curricula.get_default")
+	query := `
+        SELECT id, name, advance_after, step_back_after, is_default, created_at
+        FROM curricula
+        WHERE is_default
+    `
+	return r.get(ctx, query)
+}
+
+func (r *CurriculumRepository) List(ctx context.Context) ([]entity.Curriculum, error) {
+	ctx = withQuery(ctx, "curricula.list")
+	query := `
+        SELECT id, name, advance_after, step_back_after, is_default, created_at
+        FROM curricula
+        ORDER BY id
+    `
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var curricula []entity.Curriculum
+	for rows.Next() {
+		var c entity.Curriculum
+		if err := scanCurriculum(rows, &c); err != nil {
+			return nil, err
+		}
+		curricula = append(curricula, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for i := range curricula {
+		if curricula[i].Levels, err = r.levels(ctx, curricula[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return curricula, nil
+}
+
+func (r *CurriculumRepository) get(ctx context.Context, query string, args ...any) (entity.Curriculum, error) {
+	var curriculum entity.Curriculum
+	row := r.pool.QueryRow(ctx, query, args...)
+	if err := scanCurriculum(row, &curriculum); err != nil {
+		return entity.Curriculum{}, err
+	}
+	levels, err := r.levels(ctx, curriculum.ID)
+	if err != nil {
+		return entity.Curriculum{}, err
+	}
+	curriculum.Levels = levels
+	return curriculum, nil
+}
+
+func (r *CurriculumRepository) levels(ctx context.Context, curriculumID int) ([]entity.CurriculumLevel, error) {
+	ctx = withQuery(ctx, "curriculum_levels.list")
+	query := `
+        SELECT level_n, difficulty_id
+        FROM curriculum_levels
+        WHERE curriculum_id = $1
+        ORDER BY level_n
+    `
+	rows, err := r.pool.Query(ctx, query, curriculumID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	levels := []entity.CurriculumLevel{}
+	for rows.Next() {
+		var level entity.CurriculumLevel
+		if err := rows.Scan(&level.LevelN, &level.DifficultyID); err != nil {
+			return nil, err
+		}
+		levels = append(levels, level)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+func scanCurriculum(row pgx.Row, curriculum *entity.Curriculum) error {
+	var stepBack sql.NullInt64
+	if err := row.Scan(
+		&curriculum.ID,
+		&curriculum.Name,
+		&curriculum.AdvanceAfter,
+		&stepBack,
+		&curriculum.IsDefault,
+		&curriculum.CreatedAt,
+	); err != nil {
+		return err
+	}
+	curriculum.StepBackAfter = intPtrFromNull(stepBack)
+	return nil
+}
@@ -2,6 +2,9 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
 
 	"github.com/org/ranas-bdi-backend/internal/domain/entity"
 	"github.com/org/ranas-bdi-backend/internal/domain/ports"
@@ -14,30 +17,131 @@ type DifficultyRepository struct {
 var _ ports.DifficultyRepo = (*DifficultyRepository)(nil)
 
 func NewDifficultyRepository(pool pgxQuerier) *DifficultyRepository {
-	return &DifficultyRepository{pool: pool}
+	return &DifficultyRepository{pool: traced(pool)}
 }
 
 func (r *DifficultyRepository) GetByID(ctx context.Context, id int) (entity.Difficulty, error) {
+	ctx = withQuery(ctx, "difficulty.get_by_id")
 	var difficulty entity.Difficulty
 	query := `
-        SELECT id, name, number_of_blocks
+        SELECT id, name, number_of_blocks, time_limit_ms, layout, version, retired_at
         FROM difficulty
         WHERE id = $1
     `
 	row := r.pool.QueryRow(ctx, query, id)
-	if err := row.Scan(&difficulty.ID, &difficulty.Name, &difficulty.NumberOfBlocks); err != nil {
+	if err := scanDifficulty(row, &difficulty); err != nil {
 		return entity.Difficulty{}, err
 	}
 	return difficulty, nil
 }
 
 func (r *DifficultyRepository) GetAll(ctx context.Context) ([]entity.Difficulty, error) {
+	ctx = withQuery(ctx, "difficulty.get_all")
 	query := `
-        SELECT id, name, number_of_blocks
+        SELECT id, name, number_of_blocks, time_limit_ms, layout, version, retired_at
         FROM difficulty
         ORDER BY id
     `
-	rows, err := r.pool.Query(ctx, query)
+	return r.list(ctx, query)
+}
+
+func (r *DifficultyRepository) GetVersion(ctx context.Context, id, version int) (entity.Difficulty, error) {
+	ctx = withQuery(ctx, "difficulty.get_version")
+	var difficulty entity.Difficulty
+	query := `
+        SELECT d.id, d.name, v.number_of_blocks, v.time_limit_ms, v.layout, v.version, d.retired_at
+        FROM difficulty_versions v
+        JOIN difficulty d ON d.id = v.difficulty_id
+        WHERE v.difficulty_id = $1 AND v.version = $2
+    `
+	row := r.pool.QueryRow(ctx, query, id, version)
+	if err := scanDifficulty(row, &difficulty); err != nil {
+		return entity.Difficulty{}, err
+	}
+	return difficulty, nil
+}
+
+func (r *DifficultyRepository) ListVersions(ctx context.Context, id int) ([]entity.Difficulty, error) {
+	ctx = withQuery(ctx, "difficulty.list_versions")
+	query := `
+        SELECT d.id, d.name, v.number_of_blocks, v.time_limit_ms, v.layout, v.version, d.retired_at
+        FROM difficulty_versions v
+        JOIN difficulty d ON d.id = v.difficulty_id
+        WHERE v.difficulty_id = $1
+        ORDER BY v.version
+    `
+	return r.list(ctx, query, id)
+}
+
+func (r *DifficultyRepository) Create(ctx context.Context, difficulty entity.Difficulty) (entity.Difficulty, error) {
+	ctx = withQuery(ctx, "difficulty.create")
+	var created entity.Difficulty
+	query := `
+        INSERT INTO difficulty (name, number_of_blocks, time_limit_ms, layout)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, name, number_of_blocks, time_limit_ms, layout, version, retired_at
+    `
+	row := r.pool.QueryRow(ctx, query,
+		difficulty.Name,
+		difficulty.NumberOfBlocks,
+		nullableInt(difficulty.TimeLimitMs),
+		nullableBytes(difficulty.Layout),
+	)
+	if err := scanDifficulty(row, &created); err != nil {
+		return entity.Difficulty{}, err
+	}
+	return created, nil
+}
+
+func (r *DifficultyRepository) Update(ctx context.Context, difficulty entity.Difficulty) (entity.Difficulty, error) {
+	ctx = withQuery(ctx, "difficulty.update")
+	var updated entity.Difficulty
+	// Renaming keeps the version; changing the board or the time limit starts
+	// a new one, which the snapshot trigger copies to difficulty_versions.
+	query := `
+        UPDATE difficulty
+        SET name = $2,
+            number_of_blocks = $3,
+            time_limit_ms = $4,
+            layout = $5,
+            version = version + CASE
+                WHEN (number_of_blocks, time_limit_ms, layout) IS NOT DISTINCT FROM ($3, $4, $5::jsonb) THEN 0
+                ELSE 1
+            END
+        WHERE id = $1 AND retired_at IS NULL
+        RETURNING id, name, number_of_blocks, time_limit_ms, layout, version, retired_at
+    `
+	row := r.pool.QueryRow(ctx, query,
+		difficulty.ID,
+		difficulty.Name,
+		difficulty.NumberOfBlocks,
+		nullableInt(difficulty.TimeLimitMs),
+		nullableBytes(difficulty.Layout),
+	)
+	if err := scanDifficulty(row, &updated); err != nil {
+		return entity.Difficulty{}, err
+	}
+	return updated, nil
+}
+
+func (r *DifficultyRepository) Retire(ctx context.Context, id int) (entity.Difficulty, error) {
+	ctx = withQuery(ctx, "difficulty.retire")
+	var retired entity.Difficulty
+	query := `
+        UPDATE difficulty
+        SET retired_at = COALESCE(retired_at, now())
+        WHERE id = $1
+        RETURNING id, name, number_of_blocks, time_limit_ms, layout, version, retired_at
+    `
+	row := r.pool.QueryRow(ctx, query, id)
+	if err := scanDifficulty(row, &retired); err != nil {
+		return entity.Difficulty{}, err
+	}
+	return retired, nil
+}
+
+func (r *DifficultyRepository) list(ctx context.Context, query string, args ...any) ([]entity.Difficulty, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +150,7 @@ func (r *DifficultyRepository) GetAll(ctx context.Context) ([]entity.Difficulty,
 	var difficulties []entity.Difficulty
 	for rows.Next() {
 		var d entity.Difficulty
-		if err := rows.Scan(&d.ID, &d.Name, &d.NumberOfBlocks); err != nil {
+		if err := scanDifficulty(rows, &d); err != nil {
 			return nil, err
 		}
 		difficulties = append(difficulties, d)
@@ -56,3 +160,29 @@ func (r *DifficultyRepository) GetAll(ctx context.Context) ([]entity.Difficulty,
 	}
 	return difficulties, nil
 }
+
+func scanDifficulty(row pgx.Row, difficulty *entity.Difficulty) error {
+	var (
+		timeLimit sql.NullInt64
+		layout    []byte
+		retiredAt sql.NullTime
+	)
+	if err := row.Scan(
+		&difficulty.ID,
+		&difficulty.Name,
+		&difficulty.NumberOfBlocks,
+		&timeLimit,
+		&layout,
+		&difficulty.Version,
+		&retiredAt,
+	); err != nil {
+		return err
+	}
+	difficulty.TimeLimitMs = intPtrFromNull(timeLimit)
+	if len(layout) > 0 {
+		difficulty.Layout = make([]byte, len(layout))
+		copy(difficulty.Layout, layout)
+	}
+	difficulty.RetiredAt = timePtrFromNull(retiredAt)
+	return nil
+}
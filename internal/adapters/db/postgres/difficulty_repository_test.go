@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
 
@@ -34,6 +35,7 @@ func TestDifficultyRepositoryGetByID(t *testing.T) {
 		*(dest[0].(*int)) = 2
 		*(dest[1].(*string)) = "medium"
 		*(dest[2].(*int)) = 9
+		*(dest[3].(*sql.NullInt64)) = sql.NullInt64{Int64: 90000, Valid: true}
 		return nil
 	}}
 	repo := NewDifficultyRepository(stubQuerier{row: row})
@@ -43,4 +45,5 @@ func TestDifficultyRepositoryGetByID(t *testing.T) {
 	require.Equal(t, 2, diff.ID)
 	require.Equal(t, "medium", diff.Name)
 	require.Equal(t, 9, diff.NumberOfBlocks)
+	require.Equal(t, 90000, *diff.TimeLimitMs)
 }
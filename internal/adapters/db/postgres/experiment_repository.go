@@ -0,0 +1,229 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+type ExperimentRepository struct {
+	pool pgxQuerier
+}
+
+var _ ports.ExperimentRepo = (*ExperimentRepository)(nil)
+
+func NewExperimentRepository(pool pgxQuerier) *ExperimentRepository {
+	return &ExperimentRepository{pool: traced(pool)}
+}
+
+// Create stores an experiment and its arms, kept in the given order.
+func (r *ExperimentRepository) Create(ctx context.Context, experiment entity.Experiment) (entity.Experiment, error) {
+	ctx = withQuery(ctx, "experiments.create")
+	names := make([]string, len(experiment.Arms))
+	weights := make([]int32, len(experiment.Arms))
+	configs := make([]*string, len(experiment.Arms))
+	for i, arm := range experiment.Arms {
+		names[i] = arm.Name
+		weights[i] = int32(arm.Weight)
+		if len(arm.Config) > 0 {
+			config := string(arm.Config)
+			configs[i] = &config
+		}
+	}
+	var created entity.Experiment
+	query := `
+        WITH created AS (
+            INSERT INTO experiments (key, description, unit)
+            VALUES ($1, $2, $3)
+            RETURNING id, key, description, unit, is_active, created_at
+        ), arms AS (
+            INSERT INTO experiment_arms (experiment_id, name, weight, config, position)
+            SELECT created.id, a.name, a.weight, a.config::jsonb, a.position
+            FROM created, unnest($4::text[], $5::int[], $6::text[]) WITH ORDINALITY AS a(name, weight, config, position)
+        )
+        SELECT id, key, description, unit, is_active, created_at
+        FROM created
+    `
+	row := r.pool.QueryRow(ctx, query,
+		experiment.Key,
+		nullableString(experiment.Description),
+		experiment.Unit,
+		names,
+		weights,
+		configs,
+	)
+	if err := scanExperiment(row, &created); err != nil {
+		return entity.Experiment{}, err
+	}
+	created.Arms = experiment.Arms
+	return created, nil
+}
+
+func (r *ExperimentRepository) List(ctx context.Context) ([]entity.Experiment, error) {
+	ctx = withQuery(ctx, "experiments.list")
+	query := `
+        SELECT id, key, description, unit, is_active, created_at
+        FROM experiments
+        ORDER BY id
+    `
+	return r.list(ctx, query)
+}
+
+func (r *ExperimentRepository) ListActive(ctx context.Context) ([]entity.Experiment, error) {
+	ctx = withQuery(ctx, "experiments.list_active")
+	query := `
+        SELECT id, key, description, unit, is_active, created_at
+        FROM experiments
+        WHERE is_active
+        ORDER BY id
+    `
+	return r.list(ctx, query)
+}
+
+func (r *ExperimentRepository) Deactivate(ctx context.Context, id int) (entity.Experiment, error) {
+	ctx = withQuery(ctx, "experiments.deactivate")
+	query := `
+        UPDATE experiments
+        SET is_active = FALSE
+        WHERE id = $1
+        RETURNING id, key, description, unit, is_active, created_at
+    `
+	var experiment entity.Experiment
+	if err := scanExperiment(r.pool.QueryRow(ctx, query, id), &experiment); err != nil {
+		return entity.Experiment{}, err
+	}
+	arms, err := r.arms(ctx, experiment.ID)
+	if err != nil {
+		return entity.Experiment{}, err
+	}
+	experiment.Arms = arms
+	return experiment, nil
+}
+
+// Assign inserts the assignment or, when the unit already has one, returns
+// the stored assignment instead, so the first arm a unit gets is kept.
+func (r *ExperimentRepository) Assign(ctx context.Context, assignment entity.ExperimentAssignment) (entity.ExperimentAssignment, error) {
+	ctx = withQuery(ctx, "experiment_assignments.assign")
+	query := `
+        WITH inserted AS (
+            INSERT INTO experiment_assignments (experiment_id, unit_id, arm)
+            VALUES ($1, $2, $3)
+            ON CONFLICT (experiment_id, unit_id) DO NOTHING
+            RETURNING experiment_id, unit_id, arm, assigned_at
+        ), assigned AS (
+            SELECT experiment_id, unit_id, arm, assigned_at FROM inserted
+            UNION ALL
+            SELECT experiment_id, unit_id, arm, assigned_at
+            FROM experiment_assignments
+            WHERE experiment_id = $1 AND unit_id = $2
+        )
+        SELECT a.experiment_id, e.key, a.unit_id, a.arm, ea.config, a.assigned_at
+        FROM assigned a
+        JOIN experiments e ON e.id = a.experiment_id
+        JOIN experiment_arms ea ON ea.experiment_id = a.experiment_id AND ea.name = a.arm
+        LIMIT 1
+    `
+	var (
+		assigned entity.ExperimentAssignment
+		config   []byte
+	)
+	row := r.pool.QueryRow(ctx, query, assignment.ExperimentID, assignment.UnitID, assignment.Arm)
+	if err := row.Scan(
+		&assigned.ExperimentID,
+		&assigned.Experiment,
+		&assigned.UnitID,
+		&assigned.Arm,
+		&config,
+		&assigned.AssignedAt,
+	); err != nil {
+		return entity.ExperimentAssignment{}, err
+	}
+	if len(config) > 0 {
+		assigned.Config = make([]byte, len(config))
+		copy(assigned.Config, config)
+	}
+	return assigned, nil
+}
+
+func (r *ExperimentRepository) list(ctx context.Context, query string) ([]entity.Experiment, error) {
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var experiments []entity.Experiment
+	for rows.Next() {
+		var e entity.Experiment
+		if err := scanExperiment(rows, &e); err != nil {
+			return nil, err
+		}
+		experiments = append(experiments, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for i := range experiments {
+		if experiments[i].Arms, err = r.arms(ctx, experiments[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return experiments, nil
+}
+
+func (r *ExperimentRepository) arms(ctx context.Context, experimentID int) ([]entity.ExperimentArm, error) {
+	ctx = withQuery(ctx, "experiment_arms.list")
+	query := `
+        SELECT name, weight, config
+        FROM experiment_arms
+        WHERE experiment_id = $1
+        ORDER BY position
+    `
+	rows, err := r.pool.Query(ctx, query, experimentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	arms := []entity.ExperimentArm{}
+	for rows.Next() {
+		var (
+			arm    entity.ExperimentArm
+			config []byte
+		)
+		if err := rows.Scan(&arm.Name, &arm.Weight, &config); err != nil {
+			return nil, err
+		}
+		if len(config) > 0 {
+			arm.Config = make([]byte, len(config))
+			copy(arm.Config, config)
+		}
+		arms = append(arms, arm)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return arms, nil
+}
+
+func scanExperiment(row pgx.Row, experiment *entity.Experiment) error {
+	var description sql.NullString
+	if err := row.Scan(
+		&experiment.ID,
+		&experiment.Key,
+		&description,
+		&experiment.Unit,
+		&experiment.IsActive,
+		&experiment.CreatedAt,
+	); err != nil {
+		return err
+	}
+	experiment.Description = stringPtrFromNull(description)
+	return nil
+}
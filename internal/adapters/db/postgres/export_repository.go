@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+// exportMatches restricts an export to the matches selected by the filter,
+// passed as $1..$6 by exportArgs. It expects matches as m and sessions as s.
+const exportMatches = `
+            ($1::timestamptz IS NULL OR m.started_at >= $1)
+            AND ($2::timestamptz IS NULL OR m.started_at < $2)
+            AND ($3::uuid IS NULL OR s.player_id = $3)
+            AND ($4::int IS NULL OR m.difficulty_id = $4)
+            AND ($5::text IS NULL OR m.meta->'experiments'->>$5 IS NOT NULL)
+            AND ($6::text IS NULL OR m.meta->'experiments'->>$5 = $6)
+`
+
+type ExportRepository struct {
+	pool pgxQuerier
+}
+
+var _ ports.ExportRepo = (*ExportRepository)(nil)
+
+func NewExportRepository(pool pgxQuerier) *ExportRepository {
+	return &ExportRepository{pool: traced(pool)}
+}
+
+func (r *ExportRepository) EachSession(ctx context.Context, filter entity.ExportFilter, fn func(entity.Session) error) error {
+	ctx = withQuery(ctx, "export.sessions")
+	query := `
+        SELECT s.id, s.player_id, s.device, s.curriculum_id, s.is_finished, s.started_at, s.ended_at
+        FROM sessions s
+        WHERE ($1::timestamptz IS NULL OR s.started_at >= $1)
+            AND ($2::timestamptz IS NULL OR s.started_at < $2)
+            AND ($3::uuid IS NULL OR s.player_id = $3)
+            AND ($4::int IS NULL OR EXISTS (
+                SELECT 1 FROM matches m WHERE m.session_id = s.id AND m.difficulty_id = $4
+            ))
+            AND ($5::text IS NULL OR EXISTS (
+                SELECT 1 FROM matches m
+                WHERE m.session_id = s.id
+                    AND m.meta->'experiments'->>$5 IS NOT NULL
+                    AND ($6::text IS NULL OR m.meta->'experiments'->>$5 = $6)
+            ))
+        ORDER BY s.started_at, s.id
+    `
+	return each(ctx, r.pool, query, exportArgs(filter), scanSession, fn)
+}
+
+func (r *ExportRepository) EachMatch(ctx context.Context, filter entity.ExportFilter, fn func(entity.Match) error) error {
+	ctx = withQuery(ctx, "export.matches")
+	query := `
+        SELECT m.id, m.session_id, m.difficulty_id, m.difficulty_version, m.level_n, m.is_active,
+               m.started_at, m.ended_at, m.outcome, m.meta
+        FROM matches m
+        JOIN sessions s ON s.id = m.session_id
+        WHERE ` + exportMatches + `
+        ORDER BY m.started_at, m.id
+    `
+	return each(ctx, r.pool, query, exportArgs(filter), scanMatch, fn)
+}
+
+func (r *ExportRepository) EachMove(ctx context.Context, filter entity.ExportFilter, fn func(entity.Move) error) error {
+	ctx = withQuery(ctx, "export.moves")
+	query := `
+        SELECT mv.id, mv.match_id, mv.seq, mv.occurred_at, mv.elapsed_ms, mv.from_idx, mv.to_idx,
+               mv.move_kind, mv.frog_side, mv.is_correct, mv.interruption,
+               mv.board_before, mv.board_after, mv.branching_factor, mv.buclicidad
+        FROM moves mv
+        JOIN matches m ON m.id = mv.match_id
+        JOIN sessions s ON s.id = m.session_id
+        WHERE ` + exportMatches + `
+        ORDER BY m.started_at, m.id, mv.seq
+    `
+	return each(ctx, r.pool, query, exportArgs(filter), scanMove, fn)
+}
+
+func (r *ExportRepository) EachMatchKPI(ctx context.Context, filter entity.ExportFilter, fn func(entity.MatchKPI) error) error {
+	ctx = withQuery(ctx, "export.match_stats")
+	query := `
+        SELECT ms.match_id, ms.total_moves, ms.errors, ms.avg_time_ms,
+               ms.buclicidad_avg, ms.branch_factor_avg, ms.undos, ms.restarts,
+               ms.computed_at
+        FROM match_stats ms
+        JOIN matches m ON m.id = ms.match_id
+        JOIN sessions s ON s.id = m.session_id
+        WHERE ` + exportMatches + `
+        ORDER BY m.started_at, m.id
+    `
+	return each(ctx, r.pool, query, exportArgs(filter), scanMatchKPI, fn)
+}
+
+func exportArgs(filter entity.ExportFilter) []any {
+	return []any{
+		nullableTime(filter.From),
+		nullableTime(filter.To),
+		nullableString(filter.PlayerID),
+		nullableInt(filter.DifficultyID),
+		nullableString(filter.Experiment),
+		nullableString(filter.Arm),
+	}
+}
+
+// each runs query and hands every scanned row to fn as it is read.
+func each[T any](
+	ctx context.Context,
+	q pgxQuerier,
+	query string,
+	args []any,
+	scan func(pgx.Row, *T) error,
+	fn func(T) error,
+) error {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v T
+		if err := scan(rows, &v); err != nil {
+			return err
+		}
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -13,6 +13,16 @@ type pgxQuerier interface {
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 }
 
+// exec runs a statement that returns no rows through a pgxQuerier.
+func exec(ctx context.Context, q pgxQuerier, sql string, args ...any) error {
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	rows.Close()
+	return rows.Err()
+}
+
 func nullableString(v *string) any {
 	if v == nil {
 		return nil
@@ -79,3 +89,11 @@ func floatPtrFromNull(nf sql.NullFloat64) *float64 {
 	v := nf.Float64
 	return &v
 }
+
+func boolPtrFromNull(nb sql.NullBool) *bool {
+	if !nb.Valid {
+		return nil
+	}
+	v := nb.Bool
+	return &v
+}
@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+type HintRepository struct {
+	pool pgxQuerier
+}
+
+var _ ports.HintRepo = (*HintRepository)(nil)
+
+func NewHintRepository(pool pgxQuerier) *HintRepository {
+	return &HintRepository{pool: traced(pool)}
+}
+
+func (r *HintRepository) Create(ctx context.Context, hint entity.Hint) (entity.Hint, error) {
+	ctx = withQuery(ctx, "hints.create", matchIDAttr(hint.MatchID))
+	var created entity.Hint
+	query := `
+        WITH created AS (
+            INSERT INTO hints (match_id, seq, hint_type, source, from_idx, to_idx, dismissed)
+            SELECT $1::uuid, COALESCE(MAX(seq), 0), $2::text, $3::text, $4::int, $5::int, $6::boolean
+            FROM moves
+            WHERE match_id = $1
+            RETURNING id, match_id, seq, hint_type, source, from_idx, to_idx,
+                      dismissed, followed, created_at, resolved_at
+        ), event AS (
+            INSERT INTO outbox_events (event_type, aggregate_id, payload)
+            SELECT $7::text, match_id, to_jsonb(created) FROM created
+        )
+        SELECT id, match_id, seq, hint_type, source, from_idx, to_idx,
+               dismissed, followed, created_at, resolved_at
+        FROM created
+    `
+	row := r.pool.QueryRow(ctx, query,
+		hint.MatchID,
+		hint.HintType,
+		hint.Source,
+		nullableInt(hint.FromIdx),
+		nullableInt(hint.ToIdx),
+		hint.Dismissed,
+		entity.EventHintRecorded,
+	)
+	if err := scanHint(row, &created); err != nil {
+		return entity.Hint{}, err
+	}
+	return created, nil
+}
+
+func (r *HintRepository) ListByMatch(ctx context.Context, matchID string) ([]entity.Hint, error) {
+	ctx = withQuery(ctx, "hints.list_by_match", matchIDAttr(matchID))
+	query := `
+        SELECT id, match_id, seq, hint_type, source, from_idx, to_idx,
+               dismissed, followed, created_at, resolved_at
+        FROM hints
+        WHERE match_id = $1
+        ORDER BY seq, created_at
+    `
+	rows, err := r.pool.Query(ctx, query, matchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hints []entity.Hint
+	for rows.Next() {
+		var h entity.Hint
+		if err := scanHint(rows, &h); err != nil {
+			return nil, err
+		}
+		hints = append(hints, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return hints, nil
+}
+
+func (r *HintRepository) Dismiss(ctx context.Context, matchID, id string) (entity.Hint, error) {
+	ctx = withQuery(ctx, "hints.dismiss", matchIDAttr(matchID))
+	var hint entity.Hint
+	query := `
+        UPDATE hints
+        SET dismissed = TRUE
+        WHERE match_id = $1 AND id = $2
+        RETURNING id, match_id, seq, hint_type, source, from_idx, to_idx,
+                  dismissed, followed, created_at, resolved_at
+    `
+	row := r.pool.QueryRow(ctx, query, matchID, id)
+	if err := scanHint(row, &hint); err != nil {
+		return entity.Hint{}, err
+	}
+	return hint, nil
+}
+
+func (r *HintRepository) ListUnresolved(ctx context.Context, matchID string, seq int) ([]entity.Hint, error) {
+	ctx = withQuery(ctx, "hints.list_unresolved", matchIDAttr(matchID))
+	query := `
+        SELECT id, match_id, seq, hint_type, source, from_idx, to_idx,
+               dismissed, followed, created_at, resolved_at
+        FROM hints
+        WHERE match_id = $1 AND seq = $2 AND followed IS NULL
+        ORDER BY created_at
+    `
+	rows, err := r.pool.Query(ctx, query, matchID, seq)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hints []entity.Hint
+	for rows.Next() {
+		var h entity.Hint
+		if err := scanHint(rows, &h); err != nil {
+			return nil, err
+		}
+		hints = append(hints, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return hints, nil
+}
+
+func (r *HintRepository) Resolve(ctx context.Context, matchID, id string, followed bool) error {
+	ctx = withQuery(ctx, "hints.resolve", matchIDAttr(matchID))
+	query := `
+        UPDATE hints
+        SET followed = $3, resolved_at = now()
+        WHERE match_id = $1 AND id = $2 AND followed IS NULL
+    `
+	return exec(ctx, r.pool, query, matchID, id, followed)
+}
+
+func (r *HintRepository) SummaryByMatch(ctx context.Context, matchID string) (entity.HintSummary, error) {
+	ctx = withQuery(ctx, "hints.summary_by_match", matchIDAttr(matchID))
+	var summary entity.HintSummary
+	query := `
+        SELECT COUNT(*),
+               COUNT(*) FILTER (WHERE h.source = 'player'),
+               COUNT(*) FILTER (WHERE h.source = 'agent'),
+               COUNT(*) FILTER (WHERE h.dismissed),
+               COUNT(*) FILTER (WHERE h.followed),
+               COUNT(*) FILTER (WHERE NOT h.followed),
+               COUNT(DISTINCT mv.id) FILTER (WHERE mv.move_kind IN (1, 2) AND NOT mv.is_correct)
+        FROM hints h
+        LEFT JOIN moves mv ON mv.match_id = h.match_id AND mv.seq = h.seq + 1
+        WHERE h.match_id = $1
+    `
+	err := r.pool.QueryRow(ctx, query, matchID).Scan(
+		&summary.Total,
+		&summary.PlayerRequested,
+		&summary.AgentInitiated,
+		&summary.Dismissed,
+		&summary.Followed,
+		&summary.NotFollowed,
+		&summary.ErrorsAfterHint,
+	)
+	if err != nil {
+		return entity.HintSummary{}, err
+	}
+	return summary, nil
+}
+
+func scanHint(row pgx.Row, hint *entity.Hint) error {
+	var (
+		fromIdx    sql.NullInt64
+		toIdx      sql.NullInt64
+		followed   sql.NullBool
+		resolvedAt sql.NullTime
+	)
+	if err := row.Scan(
+		&hint.ID,
+		&hint.MatchID,
+		&hint.Seq,
+		&hint.HintType,
+		&hint.Source,
+		&fromIdx,
+		&toIdx,
+		&hint.Dismissed,
+		&followed,
+		&hint.CreatedAt,
+		&resolvedAt,
+	); err != nil {
+		return err
+	}
+	hint.FromIdx = intPtrFromNull(fromIdx)
+	hint.ToIdx = intPtrFromNull(toIdx)
+	hint.Followed = boolPtrFromNull(followed)
+	hint.ResolvedAt = timePtrFromNull(resolvedAt)
+	return nil
+}
@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+type MatchMetricsRepository struct {
+	pool pgxQuerier
+}
+
+var _ ports.MatchMetricsRepo = (*MatchMetricsRepository)(nil)
+
+func NewMatchMetricsRepository(pool pgxQuerier) *MatchMetricsRepository {
+	return &MatchMetricsRepository{pool: traced(pool)}
+}
+
+func (r *MatchMetricsRepository) Upsert(ctx context.Context, metrics entity.MatchMetrics) (entity.MatchMetrics, error) {
+	ctx = withQuery(ctx, "match_metrics.upsert", matchIDAttr(metrics.MatchID))
+	var saved entity.MatchMetrics
+	query := `
+        INSERT INTO match_metrics (
+            match_id, think_outliers, think_outlier_threshold_ms, optimal_moves,
+            longest_optimal_streak, avg_recovery_ms, first_move_latency_ms,
+            restarts, jumps, steps, jump_ratio
+        )
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        ON CONFLICT (match_id) DO UPDATE
+        SET think_outliers = EXCLUDED.think_outliers,
+            think_outlier_threshold_ms = EXCLUDED.think_outlier_threshold_ms,
+            optimal_moves = EXCLUDED.optimal_moves,
+            longest_optimal_streak = EXCLUDED.longest_optimal_streak,
+            avg_recovery_ms = EXCLUDED.avg_recovery_ms,
+            first_move_latency_ms = EXCLUDED.first_move_latency_ms,
+            restarts = EXCLUDED.restarts,
+            jumps = EXCLUDED.jumps,
+            steps = EXCLUDED.steps,
+            jump_ratio = EXCLUDED.jump_ratio,
+            computed_at = now()
+        RETURNING match_id, think_outliers, think_outlier_threshold_ms, optimal_moves,
+                  longest_optimal_streak, avg_recovery_ms, first_move_latency_ms,
+                  restarts, jumps, steps, jump_ratio, computed_at
+    `
+	row := r.pool.QueryRow(ctx, query,
+		metrics.MatchID,
+		metrics.ThinkOutliers,
+		nullableInt(metrics.ThinkOutlierThresholdMs),
+		metrics.OptimalMoves,
+		metrics.LongestOptimalStreak,
+		nullableFloat(metrics.AvgRecoveryMs),
+		nullableInt(metrics.FirstMoveLatencyMs),
+		metrics.Restarts,
+		metrics.Jumps,
+		metrics.Steps,
+		nullableFloat(metrics.JumpRatio),
+	)
+	if err := scanMatchMetrics(row, &saved); err != nil {
+		return entity.MatchMetrics{}, err
+	}
+	return saved, nil
+}
+
+func (r *MatchMetricsRepository) GetByMatch(ctx context.Context, matchID string) (entity.MatchMetrics, error) {
+	ctx = withQuery(ctx, "match_metrics.get_by_match", matchIDAttr(matchID))
+	var metrics entity.MatchMetrics
+	query := `
+        SELECT match_id, think_outliers, think_outlier_threshold_ms, optimal_moves,
+               longest_optimal_streak, avg_recovery_ms, first_move_latency_ms,
+               restarts, jumps, steps, jump_ratio, computed_at
+        FROM match_metrics
+        WHERE match_id = $1
+    `
+	row := r.pool.QueryRow(ctx, query, matchID)
+	if err := scanMatchMetrics(row, &metrics); err != nil {
+		return entity.MatchMetrics{}, err
+	}
+	return metrics, nil
+}
+
+func scanMatchMetrics(row pgx.Row, metrics *entity.MatchMetrics) error {
+	var (
+		threshold    sql.NullInt64
+		avgRecovery  sql.NullFloat64
+		firstLatency sql.NullInt64
+		jumpRatio    sql.NullFloat64
+	)
+	if err := row.Scan(
+		&metrics.MatchID,
+		&metrics.ThinkOutliers,
+		&threshold,
+		&metrics.OptimalMoves,
+		&metrics.LongestOptimalStreak,
+		&avgRecovery,
+		&firstLatency,
+		&metrics.Restarts,
+		&metrics.Jumps,
+		&metrics.Steps,
+		&jumpRatio,
+		&metrics.ComputedAt,
+	); err != nil {
+		return err
+	}
+	metrics.ThinkOutlierThresholdMs = intPtrFromNull(threshold)
+	metrics.AvgRecoveryMs = floatPtrFromNull(avgRecovery)
+	metrics.FirstMoveLatencyMs = intPtrFromNull(firstLatency)
+	metrics.JumpRatio = floatPtrFromNull(jumpRatio)
+	return nil
+}
@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+type MatchPauseRepository struct {
+	pool pgxQuerier
+}
+
+var _ ports.MatchPauseRepo = (*MatchPauseRepository)(nil)
+
+func NewMatchPauseRepository(pool pgxQuerier) *MatchPauseRepository {
+	return &MatchPauseRepository{pool: traced(pool)}
+}
+
+func (r *MatchPauseRepository) Pause(ctx context.Context, matchID string, at time.Time) (entity.MatchPause, error) {
+	ctx = withQuery(ctx, "match_pauses.pause", matchIDAttr(matchID))
+	var pause entity.MatchPause
+	query := `
+        INSERT INTO match_pauses (match_id, paused_at)
+        VALUES ($1, $2)
+        ON CONFLICT (match_id) WHERE resumed_at IS NULL DO NOTHING
+        RETURNING id, match_id, paused_at, resumed_at
+    `
+	row := r.pool.QueryRow(ctx, query, matchID, at)
+	if err := scanMatchPause(row, &pause); err != nil {
+		return entity.MatchPause{}, err
+	}
+	return pause, nil
+}
+
+func (r *MatchPauseRepository) Resume(ctx context.Context, matchID string, at time.Time) (entity.MatchPause, error) {
+	ctx = withQuery(ctx, "match_pauses.resume", matchIDAttr(matchID))
+	var pause entity.MatchPause
+	query := `
+        UPDATE match_pauses
+        SET resumed_at = GREATEST($2, paused_at)
+        WHERE match_id = $1 AND resumed_at IS NULL
+        RETURNING id, match_id, paused_at, resumed_at
+    `
+	row := r.pool.QueryRow(ctx, query, matchID, at)
+	if err := scanMatchPause(row, &pause); err != nil {
+		return entity.MatchPause{}, err
+	}
+	return pause, nil
+}
+
+func (r *MatchPauseRepository) ListByMatch(ctx context.Context, matchID string) ([]entity.MatchPause, error) {
+	ctx = withQuery(ctx, "match_pauses.list_by_match", matchIDAttr(matchID))
+	query := `
+        SELECT id, match_id, paused_at, resumed_at
+        FROM match_pauses
+        WHERE match_id = $1
+        ORDER BY paused_at
+    `
+	rows, err := r.pool.Query(ctx, query, matchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pauses []entity.MatchPause
+	for rows.Next() {
+		var p entity.MatchPause
+		if err := scanMatchPause(rows, &p); err != nil {
+			return nil, err
+		}
+		pauses = append(pauses, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return pauses, nil
+}
+
+func (r *MatchPauseRepository) ListTimedOut(ctx context.Context, limit int) ([]entity.Match, error) {
+	ctx = withQuery(ctx, "matches.list_timed_out")
+	// An open pause stops the clock at paused_at, so it counts as paused up
+	// to now. The limit is the one of the difficulty version being played.
+	query := `
+        SELECT m.id, m.session_id, m.difficulty_id, m.difficulty_version, m.level_n, m.is_active,
+               m.started_at, m.ended_at, m.outcome, m.meta
+        FROM matches m
+        JOIN difficulty_versions d ON d.difficulty_id = m.difficulty_id AND d.version = m.difficulty_version
+        WHERE m.is_active
+          AND d.time_limit_ms IS NOT NULL
+          AND EXTRACT(EPOCH FROM now() - m.started_at) * 1000
+              - COALESCE((
+                    SELECT SUM(EXTRACT(EPOCH FROM COALESCE(p.resumed_at, now()) - p.paused_at) * 1000)
+                    FROM match_pauses p
+                    WHERE p.match_id = m.id
+                ), 0) >= d.time_limit_ms
+        ORDER BY m.started_at
+        LIMIT $1
+    `
+	rows, err := r.pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []entity.Match
+	for rows.Next() {
+		var m entity.Match
+		if err := scanMatch(rows, &m); err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+func scanMatchPause(row pgx.Row, pause *entity.MatchPause) error {
+	var resumedAt sql.NullTime
+	if err := row.Scan(&pause.ID, &pause.MatchID, &pause.PausedAt, &resumedAt); err != nil {
+		return err
+	}
+	pause.ResumedAt = timePtrFromNull(resumedAt)
+	return nil
+}
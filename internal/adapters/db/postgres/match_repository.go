@@ -17,15 +17,23 @@ type MatchRepository struct {
 var _ ports.MatchRepo = (*MatchRepository)(nil)
 
 func NewMatchRepository(pool pgxQuerier) *MatchRepository {
-	return &MatchRepository{pool: pool}
+	return &MatchRepository{pool: traced(pool)}
 }
 
 func (r *MatchRepository) Create(ctx context.Context, match entity.Match) (entity.Match, error) {
+	ctx = withQuery(ctx, "matches.create")
 	var created entity.Match
 	query := `
-        INSERT INTO matches (session_id, difficulty_id, level_n, is_active, outcome, meta)
-        VALUES ($1, $2, $3, $4, $5, $6)
-        RETURNING id, session_id, difficulty_id, level_n, is_active, started_at, ended_at, outcome, meta
+        WITH created AS (
+            INSERT INTO matches (session_id, difficulty_id, level_n, is_active, outcome, meta)
+            VALUES ($1, $2, $3, $4, $5, $6)
+            RETURNING id, session_id, difficulty_id, difficulty_version, level_n, is_active, started_at, ended_at, outcome, meta
+        ), event AS (
+            INSERT INTO outbox_events (event_type, aggregate_id, payload)
+            SELECT $7::text, id, to_jsonb(created) FROM created
+        )
+        SELECT id, session_id, difficulty_id, difficulty_version, level_n, is_active, started_at, ended_at, outcome, meta
+        FROM created
     `
 	row := r.pool.QueryRow(ctx, query,
 		match.SessionID,
@@ -34,6 +42,7 @@ func (r *MatchRepository) Create(ctx context.Context, match entity.Match) (entit
 		match.IsActive,
 		nullableString(match.Outcome),
 		nullableBytes(match.Meta),
+		entity.EventMatchStarted,
 	)
 	if err := scanMatch(row, &created); err != nil {
 		return entity.Match{}, err
@@ -42,9 +51,10 @@ func (r *MatchRepository) Create(ctx context.Context, match entity.Match) (entit
 }
 
 func (r *MatchRepository) Get(ctx context.Context, id string) (entity.Match, error) {
+	ctx = withQuery(ctx, "matches.get", matchIDAttr(id))
 	var match entity.Match
 	query := `
-        SELECT id, session_id, difficulty_id, level_n, is_active, started_at, ended_at, outcome, meta
+        SELECT id, session_id, difficulty_id, difficulty_version, level_n, is_active, started_at, ended_at, outcome, meta
         FROM matches
         WHERE id = $1
     `
@@ -56,20 +66,44 @@ func (r *MatchRepository) Get(ctx context.Context, id string) (entity.Match, err
 }
 
 func (r *MatchRepository) Update(ctx context.Context, match entity.Match) (entity.Match, error) {
+	ctx = withQuery(ctx, "matches.update", matchIDAttr(match.ID))
 	var updated entity.Match
+	// MatchFinished is only recorded when this update closes an active match.
+	// The audit entry holds the fields that changed, if any.
 	query := `
-        UPDATE matches
-        SET session_id = $2,
-            difficulty_id = $3,
-            level_n = $4,
-            is_active = $5,
-            started_at = $6,
-            ended_at = $7,
-            outcome = $8,
-            meta = $9
-        WHERE id = $1
-        RETURNING id, session_id, difficulty_id, level_n, is_active, started_at, ended_at, outcome, meta
+        WITH previous AS (
+            SELECT id, session_id, difficulty_id, difficulty_version, level_n, is_active, started_at, ended_at, outcome, meta
+            FROM matches
+            WHERE id = $1
+            FOR UPDATE
+        ), updated AS (
+            UPDATE matches
+            SET session_id = $2,
+                difficulty_id = $3,
+                level_n = $4,
+                is_active = $5,
+                started_at = $6,
+                ended_at = $7,
+                outcome = $8,
+                meta = $9
+            WHERE id = $1
+            RETURNING id, session_id, difficulty_id, difficulty_version, level_n, is_active, started_at, ended_at, outcome, meta
+        ), event AS (
+            INSERT INTO outbox_events (event_type, aggregate_id, payload)
+            SELECT $10::text, updated.id, to_jsonb(updated)
+            FROM updated, previous
+            WHERE previous.is_active AND NOT updated.is_active
+        ), audit AS (
+            INSERT INTO audit_log (actor, action, entity_type, entity_id, changes, request_id)
+            SELECT $11::text, $12::text, 'match', updated.id::text, c.changes, $13::text
+            FROM updated, previous,
+                 LATERAL (SELECT _audit_changes(to_jsonb(previous), to_jsonb(updated)) AS changes) c
+            WHERE c.changes <> '{}'::jsonb
+        )
+        SELECT id, session_id, difficulty_id, difficulty_version, level_n, is_active, started_at, ended_at, outcome, meta
+        FROM updated
     `
+	actor, requestID := auditContext(ctx)
 	row := r.pool.QueryRow(ctx, query,
 		match.ID,
 		match.SessionID,
@@ -80,6 +114,10 @@ func (r *MatchRepository) Update(ctx context.Context, match entity.Match) (entit
 		nullableTime(match.EndedAt),
 		nullableString(match.Outcome),
 		nullableBytes(match.Meta),
+		entity.EventMatchFinished,
+		actor,
+		entity.AuditMatchUpdate,
+		requestID,
 	)
 	if err := scanMatch(row, &updated); err != nil {
 		return entity.Match{}, err
@@ -87,10 +125,59 @@ func (r *MatchRepository) Update(ctx context.Context, match entity.Match) (entit
 	return updated, nil
 }
 
+// Finish is Update narrowed to closing an active match. The row lock in
+// previous makes a concurrent finish wait and then find the match inactive.
+func (r *MatchRepository) Finish(ctx context.Context, match entity.Match) (entity.Match, error) {
+	ctx = withQuery(ctx, "matches.finish", matchIDAttr(match.ID))
+	var finished entity.Match
+	query := `
+        WITH previous AS (
+            SELECT id, session_id, difficulty_id, difficulty_version, level_n, is_active, started_at, ended_at, outcome, meta
+            FROM matches
+            WHERE id = $1 AND is_active
+            FOR UPDATE
+        ), updated AS (
+            UPDATE matches
+            SET is_active = FALSE,
+                ended_at = $2,
+                outcome = COALESCE($3, matches.outcome)
+            FROM previous
+            WHERE matches.id = previous.id
+            RETURNING matches.id, matches.session_id, matches.difficulty_id, matches.difficulty_version, matches.level_n,
+                      matches.is_active, matches.started_at, matches.ended_at, matches.outcome, matches.meta
+        ), event AS (
+            INSERT INTO outbox_events (event_type, aggregate_id, payload)
+            SELECT $4::text, id, to_jsonb(updated) FROM updated
+        ), audit AS (
+            INSERT INTO audit_log (actor, action, entity_type, entity_id, changes, request_id)
+            SELECT $5::text, $6::text, 'match', updated.id::text,
+                   _audit_changes(to_jsonb(previous), to_jsonb(updated)), $7::text
+            FROM updated, previous
+        )
+        SELECT id, session_id, difficulty_id, difficulty_version, level_n, is_active, started_at, ended_at, outcome, meta
+        FROM updated
+    `
+	actor, requestID := auditContext(ctx)
+	row := r.pool.QueryRow(ctx, query,
+		match.ID,
+		nullableTime(match.EndedAt),
+		nullableString(match.Outcome),
+		entity.EventMatchFinished,
+		actor,
+		entity.AuditMatchUpdate,
+		requestID,
+	)
+	if err := scanMatch(row, &finished); err != nil {
+		return entity.Match{}, err
+	}
+	return finished, nil
+}
+
 func (r *MatchRepository) GetActiveBySession(ctx context.Context, sessionID string) (entity.Match, error) {
+	ctx = withQuery(ctx, "matches.get_active_by_session")
 	var match entity.Match
 	query := `
-        SELECT id, session_id, difficulty_id, level_n, is_active, started_at, ended_at, outcome, meta
+        SELECT id, session_id, difficulty_id, difficulty_version, level_n, is_active, started_at, ended_at, outcome, meta
         FROM matches
         WHERE session_id = $1 AND is_active = TRUE
         LIMIT 1
@@ -102,6 +189,34 @@ func (r *MatchRepository) GetActiveBySession(ctx context.Context, sessionID stri
 	return match, nil
 }
 
+func (r *MatchRepository) ListBySession(ctx context.Context, sessionID string) ([]entity.Match, error) {
+	ctx = withQuery(ctx, "matches.list_by_session")
+	query := `
+        SELECT id, session_id, difficulty_id, difficulty_version, level_n, is_active, started_at, ended_at, outcome, meta
+        FROM matches
+        WHERE session_id = $1
+        ORDER BY started_at, id
+    `
+	rows, err := r.pool.Query(ctx, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []entity.Match
+	for rows.Next() {
+		var m entity.Match
+		if err := scanMatch(rows, &m); err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
 func scanMatch(row pgx.Row, match *entity.Match) error {
 	var (
 		endedAt sql.NullTime
@@ -112,6 +227,7 @@ func scanMatch(row pgx.Row, match *entity.Match) error {
 		&match.ID,
 		&match.SessionID,
 		&match.DifficultyID,
+		&match.DifficultyVersion,
 		&match.LevelN,
 		&match.IsActive,
 		&match.StartedAt,
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+type MatchStatsRepository struct {
+	pool pgxQuerier
+}
+
+var _ ports.MatchStatsRepo = (*MatchStatsRepository)(nil)
+
+func NewMatchStatsRepository(pool pgxQuerier) *MatchStatsRepository {
+	return &MatchStatsRepository{pool: traced(pool)}
+}
+
+func (r *MatchStatsRepository) GetByMatch(ctx context.Context, matchID string) (entity.MatchKPI, error) {
+	ctx = withQuery(ctx, "match_stats.get_by_match", matchIDAttr(matchID))
+	var kpi entity.MatchKPI
+	query := `
+        SELECT match_id, total_moves, errors, avg_time_ms, buclicidad_avg, branch_factor_avg,
+               undos, restarts, computed_at
+        FROM match_stats
+        WHERE match_id = $1
+    `
+	row := r.pool.QueryRow(ctx, query, matchID)
+	if err := scanMatchKPI(row, &kpi); err != nil {
+		return entity.MatchKPI{}, err
+	}
+	return kpi, nil
+}
+
+func scanMatchKPI(row pgx.Row, kpi *entity.MatchKPI) error {
+	return row.Scan(
+		&kpi.MatchID,
+		&kpi.TotalMoves,
+		&kpi.Errors,
+		&kpi.AvgTimeMs,
+		&kpi.BuclicidadAvg,
+		&kpi.BranchFactorAvg,
+		&kpi.Undos,
+		&kpi.Restarts,
+		&kpi.ComputedAt,
+	)
+}
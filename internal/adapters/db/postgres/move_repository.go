@@ -17,21 +17,31 @@ type MoveRepository struct {
 var _ ports.MoveRepo = (*MoveRepository)(nil)
 
 func NewMoveRepository(pool pgxQuerier) *MoveRepository {
-	return &MoveRepository{pool: pool}
+	return &MoveRepository{pool: traced(pool)}
 }
 
 func (r *MoveRepository) Create(ctx context.Context, move entity.Move) (entity.Move, error) {
+	ctx = withQuery(ctx, "moves.create", matchIDAttr(move.MatchID))
 	var created entity.Move
 	query := `
-        INSERT INTO moves (
-            match_id, seq, occurred_at, elapsed_ms, from_idx, to_idx,
-            move_kind, frog_side, is_correct, interruption,
-            board_before, board_after, branching_factor, buclicidad
+        WITH created AS (
+            INSERT INTO moves (
+                match_id, seq, occurred_at, elapsed_ms, from_idx, to_idx,
+                move_kind, frog_side, is_correct, interruption,
+                board_before, board_after, branching_factor, buclicidad
+            )
+            VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14)
+            RETURNING id, match_id, seq, occurred_at, elapsed_ms, from_idx, to_idx,
+                      move_kind, frog_side, is_correct, interruption,
+                      board_before, board_after, branching_factor, buclicidad
+        ), event AS (
+            INSERT INTO outbox_events (event_type, aggregate_id, payload)
+            SELECT $15::text, match_id, to_jsonb(created) FROM created
         )
-        VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14)
-        RETURNING id, match_id, seq, occurred_at, elapsed_ms, from_idx, to_idx,
-                  move_kind, frog_side, is_correct, interruption,
-                  board_before, board_after, branching_factor, buclicidad
+        SELECT id, match_id, seq, occurred_at, elapsed_ms, from_idx, to_idx,
+               move_kind, frog_side, is_correct, interruption,
+               board_before, board_after, branching_factor, buclicidad
+        FROM created
     `
 	row := r.pool.QueryRow(ctx, query,
 		move.MatchID,
@@ -48,6 +58,7 @@ func (r *MoveRepository) Create(ctx context.Context, move entity.Move) (entity.M
 		nullableBytes(move.BoardAfter),
 		nullableInt(move.BranchingFactor),
 		nullableFloat(move.Buclicidad),
+		entity.EventMoveRecorded,
 	)
 	if err := scanMove(row, &created); err != nil {
 		return entity.Move{}, err
@@ -56,6 +67,7 @@ func (r *MoveRepository) Create(ctx context.Context, move entity.Move) (entity.M
 }
 
 func (r *MoveRepository) GetByMatch(ctx context.Context, matchID string) ([]entity.Move, error) {
+	ctx = withQuery(ctx, "moves.get_by_match", matchIDAttr(matchID))
 	query := `
         SELECT id, match_id, seq, occurred_at, elapsed_ms, from_idx, to_idx,
                move_kind, frog_side, is_correct, interruption,
@@ -85,6 +97,7 @@ func (r *MoveRepository) GetByMatch(ctx context.Context, matchID string) ([]enti
 }
 
 func (r *MoveRepository) GetLastByMatch(ctx context.Context, matchID string) (entity.Move, error) {
+	ctx = withQuery(ctx, "moves.get_last_by_match", matchIDAttr(matchID))
 	var move entity.Move
 	query := `
         SELECT id, match_id, seq, occurred_at, elapsed_ms, from_idx, to_idx,
@@ -102,6 +115,23 @@ func (r *MoveRepository) GetLastByMatch(ctx context.Context, matchID string) (en
 	return move, nil
 }
 
+func (r *MoveRepository) GetBySeq(ctx context.Context, matchID string, seq int) (entity.Move, error) {
+	ctx = withQuery(ctx, "moves.get_by_seq", matchIDAttr(matchID))
+	var move entity.Move
+	query := `
+        SELECT id, match_id, seq, occurred_at, elapsed_ms, from_idx, to_idx,
+               move_kind, frog_side, is_correct, interruption,
+               board_before, board_after, branching_factor, buclicidad
+        FROM moves
+        WHERE match_id = $1 AND seq = $2
+    `
+	row := r.pool.QueryRow(ctx, query, matchID, seq)
+	if err := scanMove(row, &move); err != nil {
+		return entity.Move{}, err
+	}
+	return move, nil
+}
+
 func scanMove(row pgx.Row, move *entity.Move) error {
 	var (
 		boardBefore []byte
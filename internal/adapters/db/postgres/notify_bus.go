@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+const (
+	matchEventsChannel = "match_events"
+
+	listenMinBackoff = 500 * time.Millisecond
+	listenMaxBackoff = 30 * time.Second
+)
+
+type connAcquirer interface {
+	Acquire(ctx context.Context) (*pgxpool.Conn, error)
+}
+
+// NotifyBus fans match events out to every replica through Postgres
+// LISTEN/NOTIFY. Publish sends a NOTIFY; Run listens on a dedicated pool
+// connection and forwards what it receives, including this replica's own
+// notifications, to the local subscriber.
+type NotifyBus struct {
+	pool     pgxQuerier
+	acquirer connAcquirer
+	local    ports.EventPublisher
+}
+
+var _ ports.EventPublisher = (*NotifyBus)(nil)
+
+func NewNotifyBus(pool *pgxpool.Pool, local ports.EventPublisher) *NotifyBus {
+	return &NotifyBus{pool: traced(pool), acquirer: pool, local: local}
+}
+
+// notifyPayload is kept to identifiers so it stays far below the 8000 byte
+// NOTIFY limit; receivers load the move or match themselves.
+type notifyPayload struct {
+	Type       string    `json:"type"`
+	MatchID    string    `json:"match_id"`
+	Seq        int       `json:"seq"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func encodeNotifyPayload(event entity.MatchEvent) (string, error) {
+	b, err := json.Marshal(notifyPayload{
+		Type:       event.Type,
+		MatchID:    event.MatchID,
+		Seq:        event.Seq,
+		OccurredAt: event.OccurredAt,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func decodeNotifyPayload(payload string) (entity.MatchEvent, error) {
+	var p notifyPayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return entity.MatchEvent{}, err
+	}
+	if p.MatchID == "" || p.Type == "" {
+		return entity.MatchEvent{}, fmt.Errorf("notify payload missing match_id or type: %q", payload)
+	}
+	return entity.MatchEvent{
+		Type:       p.Type,
+		MatchID:    p.MatchID,
+		Seq:        p.Seq,
+		OccurredAt: p.OccurredAt,
+	}, nil
+}
+
+func (b *NotifyBus) Publish(ctx context.Context, event entity.MatchEvent) error {
+	payload, err := encodeNotifyPayload(event)
+	if err != nil {
+		return err
+	}
+	ctx = withQuery(ctx, "notify.publish", matchIDAttr(event.MatchID))
+	return exec(ctx, b.pool, `SELECT pg_notify($1, $2)`, matchEventsChannel, payload)
+}
+
+// Run listens until ctx is done, reconnecting with exponential backoff
+// whenever the listening connection is lost.
+func (b *NotifyBus) Run(ctx context.Context) {
+	backoff := listenMinBackoff
+	for {
+		start := time.Now()
+		err := b.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(start) > listenMaxBackoff {
+			backoff = listenMinBackoff
+		}
+		slog.WarnContext(ctx, "match event listener disconnected", "error", err, "retry_in", backoff.String())
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		backoff = min(backoff*2, listenMaxBackoff)
+	}
+}
+
+func (b *NotifyBus) listen(ctx context.Context) error {
+	conn, err := b.acquirer.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire: %w", err)
+	}
+	// The connection carries LISTEN state, so it never goes back to the pool.
+	pgConn := conn.Hijack()
+	defer pgConn.Close(context.Background())
+
+	if _, err := pgConn.Exec(ctx, "LISTEN "+matchEventsChannel); err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	slog.InfoContext(ctx, "listening for match events", "channel", matchEventsChannel)
+
+	for {
+		n, err := pgConn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("wait: %w", err)
+		}
+		event, err := decodeNotifyPayload(n.Payload)
+		if err != nil {
+			slog.WarnContext(ctx, "ignoring malformed match event", "error", err)
+			continue
+		}
+		if err := b.local.Publish(ctx, event); err != nil {
+			slog.WarnContext(ctx, "failed to forward match event", "match_id", event.MatchID, "error", err)
+		}
+	}
+}
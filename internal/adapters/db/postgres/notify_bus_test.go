@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+func TestNotifyPayloadRoundTrip(t *testing.T) {
+	move := entity.Move{ID: "move-1", MatchID: "match-1", Seq: 7, BoardBefore: []byte(`[1,1,0,2,2]`)}
+	event := entity.MatchEvent{
+		Type:       entity.MatchEventMoveRecorded,
+		MatchID:    "match-1",
+		Seq:        7,
+		OccurredAt: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+		Move:       &move,
+	}
+
+	payload, err := encodeNotifyPayload(event)
+	require.NoError(t, err)
+	require.NotContains(t, payload, "board_before")
+
+	decoded, err := decodeNotifyPayload(payload)
+	require.NoError(t, err)
+	require.Equal(t, event.Type, decoded.Type)
+	require.Equal(t, event.MatchID, decoded.MatchID)
+	require.Equal(t, event.Seq, decoded.Seq)
+	require.True(t, event.OccurredAt.Equal(decoded.OccurredAt))
+	require.Nil(t, decoded.Move)
+
+	_, err = decodeNotifyPayload(`{"seq":1}`)
+	require.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+type OutboxRepository struct {
+	pool pgxQuerier
+}
+
+var _ ports.OutboxRepo = (*OutboxRepository)(nil)
+
+func NewOutboxRepository(pool pgxQuerier) *OutboxRepository {
+	return &OutboxRepository{pool: traced(pool)}
+}
+
+func (r *OutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxEvent, error) {
+	ctx = withQuery(ctx, "outbox.claim_pending")
+	// SKIP LOCKED lets several relays (one per replica) share the table; the
+	// lease pushes next_attempt_at forward so a crashed relay's claim expires.
+	query := `
+        WITH due AS (
+            SELECT id
+            FROM outbox_events
+            WHERE delivered_at IS NULL AND failed_at IS NULL AND next_attempt_at <= now()
+            ORDER BY id
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED
+        )
+        UPDATE outbox_events o
+        SET attempts = o.attempts + 1,
+            next_attempt_at = now() + make_interval(secs => $2)
+        FROM due
+        WHERE o.id = due.id
+        RETURNING o.id, o.event_type, o.aggregate_id, o.payload, o.created_at, o.attempts
+    `
+	rows, err := r.pool.Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []entity.OutboxEvent
+	for rows.Next() {
+		var e entity.OutboxEvent
+		if err := rows.Scan(&e.ID, &e.Type, &e.AggregateID, &e.Payload, &e.CreatedAt, &e.Attempts); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events, nil
+}
+
+func (r *OutboxRepository) MarkDelivered(ctx context.Context, id int64) error {
+	ctx = withQuery(ctx, "outbox.mark_delivered")
+	query := `
+        UPDATE outbox_events
+        SET delivered_at = now(),
+            last_error = NULL
+        WHERE id = $1
+    `
+	return exec(ctx, r.pool, query, id)
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id int64, retryAt *time.Time, cause string) error {
+	ctx = withQuery(ctx, "outbox.mark_failed")
+	query := `
+        UPDATE outbox_events
+        SET next_attempt_at = COALESCE($2::timestamptz, next_attempt_at),
+            failed_at = CASE WHEN $2::timestamptz IS NULL THEN now() END,
+            last_error = $3
+        WHERE id = $1
+    `
+	return exec(ctx, r.pool, query, id, retryAt, cause)
+}
+
+func (r *OutboxRepository) PruneDelivered(ctx context.Context, before time.Time, limit int) (int, error) {
+	ctx = withQuery(ctx, "outbox.prune_delivered")
+	query := `
+        WITH due AS (
+            SELECT id
+            FROM outbox_events
+            WHERE delivered_at < $1
+            ORDER BY id
+            LIMIT $2
+            FOR UPDATE SKIP LOCKED
+        ), deleted AS (
+            DELETE FROM outbox_events o
+            USING due
+            WHERE o.id = due.id
+            RETURNING o.id
+        )
+        SELECT COUNT(*) FROM deleted
+    `
+	var deleted int
+	if err := r.pool.QueryRow(ctx, query, before, limit).Scan(&deleted); err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
@@ -0,0 +1,253 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+// The data subject fragments below are chains of data-modifying CTEs. They
+// expect a subject CTE holding the player_id to act on and leave the IDs of
+// the sessions they touched in affected. Followed by recordPrivacyAudit, the
+// operation and its audit record are a single statement.
+//
+// Besides the sessions themselves, the player ID and device are copied into
+// outbox event and webhook delivery payloads, xAPI statements and the
+// session changes in audit_log, so every fragment reaches those rows too.
+
+// deletePlayerData removes the subject's sessions, which cascades to their
+// matches, moves, stats, hints and pauses, along with their cohorts,
+// experiment assignments, outbox events, webhook deliveries and xAPI
+// statements. The player ID and device are dropped from the audited session
+// changes. Archived moves lose their match and are removed by
+// RetentionService.PurgeOrphaned.
+const deletePlayerData = `
+        player_sessions AS (
+            SELECT id FROM sessions WHERE player_id IN (SELECT player_id FROM subject)
+        ), player_matches AS (
+            SELECT id FROM matches WHERE session_id IN (SELECT id FROM player_sessions)
+        ), affected AS (
+            DELETE FROM sessions
+            WHERE id IN (SELECT id FROM player_sessions)
+            RETURNING id
+        ), cohorts AS (
+            DELETE FROM player_cohorts
+            WHERE player_id IN (SELECT player_id FROM subject)
+        ), assignments AS (
+            DELETE FROM experiment_assignments
+            WHERE unit_id IN (SELECT player_id::text FROM subject)
+                OR unit_id IN (SELECT id::text FROM player_sessions)
+        ), events AS (
+            DELETE FROM outbox_events
+            WHERE aggregate_id IN (SELECT id FROM player_sessions)
+                OR aggregate_id IN (SELECT id FROM player_matches)
+        ), deliveries AS (
+            DELETE FROM webhook_deliveries
+            WHERE payload->>'id' IN (SELECT id::text FROM player_sessions)
+                OR payload->>'session_id' IN (SELECT id::text FROM player_sessions)
+        ), statements AS (
+            DELETE FROM xapi_statements
+            WHERE statement->'context'->>'registration' IN (SELECT id::text FROM player_sessions)
+        ), session_changes AS (
+            UPDATE audit_log SET changes = changes - 'player_id' - 'device'
+            WHERE entity_type = 'session'
+                AND entity_id IN (SELECT id::text FROM player_sessions)
+                AND changes ?| ARRAY['player_id', 'device']
+        )`
+
+// anonymizePlayerData moves the subject's sessions, cohorts and experiment
+// assignments to a random player ID and replaces each device with a token
+// salted at random. Neither the ID nor the salt is kept, so the tokens cannot
+// be traced back, while the sessions of the player still group together.
+// Session payloads in the outbox and in webhook deliveries, and the actor and
+// platform of xAPI statements, get the same tokens; the player ID and device
+// are dropped from the audited session changes, whose before and after
+// values would otherwise link the tokens to the player.
+const anonymizePlayerData = `
+        player_sessions AS (
+            SELECT id FROM sessions WHERE player_id IN (SELECT player_id FROM subject)
+        ), player_matches AS (
+            SELECT id FROM matches WHERE session_id IN (SELECT id FROM player_sessions)
+        ), token AS (
+            SELECT gen_random_uuid() AS player_id, gen_random_uuid()::text AS salt
+        ), affected AS (
+            UPDATE sessions
+            SET player_id = token.player_id,
+                device = 'anon-' || left(md5(token.salt || sessions.device), 16)
+            FROM token
+            WHERE sessions.id IN (SELECT id FROM player_sessions)
+            RETURNING sessions.id, sessions.player_id, sessions.device
+        ), cohorts AS (
+            UPDATE player_cohorts SET player_id = token.player_id
+            FROM token
+            WHERE player_cohorts.player_id IN (SELECT player_id FROM subject)
+        ), assignments AS (
+            UPDATE experiment_assignments SET unit_id = token.player_id::text
+            FROM token
+            WHERE unit_id IN (SELECT player_id::text FROM subject)
+        ), events AS (
+            UPDATE outbox_events
+            SET payload = payload || jsonb_build_object('player_id', a.player_id, 'device', a.device)
+            FROM affected a
+            WHERE outbox_events.aggregate_id = a.id AND outbox_events.payload ? 'player_id'
+        ), deliveries AS (
+            UPDATE webhook_deliveries
+            SET payload = payload || jsonb_build_object('player_id', a.player_id, 'device', a.device)
+            FROM affected a
+            WHERE webhook_deliveries.payload->>'id' = a.id::text AND webhook_deliveries.payload ? 'player_id'
+        ), statements AS (
+            UPDATE xapi_statements
+            SET statement = jsonb_set(
+                    CASE WHEN statement->'context' ? 'platform'
+                         THEN jsonb_set(statement, '{context,platform}', to_jsonb(a.device))
+                         ELSE statement
+                    END,
+                    '{actor,account,name}', to_jsonb(a.player_id::text))
+            FROM affected a
+            WHERE xapi_statements.statement->'context'->>'registration' = a.id::text
+        ), session_changes AS (
+            UPDATE audit_log SET changes = changes - 'player_id' - 'device'
+            WHERE entity_type = 'session'
+                AND entity_id IN (SELECT id::text FROM player_sessions)
+                AND changes ?| ARRAY['player_id', 'device']
+        )`
+
+// clearPlayerBoards drops the boards recorded in the moves of the matches
+// selected by player_matches and in their MoveRecorded outbox payloads. The
+// archives of those matches are flagged for RetentionService to rewrite the
+// objects without boards.
+const clearPlayerBoards = `
+        boards AS (
+            UPDATE moves SET board_before = NULL, board_after = NULL
+            WHERE match_id IN (SELECT id FROM player_matches)
+        ), move_events AS (
+            UPDATE outbox_events
+            SET payload = payload || '{"board_before": null, "board_after": null}'::jsonb
+            WHERE aggregate_id IN (SELECT id FROM player_matches) AND payload ? 'board_before'
+        ), archived_boards AS (
+            UPDATE match_archives SET clear_boards = TRUE
+            WHERE match_id IN (SELECT id FROM player_matches)
+        )`
+
+// recordPrivacyAudit stores the audit record of the operation, taking the
+// action, reason, actor and request ID as $2..$5.
+const recordPrivacyAudit = `
+        audit AS (
+            INSERT INTO privacy_audit (player_id, action, reason, actor, request_id, sessions_affected)
+            SELECT player_id, $2::text, $3, $4, $5, (SELECT COUNT(*) FROM affected)
+            FROM subject
+            RETURNING id, player_id, action, reason, actor, request_id, sessions_affected, created_at
+        )`
+
+type PrivacyRepository struct {
+	pool pgxQuerier
+}
+
+var _ ports.PrivacyRepo = (*PrivacyRepository)(nil)
+
+func NewPrivacyRepository(pool pgxQuerier) *PrivacyRepository {
+	return &PrivacyRepository{pool: traced(pool)}
+}
+
+func (r *PrivacyRepository) Delete(ctx context.Context, audit entity.PrivacyAudit) (entity.PrivacyAudit, error) {
+	ctx = withQuery(ctx, "privacy.delete", playerIDAttr(audit.PlayerID))
+	return r.run(ctx, deletePlayerData, audit)
+}
+
+func (r *PrivacyRepository) Anonymize(ctx context.Context, audit entity.PrivacyAudit) (entity.PrivacyAudit, error) {
+	ctx = withQuery(ctx, "privacy.anonymize", playerIDAttr(audit.PlayerID))
+	return r.run(ctx, anonymizePlayerData, audit)
+}
+
+func (r *PrivacyRepository) run(ctx context.Context, operation string, audit entity.PrivacyAudit) (entity.PrivacyAudit, error) {
+	query := `
+        WITH subject AS (
+            SELECT $1::uuid AS player_id
+        ),` + operation + `,` + recordPrivacyAudit + `
+        SELECT id, player_id, action, reason, actor, request_id, sessions_affected, created_at
+        FROM audit
+    `
+	var recorded entity.PrivacyAudit
+	if err := scanPrivacyAudit(r.pool.QueryRow(ctx, query, privacyAuditArgs(audit)...), &recorded); err != nil {
+		return entity.PrivacyAudit{}, err
+	}
+	return recorded, nil
+}
+
+func (r *PrivacyRepository) RecordAudit(ctx context.Context, audit entity.PrivacyAudit) (entity.PrivacyAudit, error) {
+	ctx = withQuery(ctx, "privacy_audit.create", playerIDAttr(audit.PlayerID))
+	query := `
+        INSERT INTO privacy_audit (player_id, action, reason, actor, request_id, sessions_affected)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, player_id, action, reason, actor, request_id, sessions_affected, created_at
+    `
+	var recorded entity.PrivacyAudit
+	args := append(privacyAuditArgs(audit), audit.SessionsAffected)
+	if err := scanPrivacyAudit(r.pool.QueryRow(ctx, query, args...), &recorded); err != nil {
+		return entity.PrivacyAudit{}, err
+	}
+	return recorded, nil
+}
+
+func (r *PrivacyRepository) ListAudit(ctx context.Context, playerID *string, limit int) ([]entity.PrivacyAudit, error) {
+	ctx = withQuery(ctx, "privacy_audit.list")
+	query := `
+        SELECT id, player_id, action, reason, actor, request_id, sessions_affected, created_at
+        FROM privacy_audit
+        WHERE ($1::uuid IS NULL OR player_id = $1)
+        ORDER BY created_at DESC, id DESC
+        LIMIT $2
+    `
+	rows, err := r.pool.Query(ctx, query, nullableString(playerID), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []entity.PrivacyAudit
+	for rows.Next() {
+		var audit entity.PrivacyAudit
+		if err := scanPrivacyAudit(rows, &audit); err != nil {
+			return nil, err
+		}
+		records = append(records, audit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// privacyAuditArgs are the player ID followed by the $2..$5 arguments of
+// recordPrivacyAudit.
+func privacyAuditArgs(audit entity.PrivacyAudit) []any {
+	return []any{
+		audit.PlayerID,
+		audit.Action,
+		audit.Reason,
+		audit.Actor,
+		nullableString(audit.RequestID),
+	}
+}
+
+func scanPrivacyAudit(row pgx.Row, audit *entity.PrivacyAudit) error {
+	var requestID sql.NullString
+	if err := row.Scan(
+		&audit.ID,
+		&audit.PlayerID,
+		&audit.Action,
+		&audit.Reason,
+		&audit.Actor,
+		&requestID,
+		&audit.SessionsAffected,
+		&audit.CreatedAt,
+	); err != nil {
+		return err
+	}
+	audit.RequestID = stringPtrFromNull(requestID)
+	return nil
+}
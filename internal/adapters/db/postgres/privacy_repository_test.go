@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+// recordingQuerier keeps the last statement it was sent.
+type recordingQuerier struct {
+	sql string
+}
+
+func (q *recordingQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	q.sql = sql
+	return nil, errors.New("not implemented")
+}
+
+func (q *recordingQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	q.sql = sql
+	return stubRow{scanFn: func(dest ...any) error { return pgx.ErrNoRows }}
+}
+
+// privacyStatement returns the statement run by a data subject operation.
+func privacyStatement(t *testing.T, action string, withdraw bool) string {
+	t.Helper()
+	q := &recordingQuerier{}
+	audit := entity.PrivacyAudit{PlayerID: "7d3c3b1e-0000-4000-8000-000000000001", Action: action}
+	var err error
+	switch {
+	case withdraw:
+		_, _, err = NewConsentRepository(q).Withdraw(context.Background(), audit)
+	case action == entity.PrivacyDelete:
+		_, err = NewPrivacyRepository(q).Delete(context.Background(), audit)
+	default:
+		_, err = NewPrivacyRepository(q).Anonymize(context.Background(), audit)
+	}
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	return q.sql
+}
+
+func TestPrivacyDeleteReachesCopiedPlayerData(t *testing.T) {
+	for name, withdraw := range map[string]bool{"request": false, "consent_withdrawal": true} {
+		sql := privacyStatement(t, entity.PrivacyDelete, withdraw)
+		for table, statement := range map[string]string{
+			"outbox_events":      "DELETE FROM outbox_events",
+			"webhook_deliveries": "DELETE FROM webhook_deliveries",
+			"xapi_statements":    "DELETE FROM xapi_statements",
+			"audit_log":          "UPDATE audit_log SET changes = changes - 'player_id' - 'device'",
+		} {
+			t.Run(name+"/"+table, func(t *testing.T) {
+				require.Contains(t, sql, statement)
+			})
+		}
+	}
+}
+
+func TestPrivacyAnonymizeReachesCopiedPlayerData(t *testing.T) {
+	for name, withdraw := range map[string]bool{"request": false, "consent_withdrawal": true} {
+		sql := privacyStatement(t, entity.PrivacyAnonymize, withdraw)
+		for table, statement := range map[string]string{
+			"outbox_events":      "UPDATE outbox_events\n            SET payload = payload || jsonb_build_object('player_id', a.player_id, 'device', a.device)",
+			"webhook_deliveries": "UPDATE webhook_deliveries\n            SET payload = payload || jsonb_build_object('player_id', a.player_id, 'device', a.device)",
+			"xapi_statements":    "'{actor,account,name}', to_jsonb(a.player_id::text)",
+			"audit_log":          "UPDATE audit_log SET changes = changes - 'player_id' - 'device'",
+		} {
+			t.Run(name+"/"+table, func(t *testing.T) {
+				require.Contains(t, sql, statement)
+				require.NotContains(t, sql, "DELETE FROM "+table, "anonymizing keeps the rows")
+			})
+		}
+	}
+}
+
+func TestConsentWithdrawalClearsArchivedBoards(t *testing.T) {
+	sql := privacyStatement(t, entity.PrivacyAnonymize, true)
+	require.Contains(t, sql, "UPDATE moves SET board_before = NULL, board_after = NULL")
+	require.Contains(t, sql, `SET payload = payload || '{"board_before": null, "board_after": null}'::jsonb`)
+	require.Contains(t, sql, "UPDATE match_archives SET clear_boards = TRUE")
+
+	require.NotContains(t, privacyStatement(t, entity.PrivacyAnonymize, false), "clear_boards")
+}
@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+// reportMatches restricts a report to finished matches matching the filter,
+// passed as $1..$7 by reportArgs. It expects matches as m and sessions as s.
+const reportMatches = `
+            NOT m.is_active
+            AND ($1::timestamptz IS NULL OR m.started_at >= $1)
+            AND ($2::timestamptz IS NULL OR m.started_at < $2)
+            AND ($3::text IS NULL OR s.device = $3)
+            AND ($4::text IS NULL OR m.outcome = $4)
+            AND ($5::int IS NULL OR m.difficulty_id = $5)
+            AND ($6::text IS NULL OR m.meta->'experiments'->>$6 IS NOT NULL)
+            AND ($7::text IS NULL OR m.meta->'experiments'->>$6 = $7)
+`
+
+// reportKPIs are the match_stats columns summarised in reports, in the order
+// of entity.KPIDistributions.
+var reportKPIs = []string{"total_moves", "errors", "avg_time_ms", "buclicidad_avg", "branch_factor_avg"}
+
+// distributionColumns selects mean, median, p90 and sample standard deviation
+// of every report KPI.
+var distributionColumns = func() string {
+	cols := make([]string, 0, len(reportKPIs))
+	for _, kpi := range reportKPIs {
+		cols = append(cols, fmt.Sprintf(
+			"AVG(ms.%[1]s)::float8, "+
+				"percentile_cont(0.5) WITHIN GROUP (ORDER BY ms.%[1]s), "+
+				"percentile_cont(0.9) WITHIN GROUP (ORDER BY ms.%[1]s), "+
+				"stddev_samp(ms.%[1]s)::float8",
+			kpi))
+	}
+	return strings.Join(cols, ",\n               ")
+}()
+
+type ReportRepository struct {
+	pool pgxQuerier
+}
+
+var _ ports.ReportRepo = (*ReportRepository)(nil)
+
+func NewReportRepository(pool pgxQuerier) *ReportRepository {
+	return &ReportRepository{pool: traced(pool)}
+}
+
+func (r *ReportRepository) DifficultyReport(ctx context.Context, filter entity.ReportFilter) ([]entity.DifficultyReport, error) {
+	ctx = withQuery(ctx, "reports.difficulties")
+	query := `
+        SELECT d.id, d.name, COUNT(*),
+               ` + distributionColumns + `
+        FROM matches m
+        JOIN sessions s ON s.id = m.session_id
+        JOIN match_stats ms ON ms.match_id = m.id
+        JOIN difficulty d ON d.id = m.difficulty_id
+        WHERE ` + reportMatches + `
+        GROUP BY d.id, d.name
+        ORDER BY d.id
+    `
+	rows, err := r.pool.Query(ctx, query, reportArgs(filter)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []entity.DifficultyReport
+	for rows.Next() {
+		var report entity.DifficultyReport
+		if err := scanDistributions(rows, &report.KPIs, &report.DifficultyID, &report.DifficultyName, &report.Matches); err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+func (r *ReportRepository) CohortReport(ctx context.Context, filter entity.ReportFilter) ([]entity.CohortReport, error) {
+	ctx = withQuery(ctx, "reports.cohorts")
+	query := `
+        SELECT pc.cohort, COUNT(DISTINCT s.player_id), COUNT(*),
+               ` + distributionColumns + `
+        FROM matches m
+        JOIN sessions s ON s.id = m.session_id
+        JOIN match_stats ms ON ms.match_id = m.id
+        JOIN player_cohorts pc ON pc.player_id = s.player_id
+        WHERE ` + reportMatches + `
+        GROUP BY pc.cohort
+        ORDER BY pc.cohort
+    `
+	rows, err := r.pool.Query(ctx, query, reportArgs(filter)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []entity.CohortReport
+	for rows.Next() {
+		var report entity.CohortReport
+		if err := scanDistributions(rows, &report.KPIs, &report.Cohort, &report.Players, &report.Matches); err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+func (r *ReportRepository) ArmReport(ctx context.Context, filter entity.ReportFilter) ([]entity.ArmReport, error) {
+	ctx = withQuery(ctx, "reports.arms")
+	query := `
+        SELECT m.meta->'experiments'->>$6, COUNT(DISTINCT s.player_id), COUNT(*),
+               ` + distributionColumns + `
+        FROM matches m
+        JOIN sessions s ON s.id = m.session_id
+        JOIN match_stats ms ON ms.match_id = m.id
+        WHERE ` + reportMatches + `
+        GROUP BY 1
+        ORDER BY 1
+    `
+	rows, err := r.pool.Query(ctx, query, reportArgs(filter)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []entity.ArmReport
+	for rows.Next() {
+		var report entity.ArmReport
+		if err := scanDistributions(rows, &report.KPIs, &report.Arm, &report.Players, &report.Matches); err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+func reportArgs(filter entity.ReportFilter) []any {
+	return []any{
+		nullableTime(filter.From),
+		nullableTime(filter.To),
+		nullableString(filter.Device),
+		nullableString(filter.Outcome),
+		nullableInt(filter.DifficultyID),
+		nullableString(filter.Experiment),
+		nullableString(filter.Arm),
+	}
+}
+
+// scanDistributions scans the leading group columns into head followed by the
+// distributionColumns into kpis.
+func scanDistributions(row pgx.Row, kpis *entity.KPIDistributions, head ...any) error {
+	dists := []*entity.Distribution{
+		&kpis.TotalMoves,
+		&kpis.Errors,
+		&kpis.AvgTimeMs,
+		&kpis.BuclicidadAvg,
+		&kpis.BranchFactorAvg,
+	}
+	stddevs := make([]sql.NullFloat64, len(dists))
+	dest := head
+	for i, d := range dists {
+		dest = append(dest, &d.Mean, &d.Median, &d.P90, &stddevs[i])
+	}
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+	for i, d := range dists {
+		d.StdDev = floatPtrFromNull(stddevs[i])
+	}
+	return nil
+}
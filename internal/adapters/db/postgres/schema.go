@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+// requiredRelations lists the tables this build reads or writes. Readiness
+// fails while any of them is missing, i.e. seed.sql has not been applied.
+var requiredRelations = []string{
+	"difficulty",
+	"difficulty_versions",
+	"curricula",
+	"curriculum_levels",
+	"sessions",
+	"matches",
+	"moves",
+	"match_stats",
+	"outbox_events",
+	"webhooks",
+	"webhook_deliveries",
+	"player_cohorts",
+	"xapi_statements",
+	"match_metrics",
+	"hints",
+	"match_pauses",
+	"experiments",
+	"experiment_arms",
+	"experiment_assignments",
+	"player_consents",
+	"privacy_audit",
+	"match_archives",
+	"audit_log",
+}
+
+// requiredColumns lists, as table.column, the columns added to tables after
+// they were first created. seed.sql adds them with ALTER TABLE, so readiness
+// also fails while an older database has not been migrated.
+var requiredColumns = []string{
+	"difficulty.time_limit_ms",
+	"difficulty.layout",
+	"difficulty.version",
+	"difficulty.retired_at",
+	"sessions.curriculum_id",
+	"matches.difficulty_version",
+	"match_stats.undos",
+	"match_stats.restarts",
+	"outbox_events.failed_at",
+	"match_archives.clear_boards",
+}
+
+type HealthProbe struct {
+	getPool func() (*pgxpool.Pool, error)
+}
+
+var _ ports.DatabaseProbe = (*HealthProbe)(nil)
+
+func NewHealthProbe(getPool func() (*pgxpool.Pool, error)) *HealthProbe {
+	return &HealthProbe{getPool: getPool}
+}
+
+func (p *HealthProbe) Ping(ctx context.Context) error {
+	pool, err := p.getPool()
+	if err != nil {
+		return err
+	}
+	return pool.Ping(ctx)
+}
+
+func (p *HealthProbe) Stats(ctx context.Context) (ports.PoolStats, error) {
+	pool, err := p.getPool()
+	if err != nil {
+		return ports.PoolStats{}, err
+	}
+	stat := pool.Stat()
+	return ports.PoolStats{
+		TotalConns:    stat.TotalConns(),
+		AcquiredConns: stat.AcquiredConns(),
+		IdleConns:     stat.IdleConns(),
+		MaxConns:      stat.MaxConns(),
+	}, nil
+}
+
+func (p *HealthProbe) MissingRelations(ctx context.Context) ([]string, error) {
+	pool, err := p.getPool()
+	if err != nil {
+		return nil, err
+	}
+	return missingRelations(ctx, pool, requiredRelations)
+}
+
+func (p *HealthProbe) MissingColumns(ctx context.Context) ([]string, error) {
+	pool, err := p.getPool()
+	if err != nil {
+		return nil, err
+	}
+	return missingColumns(ctx, pool, requiredColumns)
+}
+
+func missingRelations(ctx context.Context, q pgxQuerier, relations []string) ([]string, error) {
+	query := `
+        SELECT name
+        FROM unnest($1::text[]) AS name
+        WHERE to_regclass(name) IS NULL
+    `
+	return listNames(ctx, q, query, relations)
+}
+
+// listNames runs a query taking a list of names and returns the names it
+// selects.
+func listNames(ctx context.Context, q pgxQuerier, query string, names []string) ([]string, error) {
+	rows, err := q.Query(ctx, query, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var missing []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		missing = append(missing, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return missing, nil
+}
+
+// missingColumns skips the columns of missing tables, which missingRelations
+// already reports.
+func missingColumns(ctx context.Context, q pgxQuerier, columns []string) ([]string, error) {
+	query := `
+        SELECT name
+        FROM unnest($1::text[]) AS name
+        WHERE to_regclass(split_part(name, '.', 1)) IS NOT NULL
+          AND NOT EXISTS (
+            SELECT 1
+            FROM information_schema.columns c
+            WHERE c.table_schema = current_schema()
+              AND c.table_name = split_part(name, '.', 1)
+              AND c.column_name = split_part(name, '.', 2)
+          )
+    `
+	return listNames(ctx, q, query, columns)
+}
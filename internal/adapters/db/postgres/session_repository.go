@@ -17,17 +17,31 @@ type SessionRepository struct {
 var _ ports.SessionRepo = (*SessionRepository)(nil)
 
 func NewSessionRepository(pool pgxQuerier) *SessionRepository {
-	return &SessionRepository{pool: pool}
+	return &SessionRepository{pool: traced(pool)}
 }
 
 func (r *SessionRepository) Create(ctx context.Context, session entity.Session) (entity.Session, error) {
+	ctx = withQuery(ctx, "sessions.create")
 	var created entity.Session
 	query := `
-        INSERT INTO sessions (player_id, device, is_finished)
-        VALUES ($1, $2, $3)
-        RETURNING id, player_id, device, is_finished, started_at, ended_at
+        WITH created AS (
+            INSERT INTO sessions (player_id, device, curriculum_id, is_finished)
+            VALUES ($1, $2, $3, $4)
+            RETURNING id, player_id, device, curriculum_id, is_finished, started_at, ended_at
+        ), event AS (
+            INSERT INTO outbox_events (event_type, aggregate_id, payload)
+            SELECT $5::text, id, to_jsonb(created) FROM created
+        )
+        SELECT id, player_id, device, curriculum_id, is_finished, started_at, ended_at
+        FROM created
     `
-	row := r.pool.QueryRow(ctx, query, nullableString(session.PlayerID), nullableString(session.Device), session.IsFinished)
+	row := r.pool.QueryRow(ctx, query,
+		nullableString(session.PlayerID),
+		nullableString(session.Device),
+		nullableInt(session.CurriculumID),
+		session.IsFinished,
+		entity.EventSessionStarted,
+	)
 	if err := scanSession(row, &created); err != nil {
 		return entity.Session{}, err
 	}
@@ -35,9 +49,10 @@ func (r *SessionRepository) Create(ctx context.Context, session entity.Session)
 }
 
 func (r *SessionRepository) Get(ctx context.Context, id string) (entity.Session, error) {
+	ctx = withQuery(ctx, "sessions.get")
 	var session entity.Session
 	query := `
-        SELECT id, player_id, device, is_finished, started_at, ended_at
+        SELECT id, player_id, device, curriculum_id, is_finished, started_at, ended_at
         FROM sessions
         WHERE id = $1
     `
@@ -49,22 +64,52 @@ func (r *SessionRepository) Get(ctx context.Context, id string) (entity.Session,
 }
 
 func (r *SessionRepository) Update(ctx context.Context, session entity.Session) (entity.Session, error) {
+	ctx = withQuery(ctx, "sessions.update")
 	var updated entity.Session
+	// SessionFinished is only recorded when this update finishes the session.
+	// The audit entry holds the fields that changed, if any.
 	query := `
-        UPDATE sessions
-        SET player_id = $2,
-            device = $3,
-            is_finished = $4,
-            ended_at = $5
-        WHERE id = $1
-        RETURNING id, player_id, device, is_finished, started_at, ended_at
+        WITH previous AS (
+            SELECT id, player_id, device, curriculum_id, is_finished, started_at, ended_at
+            FROM sessions
+            WHERE id = $1
+            FOR UPDATE
+        ), updated AS (
+            UPDATE sessions
+            SET player_id = $2,
+                device = $3,
+                curriculum_id = $4,
+                is_finished = $5,
+                ended_at = $6
+            WHERE id = $1
+            RETURNING id, player_id, device, curriculum_id, is_finished, started_at, ended_at
+        ), event AS (
+            INSERT INTO outbox_events (event_type, aggregate_id, payload)
+            SELECT $7::text, updated.id, to_jsonb(updated)
+            FROM updated, previous
+            WHERE NOT previous.is_finished AND updated.is_finished
+        ), audit AS (
+            INSERT INTO audit_log (actor, action, entity_type, entity_id, changes, request_id)
+            SELECT $8::text, $9::text, 'session', updated.id::text, c.changes, $10::text
+            FROM updated, previous,
+                 LATERAL (SELECT _audit_changes(to_jsonb(previous), to_jsonb(updated)) AS changes) c
+            WHERE c.changes <> '{}'::jsonb
+        )
+        SELECT id, player_id, device, curriculum_id, is_finished, started_at, ended_at
+        FROM updated
     `
+	actor, requestID := auditContext(ctx)
 	row := r.pool.QueryRow(ctx, query,
 		session.ID,
 		nullableString(session.PlayerID),
 		nullableString(session.Device),
+		nullableInt(session.CurriculumID),
 		session.IsFinished,
 		nullableTime(session.EndedAt),
+		entity.EventSessionFinished,
+		actor,
+		entity.AuditSessionUpdate,
+		requestID,
 	)
 	if err := scanSession(row, &updated); err != nil {
 		return entity.Session{}, err
@@ -74,14 +119,16 @@ func (r *SessionRepository) Update(ctx context.Context, session entity.Session)
 
 func scanSession(row pgx.Row, session *entity.Session) error {
 	var (
-		playerID sql.NullString
-		device   sql.NullString
-		endedAt  sql.NullTime
+		playerID   sql.NullString
+		device     sql.NullString
+		curriculum sql.NullInt64
+		endedAt    sql.NullTime
 	)
 	if err := row.Scan(
 		&session.ID,
 		&playerID,
 		&device,
+		&curriculum,
 		&session.IsFinished,
 		&session.StartedAt,
 		&endedAt,
@@ -90,6 +137,7 @@ func scanSession(row pgx.Row, session *entity.Session) error {
 	}
 	session.PlayerID = stringPtrFromNull(playerID)
 	session.Device = stringPtrFromNull(device)
+	session.CurriculumID = intPtrFromNull(curriculum)
 	session.EndedAt = timePtrFromNull(endedAt)
 	return nil
 }
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/org/ranas-bdi-backend/internal/adapters/db/postgres"
+
+var tracer = otel.Tracer(instrumentationName)
+
+const (
+	matchIDKey      = attribute.Key("match.id")
+	playerIDKey     = attribute.Key("player.id")
+	rowsAffectedKey = attribute.Key("db.rows_affected")
+)
+
+type queryInfoKey struct{}
+
+type queryInfo struct {
+	name  string
+	attrs []attribute.KeyValue
+}
+
+// withQuery names the next statement issued with ctx so its span reads
+// "moves.get_last_by_match" rather than a raw SQL verb.
+func withQuery(ctx context.Context, name string, attrs ...attribute.KeyValue) context.Context {
+	return context.WithValue(ctx, queryInfoKey{}, queryInfo{name: name, attrs: attrs})
+}
+
+func matchIDAttr(matchID string) attribute.KeyValue {
+	return matchIDKey.String(matchID)
+}
+
+func playerIDAttr(playerID string) attribute.KeyValue {
+	return playerIDKey.String(playerID)
+}
+
+// tracedQuerier wraps a pgxQuerier so that every statement gets a client span
+// ending once its rows have been consumed.
+type tracedQuerier struct {
+	next pgxQuerier
+}
+
+func traced(q pgxQuerier) pgxQuerier {
+	if _, ok := q.(tracedQuerier); ok {
+		return q
+	}
+	return tracedQuerier{next: q}
+}
+
+func (q tracedQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	ctx, span := startQuerySpan(ctx, sql)
+	rows, err := q.next.Query(ctx, sql, args...)
+	if err != nil {
+		endQuerySpan(span, err)
+		return nil, err
+	}
+	return &tracedRows{Rows: rows, span: span}, nil
+}
+
+func (q tracedQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	ctx, span := startQuerySpan(ctx, sql)
+	return tracedRow{row: q.next.QueryRow(ctx, sql, args...), span: span}
+}
+
+type tracedRow struct {
+	row  pgx.Row
+	span trace.Span
+}
+
+func (r tracedRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	if err == nil {
+		r.span.SetAttributes(rowsAffectedKey.Int64(1))
+	} else if errors.Is(err, pgx.ErrNoRows) {
+		r.span.SetAttributes(rowsAffectedKey.Int64(0))
+	}
+	endQuerySpan(r.span, err)
+	return err
+}
+
+type tracedRows struct {
+	pgx.Rows
+	span  trace.Span
+	ended bool
+}
+
+func (r *tracedRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.end()
+	return false
+}
+
+func (r *tracedRows) Close() {
+	r.Rows.Close()
+	r.end()
+}
+
+func (r *tracedRows) end() {
+	if r.ended {
+		return
+	}
+	r.ended = true
+	r.span.SetAttributes(rowsAffectedKey.Int64(r.Rows.CommandTag().RowsAffected()))
+	endQuerySpan(r.span, r.Rows.Err())
+}
+
+func startQuerySpan(ctx context.Context, sql string) (context.Context, trace.Span) {
+	operation := sqlOperation(sql)
+	info, _ := ctx.Value(queryInfoKey{}).(queryInfo)
+	name := info.name
+	if name == "" {
+		name = "postgres." + strings.ToLower(operation)
+	}
+
+	attrs := append([]attribute.KeyValue{
+		semconv.DBSystemPostgreSQL,
+		semconv.DBOperationName(operation),
+		semconv.DBQueryText(strings.Join(strings.Fields(sql), " ")),
+	}, info.attrs...)
+
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+func endQuerySpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func sqlOperation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+	return strings.ToUpper(fields[0])
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracedQuerierRecordsSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	found := stubRow{scanFn: func(dest ...any) error {
+		*(dest[0].(*int)) = 1
+		*(dest[1].(*string)) = "easy"
+		*(dest[2].(*int)) = 7
+		return nil
+	}}
+	_, err := NewDifficultyRepository(stubQuerier{row: found}).GetByID(context.Background(), 1)
+	require.NoError(t, err)
+
+	missing := stubRow{scanFn: func(dest ...any) error { return pgx.ErrNoRows }}
+	_, err = NewMoveRepository(stubQuerier{row: missing}).GetLastByMatch(context.Background(), "match-1")
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	require.Equal(t, "difficulty.get_by_id", spans[0].Name())
+	require.Contains(t, spans[0].Attributes(), attribute.String("db.operation.name", "SELECT"))
+	require.Contains(t, spans[0].Attributes(), rowsAffectedKey.Int64(1))
+
+	require.Equal(t, "moves.get_last_by_match", spans[1].Name())
+	require.Contains(t, spans[1].Attributes(), matchIDKey.String("match-1"))
+	require.Contains(t, spans[1].Attributes(), rowsAffectedKey.Int64(0))
+	require.Equal(t, codes.Unset, spans[1].Status().Code)
+}
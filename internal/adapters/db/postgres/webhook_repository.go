@@ -0,0 +1,242 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+type WebhookRepository struct {
+	pool pgxQuerier
+}
+
+var _ ports.WebhookRepo = (*WebhookRepository)(nil)
+
+func NewWebhookRepository(pool pgxQuerier) *WebhookRepository {
+	return &WebhookRepository{pool: traced(pool)}
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, webhook entity.Webhook) (entity.Webhook, error) {
+	ctx = withQuery(ctx, "webhooks.create")
+	var created entity.Webhook
+	query := `
+        INSERT INTO webhooks (url, secret, event_types, is_active)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, url, secret, event_types, is_active, created_at
+    `
+	row := r.pool.QueryRow(ctx, query, webhook.URL, webhook.Secret, webhook.EventTypes, webhook.IsActive)
+	if err := scanWebhook(row, &created); err != nil {
+		return entity.Webhook{}, err
+	}
+	return created, nil
+}
+
+func (r *WebhookRepository) Get(ctx context.Context, id string) (entity.Webhook, error) {
+	ctx = withQuery(ctx, "webhooks.get")
+	var webhook entity.Webhook
+	query := `
+        SELECT id, url, secret, event_types, is_active, created_at
+        FROM webhooks
+        WHERE id = $1
+    `
+	row := r.pool.QueryRow(ctx, query, id)
+	if err := scanWebhook(row, &webhook); err != nil {
+		return entity.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (r *WebhookRepository) List(ctx context.Context) ([]entity.Webhook, error) {
+	ctx = withQuery(ctx, "webhooks.list")
+	query := `
+        SELECT id, url, secret, event_types, is_active, created_at
+        FROM webhooks
+        ORDER BY created_at
+    `
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []entity.Webhook
+	for rows.Next() {
+		var w entity.Webhook
+		if err := scanWebhook(rows, &w); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) Deactivate(ctx context.Context, id string) (entity.Webhook, error) {
+	ctx = withQuery(ctx, "webhooks.deactivate")
+	var webhook entity.Webhook
+	query := `
+        UPDATE webhooks
+        SET is_active = FALSE
+        WHERE id = $1
+        RETURNING id, url, secret, event_types, is_active, created_at
+    `
+	row := r.pool.QueryRow(ctx, query, id)
+	if err := scanWebhook(row, &webhook); err != nil {
+		return entity.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (r *WebhookRepository) EnqueueDeliveries(ctx context.Context, event entity.OutboxEvent) (int, error) {
+	ctx = withQuery(ctx, "webhook_deliveries.enqueue")
+	query := `
+        WITH inserted AS (
+            INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+            SELECT id, $1::bigint, $2::text, $3::jsonb
+            FROM webhooks
+            WHERE is_active AND $2 = ANY(event_types)
+            ON CONFLICT (webhook_id, event_id) DO NOTHING
+            RETURNING 1
+        )
+        SELECT COUNT(*) FROM inserted
+    `
+	var n int
+	if err := r.pool.QueryRow(ctx, query, event.ID, event.Type, event.Payload).Scan(&n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.WebhookDelivery, error) {
+	ctx = withQuery(ctx, "webhook_deliveries.claim_due")
+	query := `
+        WITH due AS (
+            SELECT id
+            FROM webhook_deliveries
+            WHERE status = 'pending' AND next_attempt_at <= now()
+            ORDER BY id
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED
+        )
+        UPDATE webhook_deliveries d
+        SET attempts = d.attempts + 1,
+            next_attempt_at = now() + make_interval(secs => $2)
+        FROM due
+        WHERE d.id = due.id
+        RETURNING d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+                  d.next_attempt_at, d.last_status_code, d.last_error, d.created_at, d.delivered_at
+    `
+	deliveries, err := r.queryDeliveries(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID < deliveries[j].ID })
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, id int64, statusCode int) error {
+	ctx = withQuery(ctx, "webhook_deliveries.mark_delivered")
+	query := `
+        UPDATE webhook_deliveries
+        SET status = 'delivered',
+            delivered_at = now(),
+            last_status_code = $2,
+            last_error = NULL
+        WHERE id = $1
+    `
+	return exec(ctx, r.pool, query, id, statusCode)
+}
+
+func (r *WebhookRepository) MarkFailed(ctx context.Context, id int64, statusCode *int, cause string, retryAt *time.Time) error {
+	ctx = withQuery(ctx, "webhook_deliveries.mark_failed")
+	query := `
+        UPDATE webhook_deliveries
+        SET status = CASE WHEN $4::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+            next_attempt_at = COALESCE($4, next_attempt_at),
+            last_status_code = $2,
+            last_error = $3
+        WHERE id = $1
+    `
+	return exec(ctx, r.pool, query, id, nullableInt(statusCode), cause, nullableTime(retryAt))
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, webhookID string, limit int) ([]entity.WebhookDelivery, error) {
+	ctx = withQuery(ctx, "webhook_deliveries.list")
+	query := `
+        SELECT id, webhook_id, event_id, event_type, payload, status, attempts,
+               next_attempt_at, last_status_code, last_error, created_at, delivered_at
+        FROM webhook_deliveries
+        WHERE webhook_id = $1
+        ORDER BY created_at DESC, id DESC
+        LIMIT $2
+    `
+	return r.queryDeliveries(ctx, query, webhookID, limit)
+}
+
+func (r *WebhookRepository) queryDeliveries(ctx context.Context, query string, args ...any) ([]entity.WebhookDelivery, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []entity.WebhookDelivery
+	for rows.Next() {
+		var d entity.WebhookDelivery
+		if err := scanWebhookDelivery(rows, &d); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func scanWebhook(row pgx.Row, webhook *entity.Webhook) error {
+	return row.Scan(
+		&webhook.ID,
+		&webhook.URL,
+		&webhook.Secret,
+		&webhook.EventTypes,
+		&webhook.IsActive,
+		&webhook.CreatedAt,
+	)
+}
+
+func scanWebhookDelivery(row pgx.Row, delivery *entity.WebhookDelivery) error {
+	var (
+		statusCode  sql.NullInt64
+		lastError   sql.NullString
+		deliveredAt sql.NullTime
+	)
+	if err := row.Scan(
+		&delivery.ID,
+		&delivery.WebhookID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&statusCode,
+		&lastError,
+		&delivery.CreatedAt,
+		&deliveredAt,
+	); err != nil {
+		return err
+	}
+	delivery.LastStatusCode = intPtrFromNull(statusCode)
+	delivery.LastError = stringPtrFromNull(lastError)
+	delivery.DeliveredAt = timePtrFromNull(deliveredAt)
+	return nil
+}
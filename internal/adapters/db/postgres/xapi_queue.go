@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+type XAPIQueue struct {
+	pool pgxQuerier
+}
+
+var _ ports.XAPIQueue = (*XAPIQueue)(nil)
+
+func NewXAPIQueue(pool pgxQuerier) *XAPIQueue {
+	return &XAPIQueue{pool: traced(pool)}
+}
+
+func (q *XAPIQueue) Enqueue(ctx context.Context, statementID string, statement json.RawMessage) error {
+	ctx = withQuery(ctx, "xapi_statements.enqueue")
+	query := `
+        INSERT INTO xapi_statements (statement_id, statement)
+        VALUES ($1, $2)
+        ON CONFLICT (statement_id) DO NOTHING
+    `
+	return exec(ctx, q.pool, query, statementID, statement)
+}
+
+func (q *XAPIQueue) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entity.XAPIStatement, error) {
+	ctx = withQuery(ctx, "xapi_statements.claim_due")
+	query := `
+        WITH due AS (
+            SELECT id
+            FROM xapi_statements
+            WHERE status = 'pending' AND next_attempt_at <= now()
+            ORDER BY id
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED
+        )
+        UPDATE xapi_statements x
+        SET attempts = x.attempts + 1,
+            next_attempt_at = now() + make_interval(secs => $2)
+        FROM due
+        WHERE x.id = due.id
+        RETURNING x.id, x.statement_id, x.statement, x.status, x.attempts,
+                  x.next_attempt_at, x.last_error, x.created_at, x.sent_at
+    `
+	rows, err := q.pool.Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var statements []entity.XAPIStatement
+	for rows.Next() {
+		var st entity.XAPIStatement
+		if err := scanXAPIStatement(rows, &st); err != nil {
+			return nil, err
+		}
+		statements = append(statements, st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(statements, func(i, j int) bool { return statements[i].ID < statements[j].ID })
+	return statements, nil
+}
+
+func (q *XAPIQueue) MarkSent(ctx context.Context, ids []int64) error {
+	ctx = withQuery(ctx, "xapi_statements.mark_sent")
+	query := `
+        UPDATE xapi_statements
+        SET status = 'sent',
+            sent_at = now(),
+            last_error = NULL
+        WHERE id = ANY($1)
+    `
+	return exec(ctx, q.pool, query, ids)
+}
+
+func (q *XAPIQueue) MarkFailed(ctx context.Context, id int64, cause string, retryAt *time.Time) error {
+	ctx = withQuery(ctx, "xapi_statements.mark_failed")
+	query := `
+        UPDATE xapi_statements
+        SET status = CASE WHEN $3::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+            next_attempt_at = COALESCE($3, next_attempt_at),
+            last_error = $2
+        WHERE id = $1
+    `
+	return exec(ctx, q.pool, query, id, cause, nullableTime(retryAt))
+}
+
+func scanXAPIStatement(row pgx.Row, st *entity.XAPIStatement) error {
+	var (
+		statement []byte
+		lastError sql.NullString
+		sentAt    sql.NullTime
+	)
+	if err := row.Scan(
+		&st.ID,
+		&st.StatementID,
+		&statement,
+		&st.Status,
+		&st.Attempts,
+		&st.NextAttemptAt,
+		&lastError,
+		&st.CreatedAt,
+		&sentAt,
+	); err != nil {
+		return err
+	}
+	st.Statement = append(json.RawMessage(nil), statement...)
+	st.LastError = stringPtrFromNull(lastError)
+	st.SentAt = timePtrFromNull(sentAt)
+	return nil
+}
@@ -9,7 +9,80 @@ CREATE TABLE IF NOT EXISTS difficulty (
                             id                SERIAL PRIMARY KEY,
                             name              VARCHAR(64) NOT NULL UNIQUE,   -- easy/medium/hard
                             number_of_blocks  INT NOT NULL,
-                            CHECK (number_of_blocks % 2 = 1)                 -- hueco central
+                            time_limit_ms     INT,                           -- NULL = sin límite
+                            layout            JSONB,                         -- tablero inicial [1,1,0,2,2], 3 = obstáculo; NULL = estándar
+                            version           INT NOT NULL DEFAULT 1,        -- sube con cada cambio de tablero o tiempo
+                            retired_at        TIMESTAMPTZ,                   -- retirada: sin partidas nuevas
+                            CHECK (number_of_blocks % 2 = 1),                -- hueco central
+                            CHECK (time_limit_ms IS NULL OR time_limit_ms > 0),
+                            CHECK (layout IS NULL OR jsonb_array_length(layout) = number_of_blocks)
+    );
+
+-- Migración: columnas añadidas después de la primera versión del esquema
+ALTER TABLE difficulty
+    ADD COLUMN IF NOT EXISTS time_limit_ms INT CHECK (time_limit_ms IS NULL OR time_limit_ms > 0),
+    ADD COLUMN IF NOT EXISTS layout        JSONB CHECK (layout IS NULL OR jsonb_array_length(layout) = number_of_blocks),
+    ADD COLUMN IF NOT EXISTS version       INT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS retired_at    TIMESTAMPTZ;
+
+-- -------------------------
+-- Versiones de dificultad: las partidas guardan la versión con la que se jugaron
+-- -------------------------
+CREATE TABLE IF NOT EXISTS difficulty_versions (
+                            difficulty_id     INT NOT NULL REFERENCES difficulty(id),
+                            version           INT NOT NULL,
+                            number_of_blocks  INT NOT NULL,
+                            time_limit_ms     INT,
+                            layout            JSONB,
+                            created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+                            PRIMARY KEY (difficulty_id, version)
+    );
+
+-- Trigger: cada versión nueva de una dificultad queda registrada
+CREATE OR REPLACE FUNCTION _tg_difficulty_snapshot_version()
+RETURNS TRIGGER AS $$
+BEGIN
+INSERT INTO difficulty_versions (difficulty_id, version, number_of_blocks, time_limit_ms, layout)
+VALUES (NEW.id, NEW.version, NEW.number_of_blocks, NEW.time_limit_ms, NEW.layout)
+    ON CONFLICT (difficulty_id, version) DO NOTHING;
+RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS tg_difficulty_snapshot_version ON difficulty;
+CREATE TRIGGER tg_difficulty_snapshot_version
+    AFTER INSERT OR UPDATE OF version ON difficulty
+    FOR EACH ROW EXECUTE FUNCTION _tg_difficulty_snapshot_version();
+
+-- Migración: las dificultades anteriores al trigger también tienen su versión
+INSERT INTO difficulty_versions (difficulty_id, version, number_of_blocks, time_limit_ms, layout)
+SELECT id, version, number_of_blocks, time_limit_ms, layout FROM difficulty
+    ON CONFLICT (difficulty_id, version) DO NOTHING;
+
+-- -------------------------
+-- Currículo: secuencia ordenada de niveles por la que avanza una sesión
+-- -------------------------
+CREATE TABLE IF NOT EXISTS curricula (
+                            id                SERIAL PRIMARY KEY,
+                            name              VARCHAR(64) NOT NULL UNIQUE,
+                            advance_after     INT NOT NULL DEFAULT 1,        -- victorias en un nivel para subir
+                            step_back_after   INT,                           -- fallos seguidos para bajar; NULL = repetir siempre
+                            is_default        BOOLEAN NOT NULL DEFAULT FALSE,
+                            created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+                            CHECK (advance_after > 0),
+                            CHECK (step_back_after IS NULL OR step_back_after > 0)
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_curricula_default
+    ON curricula (is_default)
+    WHERE is_default;
+
+CREATE TABLE IF NOT EXISTS curriculum_levels (
+                            curriculum_id     INT NOT NULL REFERENCES curricula(id) ON DELETE CASCADE,
+                            level_n           INT NOT NULL,                  -- 1, 2, 3...
+                            difficulty_id     INT NOT NULL REFERENCES difficulty(id),
+                            PRIMARY KEY (curriculum_id, level_n),
+                            CHECK (level_n > 0)
     );
 
 -- -------------------------
@@ -19,11 +92,17 @@ CREATE TABLE IF NOT EXISTS sessions (
                           id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
                           player_id    UUID,                 -- opcional
                           device       TEXT,                 -- Quest, etc.
+                          curriculum_id INT REFERENCES curricula(id), -- NULL = currículo por defecto
                           is_finished  BOOLEAN NOT NULL DEFAULT FALSE,
                           started_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
                           ended_at     TIMESTAMPTZ
 );
 
+-- Migración
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS curriculum_id INT REFERENCES curricula(id);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_player ON sessions(player_id);
+
 -- -------------------------
 -- Partida (nivel) dentro de la sesión
 -- -------------------------
@@ -31,26 +110,60 @@ CREATE TABLE IF NOT EXISTS matches (
                          id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
                          session_id     UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
                          difficulty_id  INT  NOT NULL REFERENCES difficulty(id),
-                         level_n        INT  NOT NULL,                -- 3,4,5...
+                         difficulty_version INT NOT NULL,             -- versión vigente al crear la partida
+                         level_n        INT  NOT NULL,                -- posición en el currículo: 1,2,3...
                          is_active      BOOLEAN NOT NULL DEFAULT TRUE,
                          started_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
                          ended_at       TIMESTAMPTZ,
-                         outcome        VARCHAR(16),                  -- win/lose/aborted
-                         meta           JSONB
+                         outcome        VARCHAR(16),                  -- win/lose/aborted/timeout
+                         meta           JSONB,
+                         FOREIGN KEY (difficulty_id, difficulty_version)
+                             REFERENCES difficulty_versions (difficulty_id, version)
 );
 
+-- Migración: las partidas existentes se jugaron con la versión actual
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS difficulty_version INT;
+UPDATE matches m SET difficulty_version = d.version
+FROM difficulty d
+WHERE d.id = m.difficulty_id AND m.difficulty_version IS NULL;
+ALTER TABLE matches ALTER COLUMN difficulty_version SET NOT NULL;
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'matches_difficulty_id_difficulty_version_fkey') THEN
+ALTER TABLE matches ADD CONSTRAINT matches_difficulty_id_difficulty_version_fkey
+    FOREIGN KEY (difficulty_id, difficulty_version) REFERENCES difficulty_versions (difficulty_id, version);
+END IF;
+END;
+$$;
+
+-- Trigger: una partida nueva se juega con la versión vigente de su dificultad
+CREATE OR REPLACE FUNCTION _tg_matches_difficulty_version()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF NEW.difficulty_version IS NULL THEN
+SELECT version INTO NEW.difficulty_version FROM difficulty WHERE id = NEW.difficulty_id;
+END IF;
+RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS tg_matches_difficulty_version ON matches;
+CREATE TRIGGER tg_matches_difficulty_version
+    BEFORE INSERT ON matches
+    FOR EACH ROW EXECUTE FUNCTION _tg_matches_difficulty_version();
+
 CREATE UNIQUE INDEX IF NOT EXISTS ux_matches_one_active_per_session
     ON matches (session_id)
     WHERE is_active;
 
 
-CREATE INDEX idx_matches_session   ON matches(session_id);
-CREATE INDEX idx_matches_active    ON matches(session_id, is_active);
+CREATE INDEX IF NOT EXISTS idx_matches_session   ON matches(session_id);
+CREATE INDEX IF NOT EXISTS idx_matches_active    ON matches(session_id, is_active);
 
 -- -------------------------
 -- Movimientos (append-only)
 -- -------------------------
-CREATE TABLE moves (
+CREATE TABLE IF NOT EXISTS moves (
                        id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
                        match_id      UUID NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
                        seq           INT  NOT NULL,                         -- 1..N
@@ -58,7 +171,7 @@ CREATE TABLE moves (
                        elapsed_ms    INT  NOT NULL,                         -- delta vs. movimiento anterior
                        from_idx      INT  NOT NULL,
                        to_idx        INT  NOT NULL,
-                       move_kind     SMALLINT NOT NULL,                     -- 1=paso, 2=salto
+                       move_kind     SMALLINT NOT NULL,                     -- 1=paso, 2=salto, 3=deshacer, 4=reiniciar
                        frog_side     SMALLINT NOT NULL,                     -- 1=izq, 2=der
                        is_correct    BOOLEAN  NOT NULL DEFAULT TRUE,
                        interruption  BOOLEAN  NOT NULL DEFAULT FALSE,
@@ -74,35 +187,46 @@ CREATE TABLE moves (
                        UNIQUE (match_id, seq)
 );
 
-CREATE INDEX idx_moves_match_seq   ON moves(match_id, seq);
-CREATE INDEX idx_moves_match_time  ON moves(match_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_moves_match_seq   ON moves(match_id, seq);
+CREATE INDEX IF NOT EXISTS idx_moves_match_time  ON moves(match_id, occurred_at);
 
 -- -------------------------
 -- KPIs por partida (se rellenan solos por trigger)
 -- -------------------------
-CREATE TABLE match_stats (
+CREATE TABLE IF NOT EXISTS match_stats (
                              match_id           UUID PRIMARY KEY REFERENCES matches(id) ON DELETE CASCADE,
                              total_moves        INT    NOT NULL DEFAULT 0,
                              errors             INT    NOT NULL DEFAULT 0,
                              avg_time_ms        INT    NOT NULL DEFAULT 0,
                              buclicidad_avg     DOUBLE PRECISION NOT NULL DEFAULT 0,
                              branch_factor_avg  DOUBLE PRECISION NOT NULL DEFAULT 0,
+                             undos              INT    NOT NULL DEFAULT 0,
+                             restarts           INT    NOT NULL DEFAULT 0,
                              computed_at        TIMESTAMPTZ NOT NULL DEFAULT now()
 );
 
+-- Migración
+ALTER TABLE match_stats
+    ADD COLUMN IF NOT EXISTS undos    INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS restarts INT NOT NULL DEFAULT 0;
+
 -- ========== Funciones y triggers ==========
 -- Recalcular y upsert de KPIs por partida
 CREATE OR REPLACE FUNCTION _recompute_match_stats(p_match UUID)
 RETURNS VOID AS $$
 BEGIN
-INSERT INTO match_stats AS ms (match_id, total_moves, errors, avg_time_ms, buclicidad_avg, branch_factor_avg, computed_at)
+-- Deshacer (3) y reiniciar (4) no son movimientos ni errores: se cuentan
+-- aparte. Un deshacer guarda buclicidad = 1 (retroceso explícito).
+INSERT INTO match_stats AS ms (match_id, total_moves, errors, avg_time_ms, buclicidad_avg, branch_factor_avg, undos, restarts, computed_at)
 SELECT
     p_match,
-    COUNT(*)                                        AS total_moves,
-    SUM(CASE WHEN is_correct = FALSE THEN 1 ELSE 0 END) AS errors,
-    COALESCE(ROUND(AVG(elapsed_ms))::INT,0)         AS avg_time_ms,
+    COUNT(*) FILTER (WHERE move_kind IN (1, 2))     AS total_moves,
+    COUNT(*) FILTER (WHERE move_kind IN (1, 2) AND is_correct = FALSE) AS errors,
+    COALESCE(ROUND(AVG(elapsed_ms) FILTER (WHERE move_kind IN (1, 2)))::INT,0) AS avg_time_ms,
     COALESCE(AVG(buclicidad), 0)                    AS buclicidad_avg,
     COALESCE(AVG(branching_factor), 0)              AS branch_factor_avg,
+    COUNT(*) FILTER (WHERE move_kind = 3)           AS undos,
+    COUNT(*) FILTER (WHERE move_kind = 4)           AS restarts,
     now()
 FROM moves
 WHERE match_id = p_match
@@ -112,6 +236,8 @@ WHERE match_id = p_match
                                   avg_time_ms       = EXCLUDED.avg_time_ms,
                                   buclicidad_avg    = EXCLUDED.buclicidad_avg,
                                   branch_factor_avg = EXCLUDED.branch_factor_avg,
+                                  undos             = EXCLUDED.undos,
+                                  restarts          = EXCLUDED.restarts,
                                   computed_at       = EXCLUDED.computed_at;
 END;
 $$ LANGUAGE plpgsql;
@@ -130,6 +256,13 @@ ELSE
     v_match := OLD.match_id;
 END IF;
 
+  -- Los movimientos archivados se borran sin tocar los KPIs de la partida
+  IF (TG_OP = 'DELETE') AND EXISTS (
+    SELECT 1 FROM match_archives WHERE match_id = v_match AND restored_at IS NULL
+  ) THEN
+    RETURN OLD;
+END IF;
+
   PERFORM _recompute_match_stats(v_match);
 RETURN COALESCE(NEW, OLD);
 END;
@@ -167,6 +300,305 @@ RETURN v_id;
 END;
 $$ LANGUAGE plpgsql;
 
-INSERT INTO difficulty (name, number_of_blocks) VALUES ('easy', 7);
-INSERT INTO difficulty (name, number_of_blocks) VALUES ('medium', 9);
-INSERT INTO difficulty (name, number_of_blocks) VALUES ('hard', 11);
+INSERT INTO difficulty (name, number_of_blocks) VALUES ('easy', 7) ON CONFLICT (name) DO NOTHING;
+INSERT INTO difficulty (name, number_of_blocks) VALUES ('medium', 9) ON CONFLICT (name) DO NOTHING;
+INSERT INTO difficulty (name, number_of_blocks) VALUES ('hard', 11) ON CONFLICT (name) DO NOTHING;
+
+-- Currículo por defecto: de menos a más bloques, baja tras dos fallos seguidos
+INSERT INTO curricula (name, step_back_after, is_default) VALUES ('default', 2, TRUE) ON CONFLICT (name) DO NOTHING;
+INSERT INTO curriculum_levels (curriculum_id, level_n, difficulty_id)
+SELECT c.id, ROW_NUMBER() OVER (ORDER BY d.number_of_blocks), d.id
+FROM curricula c, difficulty d
+WHERE c.name = 'default'
+  AND NOT EXISTS (SELECT 1 FROM curriculum_levels l WHERE l.curriculum_id = c.id);
+
+-- -------------------------
+-- Outbox de eventos de dominio (escrito en la misma transacción que la entidad)
+-- -------------------------
+CREATE TABLE IF NOT EXISTS outbox_events (
+                               id               BIGSERIAL PRIMARY KEY,
+                               event_type       VARCHAR(64) NOT NULL,          -- SessionStarted/MatchStarted/MoveRecorded/HintRecorded/MatchFinished/SessionFinished
+                               aggregate_id     UUID NOT NULL,                 -- match o sesión
+                               payload          JSONB NOT NULL,
+                               created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+                               attempts         INT NOT NULL DEFAULT 0,
+                               next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+                               delivered_at     TIMESTAMPTZ,
+                               failed_at        TIMESTAMPTZ,                   -- sin más reintentos (dead letter)
+                               last_error       TEXT
+);
+
+-- Migración
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS failed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending
+    ON outbox_events (next_attempt_at)
+    WHERE delivered_at IS NULL AND failed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_delivered
+    ON outbox_events (delivered_at)
+    WHERE delivered_at IS NOT NULL;
+
+-- -------------------------
+-- Webhooks salientes y registro de entregas
+-- -------------------------
+CREATE TABLE IF NOT EXISTS webhooks (
+                          id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                          url          TEXT NOT NULL,
+                          secret       TEXT NOT NULL,                 -- clave HMAC
+                          event_types  TEXT[] NOT NULL,               -- filtro: MatchFinished/SessionFinished
+                          is_active    BOOLEAN NOT NULL DEFAULT TRUE,
+                          created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+                                    id                BIGSERIAL PRIMARY KEY,
+                                    webhook_id        UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+                                    event_id          BIGINT NOT NULL,               -- outbox_events.id
+                                    event_type        VARCHAR(64) NOT NULL,
+                                    payload           JSONB NOT NULL,
+                                    status            VARCHAR(16) NOT NULL DEFAULT 'pending', -- pending/delivered/failed
+                                    attempts          INT NOT NULL DEFAULT 0,
+                                    next_attempt_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                    last_status_code  INT,
+                                    last_error        TEXT,
+                                    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                    delivered_at      TIMESTAMPTZ,
+                                    UNIQUE (webhook_id, event_id)                    -- el outbox es at-least-once
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due
+    ON webhook_deliveries (next_attempt_at)
+    WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook
+    ON webhook_deliveries (webhook_id, created_at DESC);
+
+-- -------------------------
+-- Cohortes de investigación (aula, franja de edad...) por jugador
+-- -------------------------
+CREATE TABLE IF NOT EXISTS player_cohorts (
+                                id          BIGSERIAL PRIMARY KEY,
+                                player_id   UUID NOT NULL,
+                                cohort      VARCHAR(64) NOT NULL,
+                                created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                UNIQUE (player_id, cohort)
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_cohorts_cohort ON player_cohorts(cohort);
+
+-- -------------------------
+-- Cola de sentencias xAPI pendientes de enviar al LRS
+-- -------------------------
+CREATE TABLE IF NOT EXISTS xapi_statements (
+                                 id               BIGSERIAL PRIMARY KEY,
+                                 statement_id     UUID NOT NULL UNIQUE,            -- id de la sentencia (determinista)
+                                 statement        JSONB NOT NULL,
+                                 status           VARCHAR(16) NOT NULL DEFAULT 'pending', -- pending/sent/failed
+                                 attempts         INT NOT NULL DEFAULT 0,
+                                 next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                 last_error       TEXT,
+                                 created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                 sent_at          TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_xapi_statements_due
+    ON xapi_statements (next_attempt_at)
+    WHERE status = 'pending';
+
+-- -------------------------
+-- Métricas cognitivas por partida (calculadas en Go al cerrar la partida)
+-- -------------------------
+CREATE TABLE IF NOT EXISTS match_metrics (
+                               match_id                    UUID PRIMARY KEY REFERENCES matches(id) ON DELETE CASCADE,
+                               think_outliers              INT NOT NULL DEFAULT 0,   -- pausas largas antes de movimientos correctos
+                               think_outlier_threshold_ms  INT,                      -- Q3 + 1.5·IQR de elapsed_ms
+                               optimal_moves               INT NOT NULL DEFAULT 0,
+                               longest_optimal_streak      INT NOT NULL DEFAULT 0,
+                               avg_recovery_ms             DOUBLE PRECISION,         -- desde un error hasta el siguiente acierto
+                               first_move_latency_ms       INT,
+                               restarts                    INT NOT NULL DEFAULT 0,
+                               jumps                       INT NOT NULL DEFAULT 0,
+                               steps                       INT NOT NULL DEFAULT 0,
+                               jump_ratio                  DOUBLE PRECISION,         -- saltos / (saltos + pasos)
+                               computed_at                 TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- -------------------------
+-- Pistas mostradas durante una partida
+-- -------------------------
+CREATE TABLE IF NOT EXISTS hints (
+                       id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                       match_id     UUID NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+                       seq          INT  NOT NULL,                        -- último movimiento al mostrar la pista
+                       hint_type    VARCHAR(16) NOT NULL,                 -- next_move/frog/strategy
+                       source       VARCHAR(16) NOT NULL,                 -- player/agent
+                       from_idx     INT,                                  -- rana sugerida
+                       to_idx       INT,                                  -- destino sugerido
+                       dismissed    BOOLEAN NOT NULL DEFAULT FALSE,
+                       followed     BOOLEAN,                              -- NULL hasta el siguiente movimiento
+                       created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+                       resolved_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_hints_match_seq ON hints(match_id, seq);
+
+-- -------------------------
+-- Pausas de partida (p. ej. el jugador se quita el visor)
+-- -------------------------
+CREATE TABLE IF NOT EXISTS match_pauses (
+                              id          BIGSERIAL PRIMARY KEY,
+                              match_id    UUID NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+                              paused_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+                              resumed_at  TIMESTAMPTZ,                          -- NULL mientras sigue en pausa
+                              CHECK (resumed_at IS NULL OR resumed_at >= paused_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_match_pauses_match ON match_pauses(match_id, paused_at);
+-- Como mucho una pausa abierta por partida
+CREATE UNIQUE INDEX IF NOT EXISTS ux_match_pauses_open
+    ON match_pauses (match_id)
+    WHERE resumed_at IS NULL;
+
+-- -------------------------
+-- Experimentos (A/B) con brazos y asignaciones deterministas
+-- -------------------------
+CREATE TABLE IF NOT EXISTS experiments (
+                             id           SERIAL PRIMARY KEY,
+                             key          VARCHAR(64) NOT NULL UNIQUE,          -- p. ej. hints-2025
+                             description  TEXT,
+                             unit         VARCHAR(16) NOT NULL,                 -- player/session
+                             is_active    BOOLEAN NOT NULL DEFAULT TRUE,
+                             created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+                             CHECK (unit IN ('player', 'session'))
+);
+
+CREATE TABLE IF NOT EXISTS experiment_arms (
+                                 experiment_id  INT NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+                                 name           VARCHAR(64) NOT NULL,              -- p. ej. no_hints/agent_hints
+                                 weight         INT NOT NULL,                      -- proporción de asignación
+                                 config         JSONB,                             -- parámetros de la condición
+                                 position       INT NOT NULL,                      -- orden de los brazos
+                                 PRIMARY KEY (experiment_id, name),
+                                 CHECK (weight > 0)
+);
+
+CREATE TABLE IF NOT EXISTS experiment_assignments (
+                                        experiment_id  INT NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+                                        unit_id        TEXT NOT NULL,              -- player_id o session_id según la unidad
+                                        arm            VARCHAR(64) NOT NULL,
+                                        assigned_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                        PRIMARY KEY (experiment_id, unit_id),
+                                        FOREIGN KEY (experiment_id, arm) REFERENCES experiment_arms (experiment_id, name)
+);
+
+-- -------------------------
+-- Consentimientos de investigación por jugador (historial; el vigente es el último)
+-- -------------------------
+CREATE TABLE IF NOT EXISTS player_consents (
+                                 id                 BIGSERIAL PRIMARY KEY,
+                                 player_id          UUID NOT NULL,
+                                 form_version       VARCHAR(32) NOT NULL,          -- versión del formulario firmado
+                                 research_consent   BOOLEAN NOT NULL,              -- uso de los datos detallados en investigación
+                                 guardian_consent   BOOLEAN NOT NULL,              -- consentimiento del tutor legal
+                                 guardian_name      VARCHAR(128),
+                                 given_at           TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                 withdrawn_at       TIMESTAMPTZ,
+                                 withdrawal_action  VARCHAR(16),                   -- delete/anonymize
+                                 CHECK (withdrawal_action IN ('delete', 'anonymize')),
+                                 CHECK ((withdrawn_at IS NULL) = (withdrawal_action IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_consents_player ON player_consents(player_id, given_at DESC, id DESC);
+
+-- Trigger: sin consentimiento de investigación vigente no se guardan los tableros
+CREATE OR REPLACE FUNCTION _tg_moves_research_consent()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF NEW.board_before IS NULL AND NEW.board_after IS NULL THEN
+    RETURN NEW;
+END IF;
+  IF NOT COALESCE((
+    SELECT pc.research_consent AND pc.guardian_consent AND pc.withdrawn_at IS NULL
+    FROM matches m
+    JOIN sessions s ON s.id = m.session_id
+    JOIN player_consents pc ON pc.player_id = s.player_id
+    WHERE m.id = NEW.match_id
+    ORDER BY pc.given_at DESC, pc.id DESC
+    LIMIT 1
+  ), FALSE) THEN
+    NEW.board_before := NULL;
+    NEW.board_after := NULL;
+END IF;
+RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS tg_moves_research_consent ON moves;
+CREATE TRIGGER tg_moves_research_consent
+    BEFORE INSERT ON moves
+    FOR EACH ROW EXECUTE FUNCTION _tg_moves_research_consent();
+
+-- -------------------------
+-- Registro de solicitudes de datos personales (exportación, borrado, anonimización)
+-- -------------------------
+CREATE TABLE IF NOT EXISTS privacy_audit (
+                               id                 BIGSERIAL PRIMARY KEY,
+                               player_id          UUID NOT NULL,                -- jugador tal como se solicitó
+                               action             VARCHAR(16) NOT NULL,         -- export/delete/anonymize
+                               reason             VARCHAR(32) NOT NULL,         -- admin_request/consent_withdrawal
+                               actor              VARCHAR(64) NOT NULL,         -- nombre del token de administración
+                               request_id         TEXT,
+                               sessions_affected  INT NOT NULL DEFAULT 0,
+                               created_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+                               CHECK (action IN ('export', 'delete', 'anonymize'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_privacy_audit_player ON privacy_audit(player_id, created_at DESC);
+
+-- -------------------------
+-- Archivo de movimientos antiguos (JSON Lines comprimido en el almacén de objetos)
+-- -------------------------
+-- Sin FK a matches: al borrar la partida el registro queda para eliminar su archivo.
+CREATE TABLE IF NOT EXISTS match_archives (
+                                match_id     UUID PRIMARY KEY,
+                                object_key   TEXT NOT NULL,                 -- p. ej. moves/<match_id>.jsonl.gz
+                                moves        INT NOT NULL,                  -- movimientos archivados
+                                last_seq     INT NOT NULL,                  -- último seq archivado
+                                archived_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                restored_at  TIMESTAMPTZ,                   -- NULL mientras está archivada
+                                clear_boards BOOLEAN NOT NULL DEFAULT FALSE -- tableros por borrar del objeto (anonimización)
+);
+
+-- Migración
+ALTER TABLE match_archives ADD COLUMN IF NOT EXISTS clear_boards BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_match_archives_clear_boards
+    ON match_archives (archived_at)
+    WHERE clear_boards;
+
+-- -------------------------
+-- Auditoría de operaciones administrativas y cambios de estado
+-- -------------------------
+CREATE TABLE IF NOT EXISTS audit_log (
+                           id           BIGSERIAL PRIMARY KEY,
+                           actor        VARCHAR(64) NOT NULL,          -- token de administración, client o system
+                           action       TEXT NOT NULL,                 -- p. ej. match.update, POST /admin/experiments
+                           entity_type  VARCHAR(32) NOT NULL,          -- match, session, experiment...
+                           entity_id    TEXT,
+                           changes      JSONB,                         -- {campo: {before, after}}
+                           request_id   TEXT,
+                           created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created ON audit_log(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity  ON audit_log(entity_type, entity_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor   ON audit_log(actor, created_at DESC);
+
+-- Campos que difieren entre dos filas, como {campo: {"before": ..., "after": ...}}.
+CREATE OR REPLACE FUNCTION _audit_changes(p_before JSONB, p_after JSONB)
+    RETURNS JSONB AS $$
+SELECT COALESCE(
+               jsonb_object_agg(k, jsonb_build_object('before', p_before -> k, 'after', p_after -> k)),
+               '{}'::jsonb)
+FROM jsonb_object_keys(COALESCE(p_before, '{}'::jsonb) || COALESCE(p_after, '{}'::jsonb)) AS k
+WHERE (p_before -> k) IS DISTINCT FROM (p_after -> k);
+$$ LANGUAGE sql IMMUTABLE;
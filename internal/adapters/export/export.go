@@ -0,0 +1,219 @@
+// Package export encodes game data as CSV or Parquet for analysts.
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+type Dataset string
+
+const (
+	Sessions   Dataset = "sessions"
+	Matches    Dataset = "matches"
+	Moves      Dataset = "moves"
+	MatchStats Dataset = "match_stats"
+)
+
+type Format string
+
+const (
+	CSV     Format = "csv"
+	Parquet Format = "parquet"
+)
+
+// parquetBatch is how many rows are buffered before being handed to the
+// Parquet writer, which in turn flushes a row group every parquetRowGroup
+// rows.
+const (
+	parquetBatch    = 1024
+	parquetRowGroup = 64 * 1024
+)
+
+func ParseDataset(v string) (Dataset, error) {
+	switch d := Dataset(v); d {
+	case Sessions, Matches, Moves, MatchStats:
+		return d, nil
+	}
+	return "", fmt.Errorf("unknown dataset %q: want sessions, matches, moves or match_stats", v)
+}
+
+func ParseFormat(v string) (Format, error) {
+	switch f := Format(v); f {
+	case CSV, Parquet:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown format %q: want csv or parquet", v)
+}
+
+func (f Format) ContentType() string {
+	if f == Parquet {
+		return "application/vnd.apache.parquet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// FileName is the suggested file name for a dataset in this format.
+func (f Format) FileName(d Dataset) string {
+	return string(d) + "." + string(f)
+}
+
+// Write streams dataset to w, encoding each row as it is read from the
+// database.
+func Write(ctx context.Context, svc *usecase.ExportService, w io.Writer, d Dataset, f Format, filter entity.ExportFilter) error {
+	switch d {
+	case Sessions:
+		return write(w, f, func(emit func(SessionRow) error) error {
+			return svc.EachSession(ctx, filter, func(s entity.Session) error { return emit(sessionRow(s)) })
+		})
+	case Matches:
+		return write(w, f, func(emit func(MatchRow) error) error {
+			return svc.EachMatch(ctx, filter, func(m entity.Match) error { return emit(matchRow(m)) })
+		})
+	case Moves:
+		return write(w, f, func(emit func(MoveRow) error) error {
+			return svc.EachMove(ctx, filter, func(m entity.Move) error { return emit(moveRow(m)) })
+		})
+	case MatchStats:
+		return write(w, f, func(emit func(MatchKPIRow) error) error {
+			return svc.EachMatchKPI(ctx, filter, func(k entity.MatchKPI) error { return emit(matchKPIRow(k)) })
+		})
+	}
+	return fmt.Errorf("unknown dataset %q", d)
+}
+
+type encoder[T any] interface {
+	Write(row T) error
+	Close() error
+}
+
+func write[T any](w io.Writer, f Format, produce func(emit func(T) error) error) error {
+	var enc encoder[T]
+	if f == Parquet {
+		enc = newParquetEncoder[T](w)
+	} else {
+		enc = newCSVEncoder[T](w)
+	}
+	if err := produce(enc.Write); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// Columns returns the column names of a row type, in order.
+func Columns[T any]() []string {
+	t := reflect.TypeFor[T]()
+	cols := make([]string, t.NumField())
+	for i := range cols {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("parquet"), ",")
+		cols[i] = name
+	}
+	return cols
+}
+
+type csvEncoder[T any] struct {
+	w      *csv.Writer
+	header bool
+	record []string
+}
+
+func newCSVEncoder[T any](w io.Writer) *csvEncoder[T] {
+	return &csvEncoder[T]{w: csv.NewWriter(w)}
+}
+
+func (e *csvEncoder[T]) writeHeader() error {
+	if e.header {
+		return nil
+	}
+	e.header = true
+	return e.w.Write(Columns[T]())
+}
+
+func (e *csvEncoder[T]) Write(row T) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	v := reflect.ValueOf(row)
+	e.record = e.record[:0]
+	for i := range v.NumField() {
+		e.record = append(e.record, csvValue(v.Field(i)))
+	}
+	return e.w.Write(e.record)
+}
+
+func (e *csvEncoder[T]) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func csvValue(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	switch x := v.Interface().(type) {
+	case time.Time:
+		return x.UTC().Format(time.RFC3339Nano)
+	case string:
+		return x
+	case bool:
+		return strconv.FormatBool(x)
+	case int:
+		return strconv.Itoa(x)
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64)
+	}
+	panic(fmt.Sprintf("export: unsupported column type %s", v.Type()))
+}
+
+type parquetEncoder[T any] struct {
+	w     *parquet.GenericWriter[T]
+	batch []T
+}
+
+func newParquetEncoder[T any](w io.Writer) *parquetEncoder[T] {
+	return &parquetEncoder[T]{
+		w:     parquet.NewGenericWriter[T](w, parquet.MaxRowsPerRowGroup(parquetRowGroup)),
+		batch: make([]T, 0, parquetBatch),
+	}
+}
+
+func (e *parquetEncoder[T]) Write(row T) error {
+	e.batch = append(e.batch, row)
+	if len(e.batch) < parquetBatch {
+		return nil
+	}
+	return e.flush()
+}
+
+func (e *parquetEncoder[T]) flush() error {
+	if len(e.batch) == 0 {
+		return nil
+	}
+	_, err := e.w.Write(e.batch)
+	e.batch = e.batch[:0]
+	return err
+}
+
+func (e *parquetEncoder[T]) Close() error {
+	if err := e.flush(); err != nil {
+		return err
+	}
+	return e.w.Close()
+}
@@ -0,0 +1,96 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+type stubExportRepo struct {
+	moves []entity.Move
+}
+
+func (r stubExportRepo) EachSession(ctx context.Context, filter entity.ExportFilter, fn func(entity.Session) error) error {
+	return nil
+}
+
+func (r stubExportRepo) EachMatch(ctx context.Context, filter entity.ExportFilter, fn func(entity.Match) error) error {
+	return nil
+}
+
+func (r stubExportRepo) EachMove(ctx context.Context, filter entity.ExportFilter, fn func(entity.Move) error) error {
+	for _, mv := range r.moves {
+		if err := fn(mv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r stubExportRepo) EachMatchKPI(ctx context.Context, filter entity.ExportFilter, fn func(entity.MatchKPI) error) error {
+	return nil
+}
+
+func testMoves() []entity.Move {
+	branching := 3
+	at := time.Date(2025, 3, 1, 10, 0, 0, 500_000_000, time.UTC)
+	return []entity.Move{
+		{ID: "mv-1", MatchID: "m-1", Seq: 1, OccurredAt: at, ElapsedMs: 900, FromIdx: 2, ToIdx: 3,
+			MoveKind: 1, FrogSide: 1, IsCorrect: true, BoardAfter: json.RawMessage(`[1,1,0,1,2,2,2]`), BranchingFactor: &branching},
+		{ID: "mv-2", MatchID: "m-1", Seq: 2, OccurredAt: at.Add(time.Second), ElapsedMs: 1000, FromIdx: 4, ToIdx: 2,
+			MoveKind: 2, FrogSide: 2, Interruption: true},
+	}
+}
+
+func TestWriteMovesCSV(t *testing.T) {
+	svc := usecase.NewExportService(stubExportRepo{moves: testMoves()})
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(context.Background(), svc, &buf, Moves, CSV, entity.ExportFilter{}))
+	require.Equal(t,
+		"id,match_id,seq,occurred_at,elapsed_ms,from_idx,to_idx,move_kind,frog_side,is_correct,interruption,board_before,board_after,branching_factor,buclicidad\n"+
+			`mv-1,m-1,1,2025-03-01T10:00:00.5Z,900,2,3,1,1,true,false,,"[1,1,0,1,2,2,2]",3,`+"\n"+
+			"mv-2,m-1,2,2025-03-01T10:00:01.5Z,1000,4,2,2,2,false,true,,,,\n",
+		buf.String())
+}
+
+func TestWriteEmptyCSVKeepsHeader(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(context.Background(), usecase.NewExportService(stubExportRepo{}), &buf, MatchStats, CSV, entity.ExportFilter{}))
+	require.Equal(t, "match_id,total_moves,errors,avg_time_ms,buclicidad_avg,branch_factor_avg,undos,restarts,computed_at\n", buf.String())
+}
+
+func TestWriteMovesParquet(t *testing.T) {
+	svc := usecase.NewExportService(stubExportRepo{moves: testMoves()})
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(context.Background(), svc, &buf, Moves, Parquet, entity.ExportFilter{}))
+
+	rows, err := parquet.Read[MoveRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, moveRow(testMoves()[0]), rows[0])
+	require.Nil(t, rows[1].BoardAfter)
+	require.Nil(t, rows[1].BranchingFactor)
+}
+
+func TestColumnsMatchEntityJSON(t *testing.T) {
+	raw, err := json.Marshal(entity.MatchKPI{})
+	require.NoError(t, err)
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(raw, &fields))
+
+	cols := Columns[MatchKPIRow]()
+	require.Len(t, cols, len(fields))
+	for _, c := range cols {
+		require.Contains(t, fields, c)
+	}
+}
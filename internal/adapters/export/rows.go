@@ -0,0 +1,143 @@
+package export
+
+import (
+	"time"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+// Row types fix the column schema of every dataset. Column names and order
+// follow the JSON fields of the matching entity and are the same in CSV and
+// Parquet. JSON columns (meta, boards) are exported as JSON text; timestamps
+// are UTC, RFC 3339 in CSV and microsecond timestamps in Parquet. Nullable
+// columns are empty in CSV and optional in Parquet.
+
+type SessionRow struct {
+	ID           string     `parquet:"id"`
+	PlayerID     *string    `parquet:"player_id,optional"`
+	Device       *string    `parquet:"device,optional"`
+	CurriculumID *int       `parquet:"curriculum_id,optional"`
+	IsFinished   bool       `parquet:"is_finished"`
+	StartedAt    time.Time  `parquet:"started_at,timestamp(microsecond)"`
+	EndedAt      *time.Time `parquet:"ended_at,optional,timestamp(microsecond)"`
+}
+
+type MatchRow struct {
+	ID                string     `parquet:"id"`
+	SessionID         string     `parquet:"session_id"`
+	DifficultyID      int        `parquet:"difficulty_id"`
+	DifficultyVersion int        `parquet:"difficulty_version"`
+	LevelN            int        `parquet:"level_n"`
+	IsActive          bool       `parquet:"is_active"`
+	StartedAt         time.Time  `parquet:"started_at,timestamp(microsecond)"`
+	EndedAt           *time.Time `parquet:"ended_at,optional,timestamp(microsecond)"`
+	Outcome           *string    `parquet:"outcome,optional"`
+	Meta              *string    `parquet:"meta,optional"`
+}
+
+type MoveRow struct {
+	ID              string    `parquet:"id"`
+	MatchID         string    `parquet:"match_id"`
+	Seq             int       `parquet:"seq"`
+	OccurredAt      time.Time `parquet:"occurred_at,timestamp(microsecond)"`
+	ElapsedMs       int       `parquet:"elapsed_ms"`
+	FromIdx         int       `parquet:"from_idx"`
+	ToIdx           int       `parquet:"to_idx"`
+	MoveKind        int       `parquet:"move_kind"`
+	FrogSide        int       `parquet:"frog_side"`
+	IsCorrect       bool      `parquet:"is_correct"`
+	Interruption    bool      `parquet:"interruption"`
+	BoardBefore     *string   `parquet:"board_before,optional"`
+	BoardAfter      *string   `parquet:"board_after,optional"`
+	BranchingFactor *int      `parquet:"branching_factor,optional"`
+	Buclicidad      *float64  `parquet:"buclicidad,optional"`
+}
+
+type MatchKPIRow struct {
+	MatchID         string    `parquet:"match_id"`
+	TotalMoves      int       `parquet:"total_moves"`
+	Errors          int       `parquet:"errors"`
+	AvgTimeMs       int       `parquet:"avg_time_ms"`
+	BuclicidadAvg   float64   `parquet:"buclicidad_avg"`
+	BranchFactorAvg float64   `parquet:"branch_factor_avg"`
+	Undos           int       `parquet:"undos"`
+	Restarts        int       `parquet:"restarts"`
+	ComputedAt      time.Time `parquet:"computed_at,timestamp(microsecond)"`
+}
+
+func sessionRow(s entity.Session) SessionRow {
+	return SessionRow{
+		ID:           s.ID,
+		PlayerID:     s.PlayerID,
+		Device:       s.Device,
+		CurriculumID: s.CurriculumID,
+		IsFinished:   s.IsFinished,
+		StartedAt:    s.StartedAt.UTC(),
+		EndedAt:      utc(s.EndedAt),
+	}
+}
+
+func matchRow(m entity.Match) MatchRow {
+	return MatchRow{
+		ID:                m.ID,
+		SessionID:         m.SessionID,
+		DifficultyID:      m.DifficultyID,
+		DifficultyVersion: m.DifficultyVersion,
+		LevelN:            m.LevelN,
+		IsActive:          m.IsActive,
+		StartedAt:         m.StartedAt.UTC(),
+		EndedAt:           utc(m.EndedAt),
+		Outcome:           m.Outcome,
+		Meta:              text(m.Meta),
+	}
+}
+
+func moveRow(m entity.Move) MoveRow {
+	return MoveRow{
+		ID:              m.ID,
+		MatchID:         m.MatchID,
+		Seq:             m.Seq,
+		OccurredAt:      m.OccurredAt.UTC(),
+		ElapsedMs:       m.ElapsedMs,
+		FromIdx:         m.FromIdx,
+		ToIdx:           m.ToIdx,
+		MoveKind:        int(m.MoveKind),
+		FrogSide:        int(m.FrogSide),
+		IsCorrect:       m.IsCorrect,
+		Interruption:    m.Interruption,
+		BoardBefore:     text(m.BoardBefore),
+		BoardAfter:      text(m.BoardAfter),
+		BranchingFactor: m.BranchingFactor,
+		Buclicidad:      m.Buclicidad,
+	}
+}
+
+func matchKPIRow(k entity.MatchKPI) MatchKPIRow {
+	return MatchKPIRow{
+		MatchID:         k.MatchID,
+		TotalMoves:      k.TotalMoves,
+		Errors:          k.Errors,
+		AvgTimeMs:       k.AvgTimeMs,
+		BuclicidadAvg:   k.BuclicidadAvg,
+		BranchFactorAvg: k.BranchFactorAvg,
+		Undos:           k.Undos,
+		Restarts:        k.Restarts,
+		ComputedAt:      k.ComputedAt.UTC(),
+	}
+}
+
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+func text(raw []byte) *string {
+	if len(raw) == 0 {
+		return nil
+	}
+	s := string(raw)
+	return &s
+}
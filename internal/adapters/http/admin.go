@@ -0,0 +1,71 @@
+package httpadapter
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/org/ranas-bdi-backend/internal/platform/logging"
+)
+
+const actorKey = "admin_actor"
+
+var errUnauthorized = errors.New("missing or invalid admin token")
+
+// ParseAdminTokens parses ADMIN_TOKENS, a comma separated list of name:token
+// pairs, into a map from token to the operator name recorded as the actor.
+func ParseAdminTokens(raw string) (map[string]string, error) {
+	tokens := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, token, ok := strings.Cut(pair, ":")
+		name, token = strings.TrimSpace(name), strings.TrimSpace(token)
+		if !ok || name == "" || token == "" {
+			return nil, fmt.Errorf("invalid admin token entry %q: want name:token", pair)
+		}
+		if _, dup := tokens[token]; dup {
+			return nil, fmt.Errorf("admin token for %q is reused", name)
+		}
+		tokens[token] = name
+	}
+	return tokens, nil
+}
+
+// WithAdminTokens enables the /admin routes for callers presenting one of the
+// tokens as a bearer token.
+func WithAdminTokens(tokens map[string]string) Option {
+	return func(h *Handler) { h.adminTokens = tokens }
+}
+
+func (h *Handler) adminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		presented, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		actor := ""
+		if ok && presented != "" {
+			// Compare against every token so timing does not reveal which
+			// prefix matched.
+			for token, name := range h.adminTokens {
+				if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) == 1 {
+					actor = name
+				}
+			}
+		}
+		if actor == "" {
+			respondError(c, http.StatusUnauthorized, errUnauthorized)
+			c.Abort()
+			return
+		}
+
+		c.Set(actorKey, actor)
+		ctx := logging.WithActor(c.Request.Context(), actor)
+		c.Request = c.Request.WithContext(logging.With(ctx, "actor", actor))
+		c.Next()
+	}
+}
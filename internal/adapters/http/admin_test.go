@@ -0,0 +1,55 @@
+package httpadapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAdminTokens(t *testing.T) {
+	tokens, err := ParseAdminTokens(" alice:t1, bob:t2 ,")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"t1": "alice", "t2": "bob"}, tokens)
+
+	_, err = ParseAdminTokens("alice")
+	require.Error(t, err)
+	_, err = ParseAdminTokens("alice:t1,bob:t1")
+	require.Error(t, err)
+}
+
+func TestAdminMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &Handler{router: gin.New(), adminTokens: map[string]string{"t1": "alice"}}
+	h.router.GET("/admin/ping", h.adminMiddleware(), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString(actorKey))
+	})
+
+	cases := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{name: "missing", status: http.StatusUnauthorized},
+		{name: "wrong token", header: "Bearer nope", status: http.StatusUnauthorized},
+		{name: "not bearer", header: "t1", status: http.StatusUnauthorized},
+		{name: "valid", header: "Bearer t1", status: http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin/ping", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			rec := httptest.NewRecorder()
+			h.router.ServeHTTP(rec, req)
+			require.Equal(t, tc.status, rec.Code)
+			if tc.status == http.StatusOK {
+				require.Equal(t, "alice", rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+var errInvalidPlayerID = errors.New("player id must be a valid UUID")
+
+// WithAnalytics enables the learning analytics routes.
+func WithAnalytics(analytics *usecase.AnalyticsService) Option {
+	return func(h *Handler) { h.analytics = analytics }
+}
+
+func (h *Handler) handlePlayerProgress(c *gin.Context) {
+	playerID := c.Param("playerID")
+	if _, err := uuid.Parse(playerID); err != nil {
+		respondError(c, http.StatusBadRequest, errInvalidPlayerID)
+		return
+	}
+
+	var filter entity.ProgressFilter
+	filter.Experiment, filter.Arm = parseArmQuery(c)
+
+	progress, err := h.analytics.PlayerProgress(c.Request.Context(), playerID, filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrPlayerNotFound):
+			respondError(c, http.StatusNotFound, err)
+		case errors.Is(err, usecase.ErrMissingExperiment):
+			respondError(c, http.StatusBadRequest, err)
+		default:
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, progress)
+}
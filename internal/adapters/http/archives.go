@@ -0,0 +1,66 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+)
+
+var errMatchArchived = errors.New("match moves are archived; restore the match first")
+
+// WithRetention enables the admin routes archiving and restoring the moves of
+// a match.
+func WithRetention(retention *usecase.RetentionService) Option {
+	return func(h *Handler) { h.retention = retention }
+}
+
+func (h *Handler) handleArchiveMatch(c *gin.Context) {
+	archive, err := h.retention.Archive(c.Request.Context(), c.Param("matchID"))
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			respondError(c, http.StatusNotFound, err)
+		case errors.Is(err, usecase.ErrMatchStillActive), errors.Is(err, usecase.ErrNothingToArchive):
+			respondError(c, http.StatusConflict, err)
+		default:
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, archive)
+}
+
+func (h *Handler) handleRestoreMatch(c *gin.Context) {
+	archive, err := h.retention.Restore(c.Request.Context(), c.Param("matchID"))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(c, http.StatusNotFound, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, archive)
+}
+
+// rejectArchived answers 409 when the moves of the match are archived, so a
+// replay is not silently served without them.
+func (h *Handler) rejectArchived(c *gin.Context, matchID string) bool {
+	if h.retention == nil {
+		return false
+	}
+	archived, err := h.retention.Archived(c.Request.Context(), matchID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return true
+	}
+	if archived {
+		respondError(c, http.StatusConflict, errMatchArchived)
+		return true
+	}
+	return false
+}
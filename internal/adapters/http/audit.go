@@ -0,0 +1,130 @@
+package httpadapter
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+// WithAudit records successful admin requests that change state and exposes
+// the audit log on GET /audit.
+func WithAudit(audit *usecase.AuditService) Option {
+	return func(h *Handler) { h.audit = audit }
+}
+
+// auditIDKey holds the ID of the entity a handler created, since create
+// routes have no ID in the path.
+const auditIDKey = "audit_entity_id"
+
+// setAuditID names the entity the request created in its audit entry.
+func setAuditID(c *gin.Context, id string) {
+	c.Set(auditIDKey, id)
+}
+
+// auditMiddleware records each admin request that changes state and
+// succeeded, by route: the action is the method and route template and the
+// target is the last ID in the path, e.g. experiment 3 for
+// DELETE /admin/experiments/3. Create routes record the entity the handler
+// set with setAuditID, e.g. experiment 4 for POST /admin/experiments.
+func (h *Handler) auditMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return
+		}
+		if c.Writer.Status() >= http.StatusBadRequest || c.FullPath() == "" {
+			return
+		}
+		entityType, entityID := auditTarget(c)
+		ctx := c.Request.Context()
+		if _, err := h.audit.Record(ctx, c.Request.Method+" "+c.FullPath(), entityType, entityID); err != nil {
+			slog.WarnContext(ctx, "failed to record audit entry", "error", err)
+		}
+	}
+}
+
+// auditTarget returns the entity type and ID of an admin request: the entity
+// the handler created, named after the route's collection, or else the last
+// ID in the path.
+func auditTarget(c *gin.Context) (string, *string) {
+	collection := singular(path.Base(c.FullPath()))
+	if id := c.GetString(auditIDKey); id != "" {
+		return collection, &id
+	}
+	if n := len(c.Params); n > 0 {
+		last := c.Params[n-1]
+		return strings.TrimSuffix(last.Key, "ID"), &last.Value
+	}
+	return collection, nil
+}
+
+// singular turns a collection name such as difficulties into the name of
+// its entities, which audit entries and path IDs use.
+func singular(collection string) string {
+	switch {
+	case collection == "curricula":
+		return "curriculum"
+	case strings.HasSuffix(collection, "ies"):
+		return strings.TrimSuffix(collection, "ies") + "y"
+	default:
+		return strings.TrimSuffix(collection, "s")
+	}
+}
+
+func (h *Handler) handleListAudit(c *gin.Context) {
+	var (
+		filter entity.AuditFilter
+		err    error
+	)
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	filter.Actor = optionalQuery(c, "actor")
+	filter.Action = optionalQuery(c, "action")
+	filter.EntityType = optionalQuery(c, "entity_type")
+	filter.EntityID = optionalQuery(c, "entity_id")
+	filter.RequestID = optionalQuery(c, "request_id")
+	filter.Limit = defaultAuditLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxAuditLimit {
+			respondError(c, http.StatusBadRequest, errInvalidAuditLimit)
+			return
+		}
+		filter.Limit = n
+	}
+
+	entries, err := h.audit.List(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidAuditRange) {
+			respondError(c, http.StatusBadRequest, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// optionalQuery returns the query parameter key, or nil when it is empty.
+func optionalQuery(c *gin.Context, key string) *string {
+	if v := c.Query(key); v != "" {
+		return &v
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+type recordingAuditRepo struct {
+	entries []entity.AuditEntry
+}
+
+func (r *recordingAuditRepo) Record(ctx context.Context, entry entity.AuditEntry) (entity.AuditEntry, error) {
+	r.entries = append(r.entries, entry)
+	return entry, nil
+}
+
+func (r *recordingAuditRepo) List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error) {
+	return r.entries, nil
+}
+
+func TestAuditMiddlewareRecordsAdminChanges(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &recordingAuditRepo{}
+	h := &Handler{
+		router:      gin.New(),
+		adminTokens: map[string]string{"t1": "alice"},
+		audit:       usecase.NewAuditService(repo),
+	}
+	h.router.Use(requestIDMiddleware())
+	admin := h.router.Group("/admin", h.adminMiddleware(), h.auditMiddleware())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	admin.GET("/experiments", ok)
+	admin.POST("/experiments", func(c *gin.Context) {
+		setAuditID(c, "4")
+		c.Status(http.StatusCreated)
+	})
+	admin.POST("/curricula", ok)
+	admin.DELETE("/experiments/:experimentID", ok)
+	admin.POST("/matches/:matchID/archive", func(c *gin.Context) { c.Status(http.StatusConflict) })
+
+	for _, r := range []struct{ method, target string }{
+		{http.MethodGet, "/admin/experiments"},
+		{http.MethodPost, "/admin/experiments"},
+		{http.MethodPost, "/admin/curricula"},
+		{http.MethodDelete, "/admin/experiments/3"},
+		{http.MethodPost, "/admin/matches/m-1/archive"},
+	} {
+		req := httptest.NewRequest(r.method, r.target, nil)
+		req.Header.Set("Authorization", "Bearer t1")
+		req.Header.Set(requestIDHeader, "req-1")
+		h.router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	require.Len(t, repo.entries, 3)
+	created := repo.entries[0]
+	require.Equal(t, "POST /admin/experiments", created.Action)
+	require.Equal(t, "experiment", created.EntityType, "the type is the entity, not the collection")
+	require.Equal(t, "4", *created.EntityID)
+
+	require.Equal(t, "curriculum", repo.entries[1].EntityType)
+	require.Nil(t, repo.entries[1].EntityID, "no ID when the handler sets none")
+
+	deleted := repo.entries[2]
+	require.Equal(t, "alice", deleted.Actor)
+	require.Equal(t, "DELETE /admin/experiments/:experimentID", deleted.Action)
+	require.Equal(t, "experiment", deleted.EntityType)
+	require.Equal(t, "3", *deleted.EntityID)
+	require.Equal(t, "req-1", *deleted.RequestID)
+}
+
+func TestAuditRecordsCreatedEntityID(t *testing.T) {
+	repo := &recordingAuditRepo{}
+	store := newMemoryStore()
+	h := newMemoryHandler(store,
+		WithAdminTokens(map[string]string{"t1": "alice"}),
+		WithAudit(usecase.NewAuditService(repo)),
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/difficulties",
+		strings.NewReader(`{"name":"tiny","number_of_blocks":5}`))
+	req.Header.Set("Authorization", "Bearer t1")
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var created entity.Difficulty
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	require.NotZero(t, created.ID)
+	require.Len(t, repo.entries, 1)
+	require.Equal(t, "difficulty", repo.entries[0].EntityType)
+	require.Equal(t, strconv.Itoa(created.ID), *repo.entries[0].EntityID)
+}
@@ -0,0 +1,43 @@
+package httpadapter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+// handleUndoMove records an undo of the last frog move still on the board.
+func (h *Handler) handleUndoMove(c *gin.Context) {
+	h.boardAction(c, h.moves.Undo)
+}
+
+// handleRestartMatch records a restart, putting the board back to the
+// initial position.
+func (h *Handler) handleRestartMatch(c *gin.Context) {
+	h.boardAction(c, h.moves.Restart)
+}
+
+type boardActionFunc func(ctx context.Context, match entity.Match, difficulty entity.Difficulty) (entity.Move, error)
+
+func (h *Handler) boardAction(c *gin.Context, action boardActionFunc) {
+	match, difficulty, ok := h.playableMatch(c)
+	if !ok {
+		return
+	}
+
+	created, err := action(c.Request.Context(), match, difficulty)
+	if err != nil {
+		if errors.Is(err, usecase.ErrBoardAtStart) || errors.Is(err, usecase.ErrMatchPaused) {
+			respondError(c, http.StatusConflict, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
@@ -0,0 +1,103 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+// WithConsents enables the admin routes recording and withdrawing research
+// consent.
+func WithConsents(consents *usecase.ConsentService) Option {
+	return func(h *Handler) { h.consents = consents }
+}
+
+type recordConsentRequest struct {
+	FormVersion     string  `json:"form_version" binding:"required"`
+	ResearchConsent bool    `json:"research_consent"`
+	GuardianConsent bool    `json:"guardian_consent"`
+	GuardianName    *string `json:"guardian_name"`
+}
+
+// withdrawConsentRequest says what happens to the player's history: "delete"
+// or "anonymize".
+type withdrawConsentRequest struct {
+	Action string `json:"action" binding:"required"`
+}
+
+var errNoConsentInForce = errors.New("player has no consent in force")
+
+func (h *Handler) handleListConsents(c *gin.Context) {
+	playerID, ok := playerIDParam(c)
+	if !ok {
+		return
+	}
+	consents, err := h.consents.History(c.Request.Context(), playerID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, consents)
+}
+
+func (h *Handler) handleRecordConsent(c *gin.Context) {
+	playerID, ok := playerIDParam(c)
+	if !ok {
+		return
+	}
+	var req recordConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	consent, err := h.consents.Record(c.Request.Context(), entity.Consent{
+		PlayerID:        playerID,
+		FormVersion:     req.FormVersion,
+		ResearchConsent: req.ResearchConsent,
+		GuardianConsent: req.GuardianConsent,
+		GuardianName:    req.GuardianName,
+	})
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidConsent) {
+			respondError(c, http.StatusBadRequest, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, consent)
+}
+
+func (h *Handler) handleWithdrawConsent(c *gin.Context) {
+	playerID, ok := playerIDParam(c)
+	if !ok {
+		return
+	}
+	var req withdrawConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	request := privacyRequest(c, playerID)
+	request.Action = req.Action
+	consent, audit, err := h.consents.Withdraw(c.Request.Context(), request)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidConsent):
+			respondError(c, http.StatusBadRequest, err)
+		case errors.Is(err, pgx.ErrNoRows):
+			respondError(c, http.StatusNotFound, errNoConsentInForce)
+		default:
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"consent": consent, "audit": audit})
+}
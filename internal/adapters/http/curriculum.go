@@ -0,0 +1,129 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+// WithCurricula enables level progression: new matches get their level_n,
+// and by default their difficulty, from the session's curriculum.
+func WithCurricula(curricula *usecase.CurriculumService) Option {
+	return func(h *Handler) { h.curricula = curricula }
+}
+
+var errDifficultyNotOnLevel = errors.New("difficulty_id is not the difficulty of the session's curriculum level")
+
+type createCurriculumRequest struct {
+	Name          string `json:"name" binding:"required"`
+	AdvanceAfter  int    `json:"advance_after"`
+	StepBackAfter *int   `json:"step_back_after"`
+	// DifficultyIDs lists the difficulty of each level, first level first.
+	DifficultyIDs []int `json:"difficulty_ids" binding:"required"`
+}
+
+func (h *Handler) handleGetCurriculumPosition(c *gin.Context) {
+	position, err := h.curricula.Position(c.Request.Context(), c.Param("sessionID"))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(c, http.StatusNotFound, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, position)
+}
+
+func (h *Handler) handleListCurricula(c *gin.Context) {
+	curricula, err := h.curricula.List(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, curricula)
+}
+
+func (h *Handler) handleCreateCurriculum(c *gin.Context) {
+	var req createCurriculumRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	curriculum := entity.Curriculum{
+		Name:          req.Name,
+		AdvanceAfter:  req.AdvanceAfter,
+		StepBackAfter: req.StepBackAfter,
+	}
+	for _, id := range req.DifficultyIDs {
+		curriculum.Levels = append(curriculum.Levels, entity.CurriculumLevel{DifficultyID: id})
+	}
+	created, err := h.curricula.Create(c.Request.Context(), curriculum)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidCurriculum) {
+			respondError(c, http.StatusBadRequest, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	setAuditID(c, strconv.Itoa(created.ID))
+	c.JSON(http.StatusCreated, created)
+}
+
+// checkCurriculum answers 404 when a new session asks for a curriculum that
+// does not exist. Without curricula enabled the ID is stored as given.
+func (h *Handler) checkCurriculum(c *gin.Context, id *int) bool {
+	if id == nil || h.curricula == nil {
+		return true
+	}
+	if _, err := h.curricula.Get(c.Request.Context(), *id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(c, http.StatusNotFound, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return false
+	}
+	return true
+}
+
+// nextLevel returns the level_n of a new match in the session of req and
+// fills in its difficulty when the client left it out. A difficulty other
+// than the one of the session's level is refused, since the match would be
+// recorded on the wrong level. Without curricula every match is played on
+// the default level.
+func (h *Handler) nextLevel(c *gin.Context, req *createMatchRequest) (int, bool) {
+	if h.curricula == nil {
+		if req.DifficultyID == 0 {
+			respondError(c, http.StatusBadRequest, errMissingDifficultyID)
+			return 0, false
+		}
+		return h.defaultLevel, true
+	}
+	position, err := h.curricula.Position(c.Request.Context(), req.SessionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(c, http.StatusNotFound, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return 0, false
+	}
+	switch req.DifficultyID {
+	case 0:
+		req.DifficultyID = position.DifficultyID
+	case position.DifficultyID:
+	default:
+		respondError(c, http.StatusConflict, errDifficultyNotOnLevel)
+		return 0, false
+	}
+	return position.LevelN, true
+}
@@ -0,0 +1,126 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+// difficultyRequest defines a difficulty. Layout is the starting board as an
+// array of cells (0 empty, 1 left frog, 2 right frog, 3 obstacle); without
+// it the standard board for NumberOfBlocks is used.
+type difficultyRequest struct {
+	Name           string          `json:"name" binding:"required"`
+	NumberOfBlocks int             `json:"number_of_blocks"`
+	TimeLimitMs    *int            `json:"time_limit_ms"`
+	Layout         json.RawMessage `json:"layout"`
+}
+
+func (r difficultyRequest) difficulty(id int) entity.Difficulty {
+	return entity.Difficulty{
+		ID:             id,
+		Name:           r.Name,
+		NumberOfBlocks: r.NumberOfBlocks,
+		TimeLimitMs:    r.TimeLimitMs,
+		Layout:         r.Layout,
+	}
+}
+
+func (h *Handler) handleListDifficulties(c *gin.Context) {
+	difficulties, err := h.difficulties.GetAll(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, difficulties)
+}
+
+func (h *Handler) handleCreateDifficulty(c *gin.Context) {
+	var req difficultyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	created, err := h.difficulties.Create(c.Request.Context(), req.difficulty(0))
+	if err != nil {
+		respondDifficultyError(c, err)
+		return
+	}
+	setAuditID(c, strconv.Itoa(created.ID))
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *Handler) handleUpdateDifficulty(c *gin.Context) {
+	id, ok := difficultyIDParam(c)
+	if !ok {
+		return
+	}
+	var req difficultyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	updated, err := h.difficulties.Update(c.Request.Context(), req.difficulty(id))
+	if err != nil {
+		respondDifficultyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *Handler) handleRetireDifficulty(c *gin.Context) {
+	id, ok := difficultyIDParam(c)
+	if !ok {
+		return
+	}
+	retired, err := h.difficulties.Retire(c.Request.Context(), id)
+	if err != nil {
+		respondDifficultyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, retired)
+}
+
+func (h *Handler) handleListDifficultyVersions(c *gin.Context) {
+	id, ok := difficultyIDParam(c)
+	if !ok {
+		return
+	}
+	versions, err := h.difficulties.Versions(c.Request.Context(), id)
+	if err != nil {
+		respondDifficultyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, versions)
+}
+
+func difficultyIDParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("difficultyID"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, errInvalidDifficultyID)
+		return 0, false
+	}
+	return id, true
+}
+
+func respondDifficultyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidDifficulty):
+		respondError(c, http.StatusBadRequest, err)
+	case errors.Is(err, usecase.ErrDifficultyRetired):
+		respondError(c, http.StatusConflict, err)
+	case errors.Is(err, pgx.ErrNoRows):
+		respondError(c, http.StatusNotFound, err)
+	default:
+		respondError(c, http.StatusInternalServerError, err)
+	}
+}
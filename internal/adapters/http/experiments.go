@@ -0,0 +1,133 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+// WithExperiments assigns new sessions to the arms of the active experiments
+// and tags the matches they start with those arms.
+func WithExperiments(experiments *usecase.ExperimentService) Option {
+	return func(h *Handler) { h.experiments = experiments }
+}
+
+type createExperimentRequest struct {
+	Key         string                 `json:"key" binding:"required"`
+	Description *string                `json:"description"`
+	Unit        string                 `json:"unit"`
+	Arms        []experimentArmRequest `json:"arms" binding:"required"`
+}
+
+type experimentArmRequest struct {
+	Name   string          `json:"name" binding:"required"`
+	Weight int             `json:"weight"`
+	Config json.RawMessage `json:"config"`
+}
+
+// sessionResponse is a created session along with the experiment arms it was
+// assigned to.
+type sessionResponse struct {
+	entity.Session
+	Experiments []entity.ExperimentAssignment `json:"experiments"`
+}
+
+var errInvalidExperimentID = errors.New("experiment id must be an integer")
+
+func (h *Handler) handleListExperiments(c *gin.Context) {
+	experiments, err := h.experiments.List(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, experiments)
+}
+
+func (h *Handler) handleCreateExperiment(c *gin.Context) {
+	var req createExperimentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	experiment := entity.Experiment{
+		Key:         req.Key,
+		Description: req.Description,
+		Unit:        req.Unit,
+	}
+	for _, arm := range req.Arms {
+		experiment.Arms = append(experiment.Arms, entity.ExperimentArm{
+			Name:   arm.Name,
+			Weight: arm.Weight,
+			Config: arm.Config,
+		})
+	}
+	created, err := h.experiments.Create(c.Request.Context(), experiment)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidExperiment) {
+			respondError(c, http.StatusBadRequest, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	setAuditID(c, strconv.Itoa(created.ID))
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *Handler) handleDeactivateExperiment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("experimentID"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, errInvalidExperimentID)
+		return
+	}
+	experiment, err := h.experiments.Deactivate(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(c, http.StatusNotFound, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, experiment)
+}
+
+// respondSession answers a created session. With experiments enabled the
+// session is assigned right away so clients can apply its conditions. The
+// session is already stored, so a failed assignment only leaves the list
+// empty; the session is assigned again when it starts a match.
+func (h *Handler) respondSession(c *gin.Context, session entity.Session) {
+	if h.experiments == nil {
+		c.JSON(http.StatusCreated, session)
+		return
+	}
+	ctx := c.Request.Context()
+	assignments, err := h.experiments.AssignSession(ctx, session)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to assign session to experiments", "session_id", session.ID, "error", err)
+		assignments = []entity.ExperimentAssignment{}
+	}
+	c.JSON(http.StatusCreated, sessionResponse{Session: session, Experiments: assignments})
+}
+
+// matchMeta returns the experiment tag of a new match in session.
+func (h *Handler) matchMeta(c *gin.Context, session entity.Session) (json.RawMessage, bool) {
+	if h.experiments == nil {
+		return nil, true
+	}
+	assignments, err := h.experiments.AssignSession(c.Request.Context(), session)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	return usecase.MatchMeta(assignments), true
+}
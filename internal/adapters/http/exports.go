@@ -0,0 +1,81 @@
+package httpadapter
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/org/ranas-bdi-backend/internal/adapters/export"
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+// WithExports enables GET /exports/:dataset. Exports contain per-player rows,
+// so they are only served to admin tokens.
+func WithExports(exports *usecase.ExportService) Option {
+	return func(h *Handler) { h.exports = exports }
+}
+
+func (h *Handler) handleExport(c *gin.Context) {
+	dataset, err := export.ParseDataset(c.Param("dataset"))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+	format, err := export.ParseFormat(c.DefaultQuery("format", string(export.CSV)))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	filter, err := parseExportFilter(c)
+	if err == nil {
+		err = h.exports.Validate(filter)
+	}
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// Streams outlive the server-wide write timeout.
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", `attachment; filename="`+format.FileName(dataset)+`"`)
+	c.Status(http.StatusOK)
+
+	if err := export.Write(c.Request.Context(), h.exports, c.Writer, dataset, format, filter); err != nil {
+		// The status line is already sent, so abort the connection instead
+		// of letting the client mistake a partial file for a complete one.
+		slog.ErrorContext(c.Request.Context(), "export aborted", "dataset", dataset, "error", err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func parseExportFilter(c *gin.Context) (entity.ExportFilter, error) {
+	var filter entity.ExportFilter
+	var err error
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		return filter, err
+	}
+	if v := c.Query("player_id"); v != "" {
+		if _, err := uuid.Parse(v); err != nil {
+			return filter, errInvalidPlayerID
+		}
+		filter.PlayerID = &v
+	}
+	if v := c.Query("difficulty_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, errInvalidDifficultyID
+		}
+		filter.DifficultyID = &id
+	}
+	filter.Experiment, filter.Arm = parseArmQuery(c)
+	return filter, nil
+}
@@ -9,7 +9,8 @@ import (
 )
 
 type createGameReq struct {
-	GameID string `json:"game_id" binding:"required"`
+	GameID       string `json:"game_id" binding:"required"`
+	CurriculumID *int   `json:"curriculum_id"`
 }
 
 var errInvalidGameID = errors.New("game_id must be a valid UUID")
@@ -26,11 +27,15 @@ func (h *Handler) handleCreateGame(c *gin.Context) {
 		return
 	}
 
-	session, err := h.sessions.Create(c.Request.Context(), req.GameID, "")
+	if !h.checkCurriculum(c, req.CurriculumID) {
+		return
+	}
+
+	session, err := h.sessions.Create(c.Request.Context(), req.GameID, "", req.CurriculumID)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, session)
+	h.respondSession(c, session)
 }
@@ -3,13 +3,14 @@ package httpadapter
 import (
 	"errors"
 	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 	"github.com/org/ranas-bdi-backend/internal/app/usecase"
-	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/platform/logging"
+	"github.com/org/ranas-bdi-backend/internal/platform/metrics"
 )
 
 type Handler struct {
@@ -18,20 +19,62 @@ type Handler struct {
 	matches       *usecase.MatchService
 	moves         *usecase.MoveService
 	difficulties  *usecase.DifficultyService
+	health        *usecase.HealthService
+	streams       *usecase.MatchStreamService
+	replays       *usecase.ReplayService
+	matchMetrics  *usecase.MatchMetricsService
+	hints         *usecase.HintService
+	curricula     *usecase.CurriculumService
+	experiments   *usecase.ExperimentService
+	consents      *usecase.ConsentService
+	privacy       *usecase.PrivacyService
+	retention     *usecase.RetentionService
+	audit         *usecase.AuditService
+	analytics     *usecase.AnalyticsService
+	reports       *usecase.ReportService
+	exports       *usecase.ExportService
+	xapi          *usecase.XAPIService
+	webhooks      *usecase.WebhookService
+	adminTokens   map[string]string
+	metrics       *metrics.Registry
+	traceService  string
 	defaultDevice string
 	defaultLevel  int
 }
 
+// Option wires an optional subsystem into the handler. Routes that depend on
+// it are only registered when it is provided.
+type Option func(*Handler)
+
+func WithHealth(health *usecase.HealthService) Option {
+	return func(h *Handler) { h.health = health }
+}
+
+// WithStreams enables the live match stream for observers.
+func WithStreams(streams *usecase.MatchStreamService) Option {
+	return func(h *Handler) { h.streams = streams }
+}
+
+// WithMetrics instruments every route and exposes the registry on /metrics.
+func WithMetrics(registry *metrics.Registry) Option {
+	return func(h *Handler) { h.metrics = registry }
+}
+
+// WithTracing starts an OpenTelemetry span per request, named after the route
+// template and continuing any trace propagated by the caller.
+func WithTracing(serviceName string) Option {
+	return func(h *Handler) { h.traceService = serviceName }
+}
+
 func NewHandler(
 	sessions *usecase.SessionService,
 	matches *usecase.MatchService,
 	moves *usecase.MoveService,
 	difficulties *usecase.DifficultyService,
+	opts ...Option,
 ) *Handler {
-	router := gin.Default()
-
 	h := &Handler{
-		router:        router,
+		router:        gin.New(),
 		sessions:      sessions,
 		matches:       matches,
 		moves:         moves,
@@ -39,16 +82,131 @@ func NewHandler(
 		defaultDevice: "Meta Quest 3",
 		defaultLevel:  1,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
 
+	h.router.Use(h.middleware()...)
 	h.registerRoutes()
 	return h
 }
 
+func (h *Handler) middleware() []gin.HandlerFunc {
+	var chain []gin.HandlerFunc
+	if h.traceService != "" {
+		chain = append(chain, otelgin.Middleware(h.traceService))
+	}
+	chain = append(chain, requestIDMiddleware(), accessLogMiddleware(), recoveryMiddleware())
+	if h.metrics != nil {
+		chain = append(chain, h.metricsMiddleware())
+	}
+	return chain
+}
+
 func (h *Handler) registerRoutes() {
+	if h.metrics != nil {
+		h.router.GET("/metrics", gin.WrapH(h.metrics.Handler()))
+	}
+
+	h.router.GET("/healthz", h.handleHealthz)
+	h.router.GET("/version", h.handleVersion)
+	if h.health != nil {
+		h.router.GET("/readyz", h.handleReadyz)
+	}
+
 	h.router.POST("/game", h.handleCreateGame)
 	h.router.POST("/sessions", h.handleCreateSession)
+	h.router.POST("/sessions/:sessionID/finish", h.handleFinishSession)
 	h.router.POST("/matches", h.handleCreateMatch)
+	if h.curricula != nil {
+		h.router.GET("/sessions/:sessionID/curriculum", h.handleGetCurriculumPosition)
+	}
+	h.router.POST("/matches/:matchID/finish", h.handleFinishMatch)
 	h.router.POST("/matches/:matchID/moves", h.handleCreateMove)
+	h.router.POST("/matches/:matchID/undo", h.handleUndoMove)
+	h.router.POST("/matches/:matchID/restart", h.handleRestartMatch)
+	h.router.POST("/matches/:matchID/pause", h.handlePauseMatch)
+	h.router.POST("/matches/:matchID/resume", h.handleResumeMatch)
+	h.router.GET("/matches/:matchID/clock", h.handleMatchClock)
+	if h.streams != nil {
+		h.router.GET("/matches/:matchID/stream", h.handleStreamMatch)
+	}
+	if h.replays != nil {
+		h.router.GET("/matches/:matchID/replay", h.handleGetReplay)
+	}
+	if h.hints != nil {
+		h.router.POST("/matches/:matchID/hints", h.handleCreateHint)
+		h.router.GET("/matches/:matchID/hints", h.handleListHints)
+		h.router.POST("/matches/:matchID/hints/:hintID/dismiss", h.handleDismissHint)
+	}
+	if h.matchMetrics != nil {
+		h.router.GET("/matches/:matchID/stats", h.handleGetMatchStats)
+	}
+	if h.xapi != nil {
+		h.router.GET("/matches/:matchID/xapi", h.handlePreviewXAPI)
+	}
+	if h.analytics != nil {
+		h.router.GET("/players/:playerID/progress", h.handlePlayerProgress)
+	}
+	if h.reports != nil {
+		h.router.GET("/reports/difficulties", h.handleDifficultyReport)
+		h.router.GET("/reports/cohorts", h.handleCohortReport)
+		h.router.GET("/reports/arms", h.handleArmReport)
+	}
+
+	if len(h.adminTokens) == 0 {
+		return
+	}
+	if h.exports != nil {
+		h.router.GET("/exports/:dataset", h.adminMiddleware(), h.handleExport)
+	}
+	if h.audit != nil {
+		h.router.GET("/audit", h.adminMiddleware(), h.handleListAudit)
+	}
+
+	admin := h.router.Group("/admin", h.adminMiddleware())
+	if h.audit != nil {
+		admin.Use(h.auditMiddleware())
+	}
+	admin.GET("/difficulties", h.handleListDifficulties)
+	admin.POST("/difficulties", h.handleCreateDifficulty)
+	admin.PUT("/difficulties/:difficultyID", h.handleUpdateDifficulty)
+	admin.DELETE("/difficulties/:difficultyID", h.handleRetireDifficulty)
+	admin.GET("/difficulties/:difficultyID/versions", h.handleListDifficultyVersions)
+	if h.webhooks != nil {
+		admin.POST("/webhooks", h.handleCreateWebhook)
+		admin.GET("/webhooks", h.handleListWebhooks)
+		admin.DELETE("/webhooks/:webhookID", h.handleDeleteWebhook)
+		admin.GET("/webhooks/:webhookID/deliveries", h.handleListWebhookDeliveries)
+	}
+	if h.curricula != nil {
+		admin.GET("/curricula", h.handleListCurricula)
+		admin.POST("/curricula", h.handleCreateCurriculum)
+	}
+	if h.experiments != nil {
+		admin.GET("/experiments", h.handleListExperiments)
+		admin.POST("/experiments", h.handleCreateExperiment)
+		admin.DELETE("/experiments/:experimentID", h.handleDeactivateExperiment)
+	}
+	if h.reports != nil {
+		admin.GET("/players/:playerID/cohorts", h.handleGetPlayerCohorts)
+		admin.PUT("/players/:playerID/cohorts", h.handleSetPlayerCohorts)
+	}
+	if h.consents != nil {
+		admin.GET("/players/:playerID/consents", h.handleListConsents)
+		admin.POST("/players/:playerID/consents", h.handleRecordConsent)
+		admin.POST("/players/:playerID/consents/withdraw", h.handleWithdrawConsent)
+	}
+	if h.privacy != nil {
+		admin.GET("/players/:playerID/data", h.handleExportPlayerData)
+		admin.DELETE("/players/:playerID", h.handleDeletePlayerData)
+		admin.POST("/players/:playerID/anonymize", h.handleAnonymizePlayerData)
+		admin.GET("/privacy/audit", h.handleListPrivacyAudit)
+	}
+	if h.retention != nil {
+		admin.POST("/matches/:matchID/archive", h.handleArchiveMatch)
+		admin.POST("/matches/:matchID/restore", h.handleRestoreMatch)
+	}
 }
 
 func (h *Handler) Router() *gin.Engine {
@@ -56,16 +214,26 @@ func (h *Handler) Router() *gin.Engine {
 }
 
 type createSessionRequest struct {
-	GameID string `json:"game_id" binding:"required"`
+	GameID       string `json:"game_id" binding:"required"`
+	CurriculumID *int   `json:"curriculum_id"`
 }
 
+// createMatchRequest may leave DifficultyID out when curricula are enabled;
+// the match is then played on the difficulty of the session's current level.
 type createMatchRequest struct {
 	SessionID    string `json:"session_id" binding:"required"`
-	DifficultyID int    `json:"difficulty_id" binding:"required"`
+	DifficultyID int    `json:"difficulty_id"`
 }
 
+// createMoveRequest moves the frog on block Movement[0] to block Movement[1].
 type createMoveRequest struct {
-	Movement []int `json:"movement"`
+	Movement []int `json:"movement" binding:"required"`
+}
+
+// finishMatchRequest closes a match with entity.OutcomeWin or
+// entity.OutcomeLose.
+type finishMatchRequest struct {
+	Outcome string `json:"outcome" binding:"required"`
 }
 
 func (h *Handler) handleCreateSession(c *gin.Context) {
@@ -75,13 +243,47 @@ func (h *Handler) handleCreateSession(c *gin.Context) {
 		return
 	}
 
-	session, err := h.sessions.Create(c.Request.Context(), req.GameID, h.defaultDevice)
+	if !h.checkCurriculum(c, req.CurriculumID) {
+		return
+	}
+
+	session, err := h.sessions.Create(c.Request.Context(), req.GameID, h.defaultDevice, req.CurriculumID)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, session)
+	h.respondSession(c, session)
+}
+
+// handleFinishSession closes a session once its last match is over; a
+// session with an active match answers 409 until the match is finished.
+func (h *Handler) handleFinishSession(c *gin.Context) {
+	ctx := c.Request.Context()
+	sessionID := c.Param("sessionID")
+	switch _, err := h.matches.GetActiveBySession(ctx, sessionID); {
+	case err == nil:
+		respondError(c, http.StatusConflict, usecase.ErrMatchActive)
+		return
+	case !errors.Is(err, pgx.ErrNoRows):
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	session, err := h.sessions.Finish(ctx, sessionID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			respondError(c, http.StatusNotFound, err)
+		case errors.Is(err, usecase.ErrSessionFinished):
+			respondError(c, http.StatusConflict, err)
+		default:
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
 }
 
 func (h *Handler) handleCreateMatch(c *gin.Context) {
@@ -91,7 +293,11 @@ func (h *Handler) handleCreateMatch(c *gin.Context) {
 		return
 	}
 
-	if _, err := h.sessions.Get(c.Request.Context(), req.SessionID); err != nil {
+	ctx := logging.With(c.Request.Context(), "session_id", req.SessionID)
+	c.Request = c.Request.WithContext(ctx)
+
+	session, err := h.sessions.Get(c.Request.Context(), req.SessionID)
+	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			respondError(c, http.StatusNotFound, err)
 		} else {
@@ -99,8 +305,18 @@ func (h *Handler) handleCreateMatch(c *gin.Context) {
 		}
 		return
 	}
+	if session.IsFinished {
+		respondError(c, http.StatusConflict, usecase.ErrSessionFinished)
+		return
+	}
 
-	if _, err := h.difficulties.GetByID(c.Request.Context(), req.DifficultyID); err != nil {
+	level, ok := h.nextLevel(c, &req)
+	if !ok {
+		return
+	}
+
+	difficulty, err := h.difficulties.GetByID(c.Request.Context(), req.DifficultyID)
+	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			respondError(c, http.StatusNotFound, err)
 		} else {
@@ -108,10 +324,23 @@ func (h *Handler) handleCreateMatch(c *gin.Context) {
 		}
 		return
 	}
+	if difficulty.RetiredAt != nil {
+		respondError(c, http.StatusConflict, usecase.ErrDifficultyRetired)
+		return
+	}
+
+	meta, ok := h.matchMeta(c, session)
+	if !ok {
+		return
+	}
 
-	match, err := h.matches.Create(c.Request.Context(), req.SessionID, req.DifficultyID, h.defaultLevel)
+	match, err := h.matches.Create(c.Request.Context(), req.SessionID, req.DifficultyID, level, meta)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, err)
+		if errors.Is(err, usecase.ErrMatchActive) {
+			respondError(c, http.StatusConflict, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
 		return
 	}
 
@@ -127,56 +356,49 @@ func (h *Handler) handleCreateMove(c *gin.Context) {
 
 	var req createMoveRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
+		h.moves.Reject(usecase.RejectInvalidPayload)
 		respondError(c, http.StatusBadRequest, err)
 		return
 	}
-
-	match, err := h.matches.Get(c.Request.Context(), matchID)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			respondError(c, http.StatusNotFound, err)
-		} else {
-			respondError(c, http.StatusInternalServerError, err)
-		}
+	if len(req.Movement) != 2 {
+		h.moves.Reject(usecase.RejectInvalidPayload)
+		respondError(c, http.StatusBadRequest, errInvalidMovement)
 		return
 	}
 
-	seq := 1
-	lastMove, err := h.moves.GetLastByMatch(c.Request.Context(), matchID)
-	if err == nil {
-		seq = lastMove.Seq + 1
-	} else if !errors.Is(err, pgx.ErrNoRows) {
-		respondError(c, http.StatusInternalServerError, err)
+	match, difficulty, ok := h.playableMatch(c)
+	if !ok {
 		return
 	}
 
-	move := entity.Move{
-		MatchID:      match.ID,
-		Seq:          seq,
-		OccurredAt:   time.Now().UTC(),
-		ElapsedMs:    0,
-		FromIdx:      0,
-		ToIdx:        0,
-		MoveKind:     1,
-		FrogSide:     1,
-		IsCorrect:    true,
-		Interruption: false,
-	}
-
-	created, err := h.moves.Create(c.Request.Context(), move)
+	created, err := h.moves.Create(c.Request.Context(), match, difficulty, req.Movement[0], req.Movement[1])
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, err)
+		switch {
+		case errors.Is(err, usecase.ErrIllegalMove):
+			h.moves.Reject(usecase.RejectIllegalMove)
+			respondError(c, http.StatusUnprocessableEntity, err)
+		case errors.Is(err, usecase.ErrMatchPaused):
+			respondError(c, http.StatusConflict, err)
+		default:
+			respondError(c, http.StatusInternalServerError, err)
+		}
 		return
 	}
 
 	c.JSON(http.StatusCreated, created)
 }
 
-var errMissingMatchID = errors.New("match id is required")
+var (
+	errMissingMatchID      = errors.New("match id is required")
+	errMissingDifficultyID = errors.New("difficulty_id is required")
+	errMatchFinished       = errors.New("match is already finished")
+	errInvalidMovement     = errors.New("movement must be [from, to] block indexes")
+)
 
 func respondError(c *gin.Context, status int, err error) {
 	if err == nil {
 		err = errors.New("unknown error")
 	}
+	_ = c.Error(err)
 	c.JSON(status, gin.H{"error": err.Error()})
 }
@@ -0,0 +1,33 @@
+package httpadapter
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/org/ranas-bdi-backend/internal/platform/buildinfo"
+)
+
+const readinessTimeout = 2 * time.Second
+
+func (h *Handler) handleHealthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+func (h *Handler) handleReadyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	readiness := h.health.Readiness(ctx)
+	status := http.StatusOK
+	if !readiness.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, readiness)
+}
+
+func (h *Handler) handleVersion(c *gin.Context) {
+	c.JSON(http.StatusOK, buildinfo.Get())
+}
@@ -0,0 +1,96 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+type stubDatabaseProbe struct {
+	pingErr error
+	missing []string
+	columns []string
+	stats   ports.PoolStats
+}
+
+func (p stubDatabaseProbe) Ping(ctx context.Context) error {
+	return p.pingErr
+}
+
+func (p stubDatabaseProbe) Stats(ctx context.Context) (ports.PoolStats, error) {
+	return p.stats, nil
+}
+
+func (p stubDatabaseProbe) MissingRelations(ctx context.Context) ([]string, error) {
+	return p.missing, nil
+}
+
+func (p stubDatabaseProbe) MissingColumns(ctx context.Context) ([]string, error) {
+	return p.columns, nil
+}
+
+func TestHandleReadyz(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name   string
+		probe  stubDatabaseProbe
+		status int
+		schema string
+	}{
+		{
+			name:   "ready",
+			probe:  stubDatabaseProbe{stats: ports.PoolStats{TotalConns: 4, AcquiredConns: 1, IdleConns: 3, MaxConns: 10}},
+			status: http.StatusOK,
+			schema: "current",
+		},
+		{
+			name:   "database down",
+			probe:  stubDatabaseProbe{pingErr: errors.New("connection refused")},
+			status: http.StatusServiceUnavailable,
+			schema: "unknown",
+		},
+		{
+			name:   "missing tables",
+			probe:  stubDatabaseProbe{missing: []string{"match_stats"}},
+			status: http.StatusServiceUnavailable,
+			schema: "outdated",
+		},
+		{
+			name:   "missing columns",
+			probe:  stubDatabaseProbe{columns: []string{"matches.difficulty_version"}},
+			status: http.StatusServiceUnavailable,
+			schema: "outdated",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			handler := &Handler{router: router, health: usecase.NewHealthService(tc.probe)}
+			router.GET("/readyz", handler.handleReadyz)
+
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			require.Equal(t, tc.status, resp.Code)
+			var body usecase.Readiness
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+			require.Equal(t, tc.schema, body.Schema)
+			require.Equal(t, tc.probe.missing, body.MissingRelations)
+			require.Equal(t, tc.probe.columns, body.MissingColumns)
+			if tc.status == http.StatusOK {
+				require.Equal(t, tc.probe.stats, body.Pool)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+// WithHints enables recording and listing the hints shown in a match.
+func WithHints(hints *usecase.HintService) Option {
+	return func(h *Handler) { h.hints = hints }
+}
+
+type createHintRequest struct {
+	HintType  string `json:"hint_type" binding:"required"`
+	Source    string `json:"source" binding:"required"`
+	FromIdx   *int   `json:"from_idx"`
+	ToIdx     *int   `json:"to_idx"`
+	Dismissed bool   `json:"dismissed"`
+}
+
+func (h *Handler) handleCreateHint(c *gin.Context) {
+	var req createHintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	hint, err := h.hints.Record(c.Request.Context(), entity.Hint{
+		MatchID:   c.Param("matchID"),
+		HintType:  req.HintType,
+		Source:    req.Source,
+		FromIdx:   req.FromIdx,
+		ToIdx:     req.ToIdx,
+		Dismissed: req.Dismissed,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidHint):
+			respondError(c, http.StatusBadRequest, err)
+		case errors.Is(err, pgx.ErrNoRows):
+			respondError(c, http.StatusNotFound, err)
+		case errors.Is(err, usecase.ErrMatchFinished):
+			respondError(c, http.StatusConflict, err)
+		default:
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, hint)
+}
+
+func (h *Handler) handleListHints(c *gin.Context) {
+	hints, err := h.hints.List(c.Request.Context(), c.Param("matchID"))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(c, http.StatusNotFound, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, hints)
+}
+
+func (h *Handler) handleDismissHint(c *gin.Context) {
+	hint, err := h.hints.Dismiss(c.Request.Context(), c.Param("matchID"), c.Param("hintID"))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(c, http.StatusNotFound, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, hint)
+}
@@ -0,0 +1,60 @@
+package httpadapter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+func TestHintFollowedByPostedMove(t *testing.T) {
+	store := newMemoryStore()
+	hints := usecase.NewHintService(memoryMatchRepo{store}, memoryHintRepo{store})
+	h := newMemoryHandler(store, WithHints(hints))
+
+	var session entity.Session
+	require.Equal(t, http.StatusCreated, serveJSON(t, h, http.MethodPost, "/sessions",
+		map[string]any{"game_id": "11111111-1111-1111-1111-111111111111"}, &session))
+	var match entity.Match
+	require.Equal(t, http.StatusCreated, serveJSON(t, h, http.MethodPost, "/matches",
+		map[string]any{"session_id": session.ID, "difficulty_id": 1}, &match))
+	base := "/matches/" + match.ID
+
+	// resolve plays the relay: MoveRecorded events reach the hint service.
+	resolve := func() {
+		for _, event := range store.outbox {
+			if event.Type == entity.EventMoveRecorded {
+				require.NoError(t, hints.Resolve(context.Background(), event))
+			}
+		}
+	}
+
+	// [1,1,1,0,2,2,2]: point at the left frog on block 2, which the player
+	// then moves.
+	require.Equal(t, http.StatusCreated, serveJSON(t, h, http.MethodPost, base+"/hints",
+		map[string]any{"hint_type": entity.HintTypeFrog, "source": entity.HintSourceAgent, "from_idx": 2}, nil))
+	var move entity.Move
+	require.Equal(t, http.StatusCreated, serveJSON(t, h, http.MethodPost, base+"/moves",
+		map[string]any{"movement": []int{2, 3}}, &move))
+	require.Equal(t, 2, move.FromIdx)
+	require.Equal(t, 3, move.ToIdx)
+
+	// Suggest the jump 4→2, but the player steps 1→2 instead.
+	require.Equal(t, http.StatusCreated, serveJSON(t, h, http.MethodPost, base+"/hints",
+		map[string]any{"hint_type": entity.HintTypeNextMove, "source": entity.HintSourcePlayer, "from_idx": 4, "to_idx": 2}, nil))
+	require.Equal(t, http.StatusCreated, serveJSON(t, h, http.MethodPost, base+"/moves",
+		map[string]any{"movement": []int{1, 2}}, nil))
+	resolve()
+
+	var listed []entity.Hint
+	require.Equal(t, http.StatusOK, serveJSON(t, h, http.MethodGet, base+"/hints", nil, &listed))
+	require.Len(t, listed, 2)
+	require.NotNil(t, listed[0].Followed)
+	require.True(t, *listed[0].Followed)
+	require.NotNil(t, listed[1].Followed)
+	require.False(t, *listed[1].Followed)
+}
@@ -0,0 +1,95 @@
+package httpadapter
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/platform/logging"
+)
+
+const (
+	requestIDHeader   = "X-Request-ID"
+	maxRequestIDBytes = 128
+)
+
+// requestIDMiddleware reuses the caller's X-Request-ID when it looks sane and
+// generates one otherwise. The ID and the match ID path parameter are stored
+// in the request context so service and repository logs carry them. Changes
+// are attributed to the game client until adminMiddleware names an admin.
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		c.Header(requestIDHeader, id)
+
+		ctx := logging.WithRequestID(c.Request.Context(), id)
+		ctx = logging.WithActor(ctx, entity.AuditActorClient)
+		if matchID := c.Param("matchID"); matchID != "" {
+			ctx = logging.With(ctx, "match_id", matchID)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDBytes {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func accessLogMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		attrs := []any{
+			"method", c.Request.Method,
+			"route", c.FullPath(),
+			"path", c.Request.URL.Path,
+			"status", status,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"bytes", c.Writer.Size(),
+			"client_ip", c.ClientIP(),
+		}
+		if err := c.Errors.Last(); err != nil {
+			attrs = append(attrs, "error", err.Error())
+		}
+		slog.Log(c.Request.Context(), level, "http request", attrs...)
+	}
+}
+
+func recoveryMiddleware() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		// Handlers that already started streaming abort the connection;
+		// let net/http handle it.
+		if recovered == http.ErrAbortHandler {
+			panic(recovered)
+		}
+		slog.ErrorContext(c.Request.Context(), "panic recovered", "panic", recovered)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	})
+}
@@ -0,0 +1,40 @@
+package httpadapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/platform/logging"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var seen string
+	router := gin.New()
+	router.Use(requestIDMiddleware())
+	router.GET("/ping", func(c *gin.Context) {
+		seen = logging.RequestID(c.Request.Context())
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(requestIDHeader, "client-supplied-id")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, "client-supplied-id", resp.Header().Get(requestIDHeader))
+	require.Equal(t, "client-supplied-id", seen)
+
+	req = httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(requestIDHeader, "has spaces\tand tabs")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	_, err := uuid.Parse(resp.Header().Get(requestIDHeader))
+	require.NoError(t, err)
+	require.Equal(t, resp.Header().Get(requestIDHeader), seen)
+}
@@ -0,0 +1,173 @@
+package httpadapter
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+func (h *Handler) handleMatchClock(c *gin.Context) {
+	h.clockAction(c, h.matches.Clock)
+}
+
+func (h *Handler) handlePauseMatch(c *gin.Context) {
+	h.clockAction(c, h.matches.Pause)
+}
+
+func (h *Handler) handleResumeMatch(c *gin.Context) {
+	h.clockAction(c, h.matches.Resume)
+}
+
+func (h *Handler) clockAction(c *gin.Context, action func(ctx context.Context, match entity.Match, difficulty entity.Difficulty) (usecase.MatchClock, error)) {
+	ctx := c.Request.Context()
+	match, difficulty, ok := h.matchWithDifficulty(c)
+	if !ok {
+		return
+	}
+	clock, err := action(ctx, match, difficulty)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrMatchFinished),
+			errors.Is(err, usecase.ErrMatchPaused),
+			errors.Is(err, usecase.ErrMatchNotPaused):
+			respondError(c, http.StatusConflict, err)
+		default:
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, clock)
+}
+
+// handleFinishMatch closes a match with the outcome reported by the client.
+// A match that ran out of time meanwhile is finished as a timeout instead,
+// and a win is only taken when the server board is solved, since it moves
+// the session up its curriculum.
+func (h *Handler) handleFinishMatch(c *gin.Context) {
+	var req finishMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	match, difficulty, ok := h.matchWithDifficulty(c)
+	if !ok {
+		return
+	}
+	if !match.IsActive {
+		respondError(c, http.StatusConflict, errMatchFinished)
+		return
+	}
+	clock, err := h.matches.Clock(ctx, match, difficulty)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if clock.Expired {
+		if _, err := h.matches.Expire(ctx, match); err != nil {
+			slog.WarnContext(ctx, "failed to time out match", "match_id", match.ID, "error", err)
+		}
+		respondError(c, http.StatusConflict, usecase.ErrMatchTimedOut)
+		return
+	}
+	if req.Outcome == entity.OutcomeWin {
+		solved, err := h.moves.Solved(ctx, match, difficulty)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, err)
+			return
+		}
+		if !solved {
+			respondError(c, http.StatusConflict, usecase.ErrBoardNotSolved)
+			return
+		}
+	}
+
+	finished, err := h.matches.FinishActive(ctx, match.SessionID, req.Outcome)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidOutcome):
+			respondError(c, http.StatusBadRequest, err)
+		case errors.Is(err, pgx.ErrNoRows), errors.Is(err, usecase.ErrMatchFinished):
+			respondError(c, http.StatusConflict, errMatchFinished)
+		default:
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, finished)
+}
+
+// matchWithDifficulty loads the match of the request and the difficulty
+// version it is played on, answering 404 when the match does not exist.
+func (h *Handler) matchWithDifficulty(c *gin.Context) (entity.Match, entity.Difficulty, bool) {
+	return h.loadMatch(c, false)
+}
+
+// loadMatch is matchWithDifficulty; rejectMove also counts a missing match
+// as a rejected move, for the routes that take one.
+func (h *Handler) loadMatch(c *gin.Context, rejectMove bool) (entity.Match, entity.Difficulty, bool) {
+	ctx := c.Request.Context()
+	match, err := h.matches.Get(ctx, c.Param("matchID"))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			if rejectMove {
+				h.moves.Reject(usecase.RejectMatchNotFound)
+			}
+			respondError(c, http.StatusNotFound, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return entity.Match{}, entity.Difficulty{}, false
+	}
+	difficulty, err := h.difficulties.ForMatch(ctx, match)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return entity.Match{}, entity.Difficulty{}, false
+	}
+	return match, difficulty, true
+}
+
+// playableMatch loads the match of the request and checks that it can take
+// a move: it is active, not paused and within its time limit. A match found
+// to be out of time is finished with the timeout outcome on the spot.
+func (h *Handler) playableMatch(c *gin.Context) (entity.Match, entity.Difficulty, bool) {
+	ctx := c.Request.Context()
+	match, difficulty, ok := h.loadMatch(c, true)
+	if !ok {
+		return entity.Match{}, entity.Difficulty{}, false
+	}
+	if !match.IsActive {
+		h.moves.Reject(usecase.RejectMatchFinished)
+		respondError(c, http.StatusConflict, errMatchFinished)
+		return entity.Match{}, entity.Difficulty{}, false
+	}
+
+	clock, err := h.matches.Clock(ctx, match, difficulty)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return entity.Match{}, entity.Difficulty{}, false
+	}
+	switch {
+	case clock.Expired:
+		if _, err := h.matches.Expire(ctx, match); err != nil {
+			slog.WarnContext(ctx, "failed to time out match", "match_id", match.ID, "error", err)
+		}
+		h.moves.Reject(usecase.RejectMatchTimedOut)
+		respondError(c, http.StatusConflict, usecase.ErrMatchTimedOut)
+		return entity.Match{}, entity.Difficulty{}, false
+	case clock.Paused:
+		h.moves.Reject(usecase.RejectMatchPaused)
+		respondError(c, http.StatusConflict, usecase.ErrMatchPaused)
+		return entity.Match{}, entity.Difficulty{}, false
+	}
+	return match, difficulty, true
+}
@@ -0,0 +1,30 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+)
+
+// WithMatchMetrics enables GET /matches/:matchID/stats, the KPIs of a match
+// together with the cognitive metrics computed when it closed.
+func WithMatchMetrics(metrics *usecase.MatchMetricsService) Option {
+	return func(h *Handler) { h.matchMetrics = metrics }
+}
+
+func (h *Handler) handleGetMatchStats(c *gin.Context) {
+	stats, err := h.matchMetrics.Stats(c.Request.Context(), c.Param("matchID"))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(c, http.StatusNotFound, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,79 @@
+package httpadapter
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/game"
+)
+
+// solve plays a shortest solution of the standard board through the API.
+func solve(t *testing.T, h *Handler, matchID string, blocks int) {
+	t.Helper()
+	board, err := game.NewBoard(blocks)
+	require.NoError(t, err)
+	solver := game.NewSolver()
+	for !board.Solved() {
+		for _, mv := range board.LegalMoves() {
+			if !solver.Optimal(board, mv.From, mv.To) {
+				continue
+			}
+			require.Equal(t, http.StatusCreated, serveJSON(t, h, http.MethodPost, "/matches/"+matchID+"/moves",
+				map[string]any{"movement": []int{mv.From, mv.To}}, nil))
+			board, err = board.Apply(mv.From, mv.To)
+			require.NoError(t, err)
+			break
+		}
+	}
+}
+
+func TestFinishMatchAdvancesCurriculum(t *testing.T) {
+	store := newMemoryStore()
+	store.difficulties[2] = entity.Difficulty{ID: 2, Name: "hard", NumberOfBlocks: 9, Version: 1}
+	curricula := usecase.NewCurriculumService(
+		memoryCurriculumRepo{entity.Curriculum{ID: 1, Name: "default", AdvanceAfter: 1, IsDefault: true, Levels: []entity.CurriculumLevel{
+			{LevelN: 1, DifficultyID: 1},
+			{LevelN: 2, DifficultyID: 2},
+		}}},
+		memorySessionRepo{store},
+		memoryMatchRepo{store},
+		memoryDifficultyRepo{store},
+	)
+	h := newMemoryHandler(store, WithCurricula(curricula))
+
+	var session entity.Session
+	require.Equal(t, http.StatusCreated, serveJSON(t, h, http.MethodPost, "/sessions",
+		map[string]any{"game_id": "11111111-1111-1111-1111-111111111111"}, &session))
+	var match entity.Match
+	require.Equal(t, http.StatusCreated, serveJSON(t, h, http.MethodPost, "/matches",
+		map[string]any{"session_id": session.ID}, &match))
+	require.Equal(t, 1, match.DifficultyID)
+	require.Equal(t, 1, match.LevelN)
+
+	require.Equal(t, http.StatusConflict, serveJSON(t, h, http.MethodPost, "/matches",
+		map[string]any{"session_id": session.ID}, nil), "one active match per session")
+
+	finish := "/matches/" + match.ID + "/finish"
+	require.Equal(t, http.StatusBadRequest, serveJSON(t, h, http.MethodPost, finish, map[string]any{"outcome": "draw"}, nil))
+	require.Equal(t, http.StatusConflict, serveJSON(t, h, http.MethodPost, finish, map[string]any{"outcome": entity.OutcomeWin}, nil),
+		"a win needs a solved board")
+	solve(t, h, match.ID, 7)
+	var finished entity.Match
+	require.Equal(t, http.StatusOK, serveJSON(t, h, http.MethodPost, finish, map[string]any{"outcome": entity.OutcomeWin}, &finished))
+	require.False(t, finished.IsActive)
+	require.NotNil(t, finished.Outcome)
+	require.Equal(t, entity.OutcomeWin, *finished.Outcome)
+	require.Equal(t, http.StatusConflict, serveJSON(t, h, http.MethodPost, finish, map[string]any{"outcome": entity.OutcomeLose}, nil))
+
+	require.Equal(t, http.StatusConflict, serveJSON(t, h, http.MethodPost, "/matches",
+		map[string]any{"session_id": session.ID, "difficulty_id": 1}, nil), "level 2 is played on difficulty 2")
+	var next entity.Match
+	require.Equal(t, http.StatusCreated, serveJSON(t, h, http.MethodPost, "/matches",
+		map[string]any{"session_id": session.ID}, &next))
+	require.Equal(t, 2, next.DifficultyID)
+	require.Equal(t, 2, next.LevelN)
+}
@@ -0,0 +1,427 @@
+package httpadapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+// memoryStore backs the in-memory repositories used to drive the handler
+// through whole games. outbox collects the events the Postgres repositories
+// would write in the same statement.
+type memoryStore struct {
+	sessions     map[string]entity.Session
+	matches      map[string]entity.Match
+	moves        map[string][]entity.Move
+	hints        map[string][]entity.Hint
+	difficulties map[int]entity.Difficulty
+	webhooks     map[string]entity.Webhook
+	deliveries   []entity.WebhookDelivery
+	outbox       []entity.OutboxEvent
+	nextID       int
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		sessions:     map[string]entity.Session{},
+		matches:      map[string]entity.Match{},
+		moves:        map[string][]entity.Move{},
+		hints:        map[string][]entity.Hint{},
+		difficulties: map[int]entity.Difficulty{1: {ID: 1, Name: "standard", NumberOfBlocks: 7, Version: 1}},
+		webhooks:     map[string]entity.Webhook{},
+	}
+}
+
+func (s *memoryStore) id(prefix string) string {
+	s.nextID++
+	return prefix + "-" + strconv.Itoa(s.nextID)
+}
+
+func (s *memoryStore) emit(eventType, aggregateID string, payload any) {
+	data, _ := json.Marshal(payload)
+	s.outbox = append(s.outbox, entity.OutboxEvent{
+		ID:          int64(len(s.outbox) + 1),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Payload:     data,
+	})
+}
+
+type memorySessionRepo struct{ *memoryStore }
+
+func (r memorySessionRepo) Create(ctx context.Context, session entity.Session) (entity.Session, error) {
+	session.ID = r.id("session")
+	session.StartedAt = time.Now().UTC()
+	r.sessions[session.ID] = session
+	return session, nil
+}
+
+func (r memorySessionRepo) Get(ctx context.Context, id string) (entity.Session, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return entity.Session{}, pgx.ErrNoRows
+	}
+	return session, nil
+}
+
+func (r memorySessionRepo) Update(ctx context.Context, session entity.Session) (entity.Session, error) {
+	previous, ok := r.sessions[session.ID]
+	if !ok {
+		return entity.Session{}, pgx.ErrNoRows
+	}
+	r.sessions[session.ID] = session
+	if !previous.IsFinished && session.IsFinished {
+		r.emit(entity.EventSessionFinished, session.ID, session)
+	}
+	return session, nil
+}
+
+type memoryMatchRepo struct{ *memoryStore }
+
+func (r memoryMatchRepo) Create(ctx context.Context, match entity.Match) (entity.Match, error) {
+	match.ID = r.id("match")
+	match.DifficultyVersion = r.difficulties[match.DifficultyID].Version
+	match.StartedAt = time.Now().UTC()
+	r.matches[match.ID] = match
+	return match, nil
+}
+
+func (r memoryMatchRepo) Get(ctx context.Context, id string) (entity.Match, error) {
+	match, ok := r.matches[id]
+	if !ok {
+		return entity.Match{}, pgx.ErrNoRows
+	}
+	return match, nil
+}
+
+func (r memoryMatchRepo) Update(ctx context.Context, match entity.Match) (entity.Match, error) {
+	previous, ok := r.matches[match.ID]
+	if !ok {
+		return entity.Match{}, pgx.ErrNoRows
+	}
+	r.matches[match.ID] = match
+	if previous.IsActive && !match.IsActive {
+		r.emit(entity.EventMatchFinished, match.ID, match)
+	}
+	return match, nil
+}
+
+func (r memoryMatchRepo) Finish(ctx context.Context, match entity.Match) (entity.Match, error) {
+	previous, ok := r.matches[match.ID]
+	if !ok || !previous.IsActive {
+		return entity.Match{}, pgx.ErrNoRows
+	}
+	return r.Update(ctx, match)
+}
+
+func (r memoryMatchRepo) GetActiveBySession(ctx context.Context, sessionID string) (entity.Match, error) {
+	for _, m := range r.matches {
+		if m.SessionID == sessionID && m.IsActive {
+			return m, nil
+		}
+	}
+	return entity.Match{}, pgx.ErrNoRows
+}
+
+// ListBySession walks the IDs handed out so far to list matches in the order
+// they were started.
+func (r memoryMatchRepo) ListBySession(ctx context.Context, sessionID string) ([]entity.Match, error) {
+	var matches []entity.Match
+	for n := 1; n <= r.nextID; n++ {
+		if m, ok := r.matches["match-"+strconv.Itoa(n)]; ok && m.SessionID == sessionID {
+			matches = append(matches, m)
+		}
+	}
+	return matches, nil
+}
+
+type memoryMoveRepo struct{ *memoryStore }
+
+func (r memoryMoveRepo) Create(ctx context.Context, move entity.Move) (entity.Move, error) {
+	move.ID = r.id("move")
+	r.moves[move.MatchID] = append(r.moves[move.MatchID], move)
+	r.emit(entity.EventMoveRecorded, move.MatchID, move)
+	return move, nil
+}
+
+func (r memoryMoveRepo) GetByMatch(ctx context.Context, matchID string) ([]entity.Move, error) {
+	return r.moves[matchID], nil
+}
+
+func (r memoryMoveRepo) GetLastByMatch(ctx context.Context, matchID string) (entity.Move, error) {
+	moves := r.moves[matchID]
+	if len(moves) == 0 {
+		return entity.Move{}, pgx.ErrNoRows
+	}
+	return moves[len(moves)-1], nil
+}
+
+func (r memoryMoveRepo) GetBySeq(ctx context.Context, matchID string, seq int) (entity.Move, error) {
+	for _, mv := range r.moves[matchID] {
+		if mv.Seq == seq {
+			return mv, nil
+		}
+	}
+	return entity.Move{}, pgx.ErrNoRows
+}
+
+type memoryPauseRepo struct{ *memoryStore }
+
+func (r memoryPauseRepo) Pause(ctx context.Context, matchID string, at time.Time) (entity.MatchPause, error) {
+	return entity.MatchPause{}, pgx.ErrNoRows
+}
+
+func (r memoryPauseRepo) Resume(ctx context.Context, matchID string, at time.Time) (entity.MatchPause, error) {
+	return entity.MatchPause{}, pgx.ErrNoRows
+}
+
+func (r memoryPauseRepo) ListByMatch(ctx context.Context, matchID string) ([]entity.MatchPause, error) {
+	return nil, nil
+}
+
+func (r memoryPauseRepo) ListTimedOut(ctx context.Context, limit int) ([]entity.Match, error) {
+	return nil, nil
+}
+
+type memoryDifficultyRepo struct{ *memoryStore }
+
+func (r memoryDifficultyRepo) GetByID(ctx context.Context, id int) (entity.Difficulty, error) {
+	d, ok := r.difficulties[id]
+	if !ok {
+		return entity.Difficulty{}, pgx.ErrNoRows
+	}
+	return d, nil
+}
+
+func (r memoryDifficultyRepo) GetAll(ctx context.Context) ([]entity.Difficulty, error) {
+	var all []entity.Difficulty
+	for _, d := range r.difficulties {
+		all = append(all, d)
+	}
+	return all, nil
+}
+
+func (r memoryDifficultyRepo) GetVersion(ctx context.Context, id, version int) (entity.Difficulty, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r memoryDifficultyRepo) ListVersions(ctx context.Context, id int) ([]entity.Difficulty, error) {
+	d, err := r.GetByID(ctx, id)
+	return []entity.Difficulty{d}, err
+}
+
+func (r memoryDifficultyRepo) Create(ctx context.Context, difficulty entity.Difficulty) (entity.Difficulty, error) {
+	difficulty.ID = len(r.difficulties) + 1
+	difficulty.Version = 1
+	r.difficulties[difficulty.ID] = difficulty
+	return difficulty, nil
+}
+
+func (r memoryDifficultyRepo) Update(ctx context.Context, difficulty entity.Difficulty) (entity.Difficulty, error) {
+	return difficulty, nil
+}
+
+func (r memoryDifficultyRepo) Retire(ctx context.Context, id int) (entity.Difficulty, error) {
+	return r.GetByID(ctx, id)
+}
+
+type memoryHintRepo struct{ *memoryStore }
+
+func (r memoryHintRepo) Create(ctx context.Context, hint entity.Hint) (entity.Hint, error) {
+	hint.ID = r.id("hint")
+	hint.Seq = len(r.moves[hint.MatchID])
+	hint.CreatedAt = time.Now().UTC()
+	r.hints[hint.MatchID] = append(r.hints[hint.MatchID], hint)
+	r.emit(entity.EventHintRecorded, hint.MatchID, hint)
+	return hint, nil
+}
+
+func (r memoryHintRepo) ListByMatch(ctx context.Context, matchID string) ([]entity.Hint, error) {
+	return r.hints[matchID], nil
+}
+
+func (r memoryHintRepo) Dismiss(ctx context.Context, matchID, id string) (entity.Hint, error) {
+	return entity.Hint{}, pgx.ErrNoRows
+}
+
+func (r memoryHintRepo) ListUnresolved(ctx context.Context, matchID string, seq int) ([]entity.Hint, error) {
+	var hints []entity.Hint
+	for _, h := range r.hints[matchID] {
+		if h.Seq == seq && h.Followed == nil {
+			hints = append(hints, h)
+		}
+	}
+	return hints, nil
+}
+
+func (r memoryHintRepo) Resolve(ctx context.Context, matchID, id string, followed bool) error {
+	for i, h := range r.hints[matchID] {
+		if h.ID == id && h.Followed == nil {
+			r.hints[matchID][i].Followed = &followed
+		}
+	}
+	return nil
+}
+
+func (r memoryHintRepo) SummaryByMatch(ctx context.Context, matchID string) (entity.HintSummary, error) {
+	return entity.HintSummary{}, nil
+}
+
+type memoryWebhookRepo struct{ *memoryStore }
+
+func (r memoryWebhookRepo) Create(ctx context.Context, webhook entity.Webhook) (entity.Webhook, error) {
+	webhook.ID = r.id("webhook")
+	webhook.IsActive = true
+	r.webhooks[webhook.ID] = webhook
+	return webhook, nil
+}
+
+func (r memoryWebhookRepo) Get(ctx context.Context, id string) (entity.Webhook, error) {
+	webhook, ok := r.webhooks[id]
+	if !ok {
+		return entity.Webhook{}, pgx.ErrNoRows
+	}
+	return webhook, nil
+}
+
+func (r memoryWebhookRepo) List(ctx context.Context) ([]entity.Webhook, error) {
+	webhooks := make([]entity.Webhook, 0, len(r.webhooks))
+	for _, w := range r.webhooks {
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, nil
+}
+
+func (r memoryWebhookRepo) Deactivate(ctx context.Context, id string) (entity.Webhook, error) {
+	webhook, err := r.Get(ctx, id)
+	if err != nil {
+		return entity.Webhook{}, err
+	}
+	webhook.IsActive = false
+	r.webhooks[id] = webhook
+	return webhook, nil
+}
+
+func (r memoryWebhookRepo) EnqueueDeliveries(ctx context.Context, event entity.OutboxEvent) (int, error) {
+	n := 0
+	for _, w := range r.webhooks {
+		if !w.IsActive || !slices.Contains(w.EventTypes, event.Type) {
+			continue
+		}
+		r.deliveries = append(r.deliveries, entity.WebhookDelivery{
+			ID:        int64(len(r.deliveries) + 1),
+			WebhookID: w.ID,
+			EventID:   event.ID,
+			EventType: event.Type,
+			Payload:   event.Payload,
+			Status:    entity.WebhookDeliveryPending,
+		})
+		n++
+	}
+	return n, nil
+}
+
+func (r memoryWebhookRepo) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.WebhookDelivery, error) {
+	var due []entity.WebhookDelivery
+	for i := range r.deliveries {
+		if r.deliveries[i].Status == entity.WebhookDeliveryPending && len(due) < limit {
+			r.deliveries[i].Attempts++
+			due = append(due, r.deliveries[i])
+		}
+	}
+	return due, nil
+}
+
+func (r memoryWebhookRepo) MarkDelivered(ctx context.Context, id int64, statusCode int) error {
+	d := &r.deliveries[id-1]
+	d.Status, d.LastStatusCode = entity.WebhookDeliveryDelivered, &statusCode
+	return nil
+}
+
+func (r memoryWebhookRepo) MarkFailed(ctx context.Context, id int64, statusCode *int, cause string, retryAt *time.Time) error {
+	d := &r.deliveries[id-1]
+	d.LastStatusCode, d.LastError = statusCode, &cause
+	if retryAt == nil {
+		d.Status = entity.WebhookDeliveryFailed
+	}
+	return nil
+}
+
+func (r memoryWebhookRepo) ListDeliveries(ctx context.Context, webhookID string, limit int) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	for _, d := range r.deliveries {
+		if d.WebhookID == webhookID {
+			deliveries = append(deliveries, d)
+		}
+	}
+	return deliveries, nil
+}
+
+// memoryCurriculumRepo serves a single curriculum as the default one.
+type memoryCurriculumRepo struct{ curriculum entity.Curriculum }
+
+func (r memoryCurriculumRepo) Create(ctx context.Context, curriculum entity.Curriculum) (entity.Curriculum, error) {
+	return curriculum, nil
+}
+
+func (r memoryCurriculumRepo) Get(ctx context.Context, id int) (entity.Curriculum, error) {
+	if id != r.curriculum.ID {
+		return entity.Curriculum{}, pgx.ErrNoRows
+	}
+	return r.curriculum, nil
+}
+
+func (r memoryCurriculumRepo) GetDefault(ctx context.Context) (entity.Curriculum, error) {
+	return r.curriculum, nil
+}
+
+func (r memoryCurriculumRepo) List(ctx context.Context) ([]entity.Curriculum, error) {
+	return []entity.Curriculum{r.curriculum}, nil
+}
+
+// newMemoryHandler builds a handler on an in-memory store with the core
+// services, plus whatever opts enable.
+func newMemoryHandler(store *memoryStore, opts ...Option) *Handler {
+	gin.SetMode(gin.TestMode)
+	return NewHandler(
+		usecase.NewSessionService(memorySessionRepo{store}),
+		usecase.NewMatchService(memoryMatchRepo{store}, memoryPauseRepo{store}, nil, nil),
+		usecase.NewMoveService(memoryMoveRepo{store}, memoryPauseRepo{store}, nil, nil),
+		usecase.NewDifficultyService(memoryDifficultyRepo{store}),
+		opts...,
+	)
+}
+
+// serveJSON sends body as JSON to the handler and decodes the response into
+// out when it is not nil.
+func serveJSON(t *testing.T, h *Handler, method, target string, body, out any) int {
+	t.Helper()
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, target, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.Router().ServeHTTP(rec, req)
+	if out != nil && rec.Code < http.StatusBadRequest {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("decode %s %s: %v", method, target, err)
+		}
+	}
+	return rec.Code
+}
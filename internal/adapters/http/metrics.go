@@ -0,0 +1,24 @@
+package httpadapter
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const unmatchedRoute = "unmatched"
+
+// metricsMiddleware labels requests by route template rather than raw path so
+// match and session IDs do not explode the series count.
+func (h *Handler) metricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		h.metrics.ObserveHTTP(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,72 @@
+package httpadapter
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/platform/metrics"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registry := metrics.New()
+	handler := NewHandler(
+		usecase.NewSessionService(&stubSessionRepo{}),
+		usecase.NewMatchService(nil, nil, registry, nil),
+		usecase.NewMoveService(nil, nil, registry, nil),
+		nil,
+		WithMetrics(registry),
+	)
+	router := handler.Router()
+
+	req := httptest.NewRequest(http.MethodPost, "/game", bytes.NewBufferString(`{"game_id":"nope"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodPost, "/matches/abc/moves", bytes.NewBufferString(`{`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/does-not-exist", nil))
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	text := string(body)
+
+	require.Contains(t, text, `ranas_http_requests_total{method="POST",route="/game",status="400"} 1`)
+	require.Contains(t, text, `ranas_http_requests_total{method="POST",route="/matches/:matchID/moves",status="400"} 1`)
+	require.Contains(t, text, `ranas_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	require.Contains(t, text, `ranas_http_request_duration_seconds_count{method="POST",route="/game"} 1`)
+	require.Contains(t, text, `ranas_game_moves_rejected_total{reason="invalid_payload"} 1`)
+}
+
+func TestMissingMatchRejectsOnlyMoves(t *testing.T) {
+	registry := metrics.New()
+	store := newMemoryStore()
+	handler := NewHandler(
+		usecase.NewSessionService(memorySessionRepo{store}),
+		usecase.NewMatchService(memoryMatchRepo{store}, memoryPauseRepo{store}, registry, nil),
+		usecase.NewMoveService(memoryMoveRepo{store}, memoryPauseRepo{store}, registry, nil),
+		usecase.NewDifficultyService(memoryDifficultyRepo{store}),
+		WithMetrics(registry),
+	)
+
+	require.Equal(t, http.StatusNotFound, serveJSON(t, handler, http.MethodPost, "/matches/nope/pause", nil, nil))
+	require.Equal(t, http.StatusNotFound, serveJSON(t, handler, http.MethodPost, "/matches/nope/moves", map[string]any{"movement": []int{2, 3}}, nil))
+
+	resp := httptest.NewRecorder()
+	handler.Router().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Contains(t, resp.Body.String(), `ranas_game_moves_rejected_total{reason="match_not_found"} 1`)
+}
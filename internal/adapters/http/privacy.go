@@ -0,0 +1,119 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/platform/logging"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+var errInvalidAuditLimit = errors.New("limit must be between 1 and " + strconv.Itoa(maxAuditLimit))
+
+// WithPrivacy enables the admin routes answering data subject requests.
+func WithPrivacy(privacy *usecase.PrivacyService) Option {
+	return func(h *Handler) { h.privacy = privacy }
+}
+
+// adminActor is the name of the admin token the request was made with.
+func adminActor(c *gin.Context) string {
+	return c.GetString(actorKey)
+}
+
+// privacyRequest starts the audit record of a data subject operation on
+// playerID made by the calling admin.
+func privacyRequest(c *gin.Context, playerID string) entity.PrivacyAudit {
+	request := entity.PrivacyAudit{PlayerID: playerID, Actor: adminActor(c)}
+	if id := logging.RequestID(c.Request.Context()); id != "" {
+		request.RequestID = &id
+	}
+	return request
+}
+
+// playerIDParam reads the playerID path parameter, answering 400 when it is
+// not a UUID.
+func playerIDParam(c *gin.Context) (string, bool) {
+	playerID := c.Param("playerID")
+	if _, err := uuid.Parse(playerID); err != nil {
+		respondError(c, http.StatusBadRequest, errInvalidPlayerID)
+		return "", false
+	}
+	return playerID, true
+}
+
+func (h *Handler) handleExportPlayerData(c *gin.Context) {
+	playerID, ok := playerIDParam(c)
+	if !ok {
+		return
+	}
+	data, err := h.privacy.Export(c.Request.Context(), privacyRequest(c, playerID))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="player-`+playerID+`.json"`)
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *Handler) handleDeletePlayerData(c *gin.Context) {
+	playerID, ok := playerIDParam(c)
+	if !ok {
+		return
+	}
+	audit, err := h.privacy.Delete(c.Request.Context(), privacyRequest(c, playerID))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, audit)
+}
+
+func (h *Handler) handleAnonymizePlayerData(c *gin.Context) {
+	playerID, ok := playerIDParam(c)
+	if !ok {
+		return
+	}
+	audit, err := h.privacy.Anonymize(c.Request.Context(), privacyRequest(c, playerID))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, audit)
+}
+
+func (h *Handler) handleListPrivacyAudit(c *gin.Context) {
+	var playerID *string
+	if v := c.Query("player_id"); v != "" {
+		if _, err := uuid.Parse(v); err != nil {
+			respondError(c, http.StatusBadRequest, errInvalidPlayerID)
+			return
+		}
+		playerID = &v
+	}
+	limit := defaultAuditLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxAuditLimit {
+			respondError(c, http.StatusBadRequest, errInvalidAuditLimit)
+			return
+		}
+		limit = n
+	}
+
+	records, err := h.privacy.Audit(c.Request.Context(), playerID, limit)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, records)
+}
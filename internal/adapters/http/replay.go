@@ -0,0 +1,63 @@
+package httpadapter
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/adapters/render"
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+)
+
+var errInvalidReplayFormat = errors.New("format must be json, svg or gif")
+
+// WithReplays enables GET /matches/:matchID/replay.
+func WithReplays(replays *usecase.ReplayService) Option {
+	return func(h *Handler) { h.replays = replays }
+}
+
+func (h *Handler) handleGetReplay(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	var (
+		draw        func(io.Writer, usecase.Replay) error
+		contentType string
+	)
+	switch format {
+	case "json":
+	case "svg":
+		draw, contentType = render.SVG, "image/svg+xml"
+	case "gif":
+		draw, contentType = render.GIF, "image/gif"
+	default:
+		respondError(c, http.StatusBadRequest, errInvalidReplayFormat)
+		return
+	}
+
+	if h.rejectArchived(c, c.Param("matchID")) {
+		return
+	}
+	replay, err := h.replays.Replay(c.Request.Context(), c.Param("matchID"))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(c, http.StatusNotFound, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	if draw == nil {
+		c.JSON(http.StatusOK, replay)
+		return
+	}
+	var buf bytes.Buffer
+	if err := draw(&buf, replay); err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
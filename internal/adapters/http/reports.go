@@ -0,0 +1,174 @@
+package httpadapter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+// WithReports enables the aggregate research reports and, when admin tokens
+// are configured, cohort assignment.
+func WithReports(reports *usecase.ReportService) Option {
+	return func(h *Handler) { h.reports = reports }
+}
+
+type setCohortsRequest struct {
+	Cohorts []string `json:"cohorts"`
+}
+
+func (h *Handler) handleDifficultyReport(c *gin.Context) {
+	filter, err := parseReportFilter(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	reports, err := h.reports.Difficulties(c.Request.Context(), filter)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, reports)
+}
+
+func (h *Handler) handleCohortReport(c *gin.Context) {
+	filter, err := parseReportFilter(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	reports, err := h.reports.Cohorts(c.Request.Context(), filter)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, reports)
+}
+
+// handleArmReport compares the arms of the experiment given by the
+// experiment query parameter.
+func (h *Handler) handleArmReport(c *gin.Context) {
+	filter, err := parseReportFilter(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	reports, err := h.reports.Arms(c.Request.Context(), filter)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, reports)
+}
+
+func respondReportError(c *gin.Context, err error) {
+	if errors.Is(err, usecase.ErrInvalidReportRange) || errors.Is(err, usecase.ErrMissingExperiment) {
+		respondError(c, http.StatusBadRequest, err)
+	} else {
+		respondError(c, http.StatusInternalServerError, err)
+	}
+}
+
+func (h *Handler) handleGetPlayerCohorts(c *gin.Context) {
+	playerID := c.Param("playerID")
+	if _, err := uuid.Parse(playerID); err != nil {
+		respondError(c, http.StatusBadRequest, errInvalidPlayerID)
+		return
+	}
+	cohorts, err := h.reports.PlayerCohorts(c.Request.Context(), playerID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"player_id": playerID, "cohorts": cohorts})
+}
+
+func (h *Handler) handleSetPlayerCohorts(c *gin.Context) {
+	playerID := c.Param("playerID")
+	if _, err := uuid.Parse(playerID); err != nil {
+		respondError(c, http.StatusBadRequest, errInvalidPlayerID)
+		return
+	}
+	var req setCohortsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	cohorts, err := h.reports.SetPlayerCohorts(c.Request.Context(), playerID, req.Cohorts)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidCohort) {
+			respondError(c, http.StatusBadRequest, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"player_id": playerID, "cohorts": cohorts})
+}
+
+// parseReportFilter reads from, to, device, outcome, difficulty_id,
+// experiment and arm. Dates
+// are RFC 3339 timestamps or plain YYYY-MM-DD days in UTC.
+func parseReportFilter(c *gin.Context) (entity.ReportFilter, error) {
+	var filter entity.ReportFilter
+	var err error
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		return filter, err
+	}
+	if v, ok := c.GetQuery("device"); ok && v != "" {
+		filter.Device = &v
+	}
+	if v, ok := c.GetQuery("outcome"); ok && v != "" {
+		filter.Outcome = &v
+	}
+	if v, ok := c.GetQuery("difficulty_id"); ok && v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, errInvalidDifficultyID
+		}
+		filter.DifficultyID = &id
+	}
+	filter.Experiment, filter.Arm = parseArmQuery(c)
+	if filter.Arm != nil && filter.Experiment == nil {
+		return filter, usecase.ErrMissingExperiment
+	}
+	return filter, nil
+}
+
+// parseArmQuery reads the experiment and arm query parameters shared by
+// reports and exports.
+func parseArmQuery(c *gin.Context) (experiment, arm *string) {
+	if v := c.Query("experiment"); v != "" {
+		experiment = &v
+	}
+	if v := c.Query("arm"); v != "" {
+		arm = &v
+	}
+	return experiment, arm
+}
+
+func parseTimeQuery(c *gin.Context, key string) (*time.Time, error) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("%s must be an RFC 3339 timestamp or YYYY-MM-DD", key)
+}
+
+var errInvalidDifficultyID = errors.New("difficulty_id must be an integer")
@@ -0,0 +1,41 @@
+package httpadapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+func TestParseReportFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	parse := func(query string) (entity.ReportFilter, error) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/reports/cohorts?"+query, nil)
+		return parseReportFilter(c)
+	}
+
+	filter, err := parse("from=2025-01-01&to=2025-02-01T00:00:00Z&device=Quest&outcome=win&difficulty_id=2")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), *filter.From)
+	require.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), *filter.To)
+	require.Equal(t, "Quest", *filter.Device)
+	require.Equal(t, "win", *filter.Outcome)
+	require.Equal(t, 2, *filter.DifficultyID)
+
+	filter, err = parse("")
+	require.NoError(t, err)
+	require.Nil(t, filter.From)
+	require.Nil(t, filter.Device)
+
+	_, err = parse("from=yesterday")
+	require.Error(t, err)
+	_, err = parse("difficulty_id=hard")
+	require.Error(t, err)
+}
@@ -0,0 +1,108 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/adapters/webhook"
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+func TestFinishSessionDeliversWebhook(t *testing.T) {
+	received := make(chan webhook.Envelope, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var envelope webhook.Envelope
+		if err := json.Unmarshal(body, &envelope); err == nil {
+			received <- envelope
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	store := newMemoryStore()
+	webhooks := usecase.NewWebhookService(memoryWebhookRepo{store}, webhook.NewSender(receiver.Client()))
+	_, err := webhooks.Register(context.Background(), receiver.URL, []string{entity.EventSessionFinished})
+	require.NoError(t, err)
+	h := newMemoryHandler(store, WithWebhooks(webhooks))
+
+	var session entity.Session
+	require.Equal(t, http.StatusCreated, serveJSON(t, h, http.MethodPost, "/sessions",
+		map[string]any{"game_id": "11111111-1111-1111-1111-111111111111"}, &session))
+	var match entity.Match
+	require.Equal(t, http.StatusCreated, serveJSON(t, h, http.MethodPost, "/matches",
+		map[string]any{"session_id": session.ID, "difficulty_id": 1}, &match))
+
+	finish := "/sessions/" + session.ID + "/finish"
+	require.Equal(t, http.StatusConflict, serveJSON(t, h, http.MethodPost, finish, nil, nil), "the match is still active")
+	require.Equal(t, http.StatusOK, serveJSON(t, h, http.MethodPost, "/matches/"+match.ID+"/finish",
+		map[string]any{"outcome": entity.OutcomeLose}, nil))
+	var finished entity.Session
+	require.Equal(t, http.StatusOK, serveJSON(t, h, http.MethodPost, finish, nil, &finished))
+	require.True(t, finished.IsFinished)
+	require.NotNil(t, finished.EndedAt)
+	require.Equal(t, http.StatusConflict, serveJSON(t, h, http.MethodPost, finish, nil, nil))
+	require.Equal(t, http.StatusConflict, serveJSON(t, h, http.MethodPost, "/matches",
+		map[string]any{"session_id": session.ID, "difficulty_id": 1}, nil))
+
+	// Play the relay: outbox events reach the webhook service, which queues
+	// the subscribed ones and delivers them.
+	for _, event := range store.outbox {
+		require.NoError(t, webhooks.Enqueue(context.Background(), event))
+	}
+	attempted, err := webhooks.DeliverDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, attempted, "only SessionFinished is subscribed")
+
+	envelope := <-received
+	require.Equal(t, entity.EventSessionFinished, envelope.Type)
+	var payload entity.Session
+	require.NoError(t, json.Unmarshal(envelope.Data, &payload))
+	require.Equal(t, session.ID, payload.ID)
+	require.True(t, payload.IsFinished)
+	require.Equal(t, entity.WebhookDeliveryDelivered, store.deliveries[0].Status)
+}
+
+// failingExperimentRepo has one active experiment and cannot store
+// assignments.
+type failingExperimentRepo struct{}
+
+func (failingExperimentRepo) Create(ctx context.Context, experiment entity.Experiment) (entity.Experiment, error) {
+	return experiment, nil
+}
+
+func (failingExperimentRepo) List(ctx context.Context) ([]entity.Experiment, error) {
+	return nil, nil
+}
+
+func (failingExperimentRepo) ListActive(ctx context.Context) ([]entity.Experiment, error) {
+	return []entity.Experiment{{ID: 1, Key: "hints", Unit: entity.ExperimentUnitSession, Arms: []entity.ExperimentArm{{Name: "control", Weight: 1}}}}, nil
+}
+
+func (failingExperimentRepo) Deactivate(ctx context.Context, id int) (entity.Experiment, error) {
+	return entity.Experiment{}, nil
+}
+
+func (failingExperimentRepo) Assign(ctx context.Context, assignment entity.ExperimentAssignment) (entity.ExperimentAssignment, error) {
+	return entity.ExperimentAssignment{}, errors.New("connection reset")
+}
+
+func TestCreateSessionSurvivesFailedAssignment(t *testing.T) {
+	store := newMemoryStore()
+	h := newMemoryHandler(store, WithExperiments(usecase.NewExperimentService(failingExperimentRepo{})))
+
+	var created sessionResponse
+	require.Equal(t, http.StatusCreated, serveJSON(t, h, http.MethodPost, "/sessions",
+		map[string]any{"game_id": "11111111-1111-1111-1111-111111111111"}, &created))
+	require.NotEmpty(t, created.ID)
+	require.Empty(t, created.Experiments)
+	require.Len(t, store.sessions, 1, "the client has no reason to retry")
+}
@@ -0,0 +1,68 @@
+package httpadapter
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	streamHeartbeat   = 15 * time.Second
+	streamEventLagged = "lagged"
+)
+
+func (h *Handler) handleStreamMatch(c *gin.Context) {
+	matchID := c.Param("matchID")
+	ctx := c.Request.Context()
+
+	// Subscribe before taking the snapshot so nothing published in between
+	// is lost; at worst the first update repeats what the snapshot shows.
+	updates, err := h.streams.Subscribe(ctx, matchID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	snapshot, err := h.streams.Snapshot(ctx, matchID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(c, http.StatusNotFound, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	// Streams outlive the server-wide write timeout.
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.SSEvent(snapshot.Event, snapshot)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				if ctx.Err() == nil {
+					c.SSEvent(streamEventLagged, gin.H{"match_id": matchID})
+				}
+				return false
+			}
+			c.SSEvent(update.Event, update)
+			return true
+		case <-heartbeat.C:
+			_, _ = io.WriteString(w, ": ping\n\n")
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	})
+}
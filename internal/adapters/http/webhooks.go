@@ -0,0 +1,103 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+const (
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 500
+)
+
+// WithWebhooks enables webhook management under /admin/webhooks. It has no
+// effect unless admin tokens are configured as well.
+func WithWebhooks(webhooks *usecase.WebhookService) Option {
+	return func(h *Handler) { h.webhooks = webhooks }
+}
+
+type createWebhookRequest struct {
+	URL        string   `json:"url" binding:"required"`
+	EventTypes []string `json:"event_types" binding:"required"`
+}
+
+// createWebhookResponse is the only response that carries the signing secret.
+type createWebhookResponse struct {
+	entity.Webhook
+	Secret string `json:"secret"`
+}
+
+func (h *Handler) handleCreateWebhook(c *gin.Context) {
+	var req createWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	webhook, err := h.webhooks.Register(c.Request.Context(), req.URL, req.EventTypes)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidWebhook) {
+			respondError(c, http.StatusBadRequest, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	setAuditID(c, webhook.ID)
+	c.JSON(http.StatusCreated, createWebhookResponse{Webhook: webhook, Secret: webhook.Secret})
+}
+
+func (h *Handler) handleListWebhooks(c *gin.Context) {
+	webhooks, err := h.webhooks.List(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, webhooks)
+}
+
+func (h *Handler) handleDeleteWebhook(c *gin.Context) {
+	webhook, err := h.webhooks.Deactivate(c.Request.Context(), c.Param("webhookID"))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(c, http.StatusNotFound, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, webhook)
+}
+
+func (h *Handler) handleListWebhookDeliveries(c *gin.Context) {
+	limit := defaultDeliveriesLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxDeliveriesLimit {
+			respondError(c, http.StatusBadRequest, errInvalidLimit)
+			return
+		}
+		limit = n
+	}
+
+	deliveries, err := h.webhooks.Deliveries(c.Request.Context(), c.Param("webhookID"), limit)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(c, http.StatusNotFound, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, deliveries)
+}
+
+var errInvalidLimit = errors.New("limit must be between 1 and " + strconv.Itoa(maxDeliveriesLimit))
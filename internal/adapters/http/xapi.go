@@ -0,0 +1,30 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+)
+
+// WithXAPI enables GET /matches/:matchID/xapi, a preview of the statements
+// sent to the learning record store for a match.
+func WithXAPI(statements *usecase.XAPIService) Option {
+	return func(h *Handler) { h.xapi = statements }
+}
+
+func (h *Handler) handlePreviewXAPI(c *gin.Context) {
+	statements, err := h.xapi.Preview(c.Request.Context(), c.Param("matchID"))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(c, http.StatusNotFound, err)
+		} else {
+			respondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, statements)
+}
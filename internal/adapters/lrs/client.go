@@ -0,0 +1,103 @@
+// Package lrs delivers xAPI statements to a learning record store.
+package lrs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+	"github.com/org/ranas-bdi-backend/internal/domain/xapi"
+)
+
+const maxResponseBytes = 64 << 10
+
+const (
+	defaultHomePage     = "https://ranas-bdi.local"
+	defaultActivityBase = "https://ranas-bdi.local/xapi/activities/ranas"
+)
+
+// Config locates the LRS and names the actors and activities in statements.
+// Endpoint is the xAPI base URL; statements are posted to
+// Endpoint + "/statements".
+type Config struct {
+	Endpoint     string
+	Username     string
+	Password     string
+	HomePage     string
+	ActivityBase string
+}
+
+// ConfigFromEnv reads XAPI_LRS_ENDPOINT, XAPI_LRS_USERNAME,
+// XAPI_LRS_PASSWORD, XAPI_HOMEPAGE and XAPI_ACTIVITY_BASE. An empty Endpoint
+// means no LRS is configured.
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Endpoint:     strings.TrimRight(os.Getenv("XAPI_LRS_ENDPOINT"), "/"),
+		Username:     os.Getenv("XAPI_LRS_USERNAME"),
+		Password:     os.Getenv("XAPI_LRS_PASSWORD"),
+		HomePage:     os.Getenv("XAPI_HOMEPAGE"),
+		ActivityBase: os.Getenv("XAPI_ACTIVITY_BASE"),
+	}
+	if cfg.HomePage == "" {
+		cfg.HomePage = defaultHomePage
+	}
+	if cfg.ActivityBase == "" {
+		cfg.ActivityBase = defaultActivityBase
+	}
+	return cfg
+}
+
+// Builder returns the statement builder for this deployment.
+func (c Config) Builder() xapi.Builder {
+	return xapi.NewBuilder(c.HomePage, c.ActivityBase)
+}
+
+type Client struct {
+	cfg    Config
+	client *http.Client
+}
+
+var _ ports.LRSClient = (*Client)(nil)
+
+func NewClient(cfg Config, client *http.Client) *Client {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Client{cfg: cfg, client: client}
+}
+
+// SendStatements posts a batch of statements in a single request.
+func (c *Client) SendStatements(ctx context.Context, statements []json.RawMessage) error {
+	body, err := json.Marshal(statements)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.Endpoint+"/statements", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Experience-API-Version", xapi.Version)
+	if c.cfg.Username != "" {
+		req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("lrs responded %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
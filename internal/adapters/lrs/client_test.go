@@ -0,0 +1,58 @@
+package lrs
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLRS accepts statement batches like a learning record store would and
+// keeps what it received.
+type fakeLRS struct {
+	received []json.RawMessage
+	status   int
+}
+
+func (f *fakeLRS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, pass, ok := r.BasicAuth()
+	if r.URL.Path != "/xapi/statements" || r.Header.Get("X-Experience-API-Version") != "1.0.3" ||
+		!ok || user != "key" || pass != "secret" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if f.status != 0 {
+		w.WriteHeader(f.status)
+		_, _ = io.WriteString(w, "statement conflict")
+		return
+	}
+	var batch []json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.received = append(f.received, batch...)
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestClientSendStatements(t *testing.T) {
+	lrs := &fakeLRS{}
+	srv := httptest.NewServer(lrs)
+	defer srv.Close()
+
+	client := NewClient(Config{Endpoint: srv.URL + "/xapi", Username: "key", Password: "secret"}, srv.Client())
+	batch := []json.RawMessage{json.RawMessage(`{"id":"a"}`), json.RawMessage(`{"id":"b"}`)}
+
+	require.NoError(t, client.SendStatements(context.Background(), batch))
+	require.Len(t, lrs.received, 2)
+	require.JSONEq(t, `{"id":"b"}`, string(lrs.received[1]))
+
+	lrs.status = http.StatusConflict
+	err := client.SendStatements(context.Background(), batch)
+	require.ErrorContains(t, err, "409")
+	require.ErrorContains(t, err, "statement conflict")
+}
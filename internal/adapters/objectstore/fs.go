@@ -0,0 +1,87 @@
+// Package objectstore holds the ObjectStore implementations archives are
+// written to.
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+var errInvalidKey = errors.New("invalid object key")
+
+// FS stores objects as files under a root directory, keys being slash
+// separated paths relative to it.
+type FS struct {
+	root string
+}
+
+var _ ports.ObjectStore = (*FS)(nil)
+
+func NewFS(root string) *FS {
+	return &FS{root: root}
+}
+
+// Put writes to a temporary file renamed into place, so readers never see a
+// partial object.
+func (s *FS) Put(ctx context.Context, key string, body io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".put-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *FS) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (s *FS) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path maps key to a file under root, refusing keys that would escape it.
+func (s *FS) path(key string) (string, error) {
+	if !fs.ValidPath(key) || key == "." || strings.Contains(key, `\`) {
+		return "", fmt.Errorf("%w: %q", errInvalidKey, key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,35 @@
+package objectstore
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFSPutGetDelete(t *testing.T) {
+	ctx := context.Background()
+	store := NewFS(t.TempDir())
+
+	require.NoError(t, store.Put(ctx, "moves/m-1.jsonl.gz", strings.NewReader("first")))
+	require.NoError(t, store.Put(ctx, "moves/m-1.jsonl.gz", strings.NewReader("second")))
+
+	r, err := store.Get(ctx, "moves/m-1.jsonl.gz")
+	require.NoError(t, err)
+	body, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, "second", string(body))
+
+	require.NoError(t, store.Delete(ctx, "moves/m-1.jsonl.gz"))
+	require.NoError(t, store.Delete(ctx, "moves/m-1.jsonl.gz"))
+	_, err = store.Get(ctx, "moves/m-1.jsonl.gz")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	for _, key := range []string{"../outside", "/abs", "moves/../../x", ""} {
+		require.Error(t, store.Put(ctx, key, strings.NewReader("x")), key)
+	}
+}
@@ -0,0 +1,95 @@
+package realtime
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+// DefaultBuffer is how many undelivered events a subscriber may accumulate
+// before the hub drops it.
+const DefaultBuffer = 64
+
+// Hub is an in-process publish/subscribe fan-out keyed by match ID. Publish
+// never blocks: a subscriber whose buffer is full is disconnected so that one
+// slow observer cannot stall move submission for everyone else.
+type Hub struct {
+	buffer int
+
+	mu   sync.Mutex
+	subs map[string]map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	ch     chan entity.MatchEvent
+	closed bool
+}
+
+var (
+	_ ports.EventPublisher  = (*Hub)(nil)
+	_ ports.EventSubscriber = (*Hub)(nil)
+)
+
+func NewHub(buffer int) *Hub {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	return &Hub{buffer: buffer, subs: make(map[string]map[*subscriber]struct{})}
+}
+
+func (h *Hub) Publish(ctx context.Context, event entity.MatchEvent) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subs[event.MatchID] {
+		select {
+		case sub.ch <- event:
+		default:
+			slog.WarnContext(ctx, "dropping slow match subscriber", "match_id", event.MatchID)
+			h.removeLocked(event.MatchID, sub)
+		}
+	}
+	return nil
+}
+
+func (h *Hub) Subscribe(ctx context.Context, matchID string) (<-chan entity.MatchEvent, error) {
+	sub := &subscriber{ch: make(chan entity.MatchEvent, h.buffer)}
+
+	h.mu.Lock()
+	if h.subs[matchID] == nil {
+		h.subs[matchID] = make(map[*subscriber]struct{})
+	}
+	h.subs[matchID][sub] = struct{}{}
+	h.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		h.mu.Lock()
+		h.removeLocked(matchID, sub)
+		h.mu.Unlock()
+	}()
+
+	return sub.ch, nil
+}
+
+// Subscribers reports how many observers are attached to matchID.
+func (h *Hub) Subscribers(matchID string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs[matchID])
+}
+
+func (h *Hub) removeLocked(matchID string, sub *subscriber) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	close(sub.ch)
+	delete(h.subs[matchID], sub)
+	if len(h.subs[matchID]) == 0 {
+		delete(h.subs, matchID)
+	}
+}
@@ -0,0 +1,65 @@
+package realtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+func TestHubDeliversToMatchSubscribers(t *testing.T) {
+	hub := NewHub(4)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := hub.Subscribe(ctx, "match-1")
+	require.NoError(t, err)
+	other, err := hub.Subscribe(ctx, "match-2")
+	require.NoError(t, err)
+
+	require.NoError(t, hub.Publish(ctx, entity.MatchEvent{Type: entity.MatchEventMoveRecorded, MatchID: "match-1", Seq: 1}))
+
+	select {
+	case ev := <-events:
+		require.Equal(t, 1, ev.Seq)
+	case <-time.After(time.Second):
+		t.Fatal("event not delivered")
+	}
+	require.Empty(t, other)
+}
+
+func TestHubDropsSlowSubscriber(t *testing.T) {
+	hub := NewHub(2)
+	ctx := context.Background()
+
+	slow, err := hub.Subscribe(ctx, "match-1")
+	require.NoError(t, err)
+
+	for seq := 1; seq <= 3; seq++ {
+		require.NoError(t, hub.Publish(ctx, entity.MatchEvent{MatchID: "match-1", Seq: seq}))
+	}
+
+	require.Equal(t, 0, hub.Subscribers("match-1"))
+	var seqs []int
+	for ev := range slow {
+		seqs = append(seqs, ev.Seq)
+	}
+	require.Equal(t, []int{1, 2}, seqs)
+}
+
+func TestHubUnsubscribesOnCancel(t *testing.T) {
+	hub := NewHub(1)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events, err := hub.Subscribe(ctx, "match-1")
+	require.NoError(t, err)
+	require.Equal(t, 1, hub.Subscribers("match-1"))
+
+	cancel()
+	_, open := <-events
+	require.False(t, open)
+	require.Equal(t, 0, hub.Subscribers("match-1"))
+}
@@ -0,0 +1,228 @@
+// Package render draws match replays as animated images for embedding in
+// reports.
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/game"
+)
+
+// Playback keeps the real rhythm of the match but clamps each frame so long
+// pauses do not stall the animation and quick moves stay visible.
+const (
+	minFrameDelay  = 250 * time.Millisecond
+	maxFrameDelay  = 2 * time.Second
+	lastFrameDelay = 2 * time.Second
+
+	cellSize = 48
+	padding  = 8
+)
+
+var (
+	colorBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	colorBlock      = color.RGBA{0xe0, 0xe0, 0xe0, 0xff}
+	colorLeft       = color.RGBA{0x2e, 0x7d, 0x32, 0xff}
+	colorRight      = color.RGBA{0x8d, 0x6e, 0x63, 0xff}
+	colorError      = color.RGBA{0xc6, 0x28, 0x28, 0xff}
+	colorPause      = color.RGBA{0xf9, 0xa8, 0x25, 0xff}
+	colorHint       = color.RGBA{0x15, 0x65, 0xc0, 0xff}
+)
+
+type frame struct {
+	board game.Board
+	delay time.Duration
+	// highlight is the block the frog landed on, or -1 for the initial board.
+	highlight    int
+	isCorrect    bool
+	interruption bool
+	caption      string
+	// hints were shown on board, before the next move.
+	hints []usecase.ReplayHint
+}
+
+func frames(r usecase.Replay) []frame {
+	out := make([]frame, 0, len(r.Frames)+1)
+	out = append(out, frame{board: r.Initial, highlight: -1, isCorrect: true, caption: "start", hints: r.InitialHints})
+	for _, f := range r.Frames {
+		out[len(out)-1].delay = clampDelay(time.Duration(f.ElapsedMs) * time.Millisecond)
+		out = append(out, frame{
+			board:        f.Board,
+			highlight:    f.To,
+			isCorrect:    f.IsCorrect,
+			interruption: f.Interruption,
+			caption:      fmt.Sprintf("move %d · %.1fs", f.Seq, float64(f.AtMs)/1000),
+			hints:        f.Hints,
+		})
+	}
+	out[len(out)-1].delay = lastFrameDelay
+	return out
+}
+
+// hinted returns the blocks pointed at by the hints of a frame, and whether
+// any hint had no target at all.
+func hinted(hints []usecase.ReplayHint) (blocks map[int]bool, untargeted bool) {
+	blocks = make(map[int]bool)
+	for _, h := range hints {
+		if h.FromIdx == nil {
+			untargeted = true
+			continue
+		}
+		blocks[*h.FromIdx] = true
+		if h.ToIdx != nil {
+			blocks[*h.ToIdx] = true
+		}
+	}
+	return blocks, untargeted
+}
+
+// hintCaption names the hints of a frame, e.g. "hint: frog (agent)".
+func hintCaption(hints []usecase.ReplayHint) string {
+	names := make([]string, len(hints))
+	for i, h := range hints {
+		names[i] = fmt.Sprintf("%s (%s)", h.HintType, h.Source)
+		if h.Dismissed {
+			names[i] += " dismissed"
+		}
+	}
+	return "hint: " + strings.Join(names, ", ")
+}
+
+func clampDelay(d time.Duration) time.Duration {
+	return min(max(d, minFrameDelay), maxFrameDelay)
+}
+
+func frogColor(c game.Cell) color.RGBA {
+	if c == game.Left {
+		return colorLeft
+	}
+	return colorRight
+}
+
+// SVG writes the replay as a looping SMIL animation.
+func SVG(w io.Writer, r usecase.Replay) error {
+	fs := frames(r)
+	var total time.Duration
+	for _, f := range fs {
+		total += f.delay
+	}
+	width := r.Blocks*cellSize + 2*padding
+	height := cellSize + 2*padding + 20
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hex(colorBackground))
+
+	var at time.Duration
+	for _, f := range fs {
+		begin := float64(at) / float64(total)
+		end := float64(at+f.delay) / float64(total)
+		at += f.delay
+
+		b.WriteString(`<g visibility="hidden">`)
+		fmt.Fprintf(&b, `<animate attributeName="visibility" values="hidden;visible;hidden" keyTimes="0;%.4f;%.4f" dur="%.3fs" calcMode="discrete" repeatCount="indefinite"/>`,
+			begin, end, total.Seconds())
+		blocks, _ := hinted(f.hints)
+		for i, c := range f.board {
+			x := padding + i*cellSize
+			if blocks[i] {
+				fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="8" fill="none" stroke="%s" stroke-width="2" stroke-dasharray="4 3"/>`,
+					x, padding, cellSize, cellSize, hex(colorHint))
+			}
+			stroke := "none"
+			if i == f.highlight {
+				stroke = hex(colorLeft)
+				if !f.isCorrect {
+					stroke = hex(colorError)
+				}
+			}
+			fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="6" fill="%s" stroke="%s" stroke-width="3"/>`,
+				x+2, padding+2, cellSize-4, cellSize-4, hex(colorBlock), stroke)
+			if c != game.Empty {
+				fmt.Fprintf(&b, `<circle cx="%d" cy="%d" r="%d" fill="%s"/>`,
+					x+cellSize/2, padding+cellSize/2, cellSize/3, hex(frogColor(c)))
+			}
+		}
+		caption := f.caption
+		if f.interruption {
+			caption += " · interruption"
+		}
+		if len(f.hints) > 0 {
+			caption += " · " + hintCaption(f.hints)
+		}
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-family="sans-serif" font-size="12">%s</text>`,
+			padding, height-padding, caption)
+		b.WriteString("</g>\n")
+	}
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// GIF writes the replay as a looping animated GIF.
+func GIF(w io.Writer, r usecase.Replay) error {
+	palette := color.Palette{colorBackground, colorBlock, colorLeft, colorRight, colorError, colorPause, colorHint}
+	index := func(c color.RGBA) uint8 { return uint8(palette.Index(c)) }
+
+	bounds := image.Rect(0, 0, r.Blocks*cellSize+2*padding, cellSize+2*padding)
+	anim := &gif.GIF{}
+	for _, f := range frames(r) {
+		img := image.NewPaletted(bounds, palette)
+		if f.interruption {
+			fillRect(img, bounds, index(colorPause))
+		}
+		// Hints are a bar under the blocks they point at, or under the whole
+		// board for hints without a target.
+		blocks, untargeted := hinted(f.hints)
+		if untargeted {
+			fillRect(img, image.Rect(padding, padding+cellSize+2, bounds.Max.X-padding, bounds.Max.Y-2), index(colorHint))
+		}
+		for i, c := range f.board {
+			cell := image.Rect(padding+i*cellSize, padding, padding+(i+1)*cellSize, padding+cellSize)
+			if blocks[i] {
+				fillRect(img, image.Rect(cell.Min.X+4, cell.Max.Y+2, cell.Max.X-4, bounds.Max.Y-2), index(colorHint))
+			}
+			if i == f.highlight && !f.isCorrect {
+				fillRect(img, cell.Inset(1), index(colorError))
+			}
+			fillRect(img, cell.Inset(4), index(colorBlock))
+			if c != game.Empty {
+				fillCircle(img, cell.Min.X+cellSize/2, cell.Min.Y+cellSize/2, cellSize/3, index(frogColor(c)))
+			}
+		}
+		anim.Image = append(anim.Image, img)
+		anim.Delay = append(anim.Delay, int(f.delay/(10*time.Millisecond)))
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+func fillRect(img *image.Paletted, r image.Rectangle, c uint8) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetColorIndex(x, y, c)
+		}
+	}
+}
+
+func fillCircle(img *image.Paletted, cx, cy, radius int, c uint8) {
+	for y := -radius; y <= radius; y++ {
+		for x := -radius; x <= radius; x++ {
+			if x*x+y*y <= radius*radius {
+				img.SetColorIndex(cx+x, cy+y, c)
+			}
+		}
+	}
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,65 @@
+package render
+
+import (
+	"bytes"
+	"image/gif"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/app/usecase"
+	"github.com/org/ranas-bdi-backend/internal/domain/game"
+)
+
+func testReplay() usecase.Replay {
+	from, to := 2, 0
+	return usecase.Replay{
+		Blocks:       3,
+		Initial:      game.Board{game.Left, game.Empty, game.Right},
+		InitialHints: []usecase.ReplayHint{{HintType: "strategy", Source: "player"}},
+		Frames: []usecase.ReplayFrame{
+			{Seq: 1, AtMs: 100, ElapsedMs: 100, From: 0, To: 1, IsCorrect: true, Board: game.Board{game.Empty, game.Left, game.Right},
+				Hints: []usecase.ReplayHint{{HintType: "next_move", Source: "agent", FromIdx: &from, ToIdx: &to}}},
+			{Seq: 2, AtMs: 10100, ElapsedMs: 10000, From: 2, To: 0, IsCorrect: false, Board: game.Board{game.Right, game.Left, game.Empty}},
+		},
+	}
+}
+
+func TestGIF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, GIF(&buf, testReplay()))
+
+	anim, err := gif.DecodeAll(&buf)
+	require.NoError(t, err)
+	require.Len(t, anim.Image, 3)
+	// 100ms is raised to the minimum and 10s capped to the maximum.
+	require.Equal(t, []int{25, 200, 200}, anim.Delay)
+
+	// The strategy hint underlines the whole initial board; the next_move
+	// hint only blocks 2 and 0.
+	hint := func(frame, block int) bool {
+		img := anim.Image[frame]
+		x := padding + block*cellSize + cellSize/2
+		return img.Palette[img.ColorIndexAt(x, padding+cellSize+4)] == colorHint
+	}
+	require.True(t, hint(0, 1))
+	require.True(t, hint(1, 0))
+	require.False(t, hint(1, 1))
+	require.True(t, hint(1, 2))
+	require.False(t, hint(2, 0))
+}
+
+func TestSVG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, SVG(&buf, testReplay()))
+
+	out := buf.String()
+	require.True(t, strings.HasPrefix(out, "<svg"))
+	require.Equal(t, 3, strings.Count(out, "<animate "))
+	require.Contains(t, out, "move 2 · 10.1s")
+	require.Contains(t, out, "start · hint: strategy (player)")
+	require.Contains(t, out, "move 1 · 0.1s · hint: next_move (agent)")
+	require.Equal(t, 2, strings.Count(out, `stroke-dasharray`), "one outline per hinted block")
+	require.Contains(t, out, `dur="4.250s"`)
+}
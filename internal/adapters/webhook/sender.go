@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+// Headers set on every delivery. Receivers verify the request by recomputing
+// Sign(secret, timestamp, body) and comparing it with SignatureHeader.
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+const maxResponseBytes = 64 << 10
+
+type Sender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+var _ ports.WebhookSender = (*Sender)(nil)
+
+func NewSender(client *http.Client) *Sender {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Sender{client: client, now: time.Now}
+}
+
+// Envelope is the JSON body posted to receivers. ID is the domain event ID and
+// stays the same across retries, so receivers can de-duplicate on it.
+type Envelope struct {
+	ID   int64           `json:"id"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+func (s *Sender) Send(ctx context.Context, webhook entity.Webhook, delivery entity.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(Envelope{ID: delivery.EventID, Type: delivery.EventType, Data: delivery.Payload})
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns "sha256=" followed by the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the webhook secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+func TestSenderSignsDelivery(t *testing.T) {
+	var (
+		gotHeaders http.Header
+		gotBody    []byte
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header.Clone()
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	sender := NewSender(receiver.Client())
+	sender.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	webhook := entity.Webhook{ID: "wh-1", URL: receiver.URL, Secret: "s3cret"}
+	delivery := entity.WebhookDelivery{
+		ID:        7,
+		EventID:   42,
+		EventType: entity.EventMatchFinished,
+		Payload:   json.RawMessage(`{"id":"m-1","outcome":"win"}`),
+	}
+
+	status, err := sender.Send(context.Background(), webhook, delivery)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, status)
+
+	require.Equal(t, "1700000000", gotHeaders.Get(TimestampHeader))
+	require.Equal(t, entity.EventMatchFinished, gotHeaders.Get(EventHeader))
+	require.Equal(t, "7", gotHeaders.Get(DeliveryHeader))
+	require.Equal(t, Sign("s3cret", "1700000000", gotBody), gotHeaders.Get(SignatureHeader))
+
+	var envelope Envelope
+	require.NoError(t, json.Unmarshal(gotBody, &envelope))
+	require.Equal(t, int64(42), envelope.ID)
+	require.Equal(t, entity.EventMatchFinished, envelope.Type)
+	require.JSONEq(t, `{"id":"m-1","outcome":"win"}`, string(envelope.Data))
+}
+
+func TestSenderRejectsNon2xx(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer receiver.Close()
+
+	status, err := NewSender(receiver.Client()).Send(context.Background(),
+		entity.Webhook{URL: receiver.URL, Secret: "s"},
+		entity.WebhookDelivery{Payload: json.RawMessage(`{}`)},
+	)
+	require.Error(t, err)
+	require.Equal(t, http.StatusBadGateway, status)
+}
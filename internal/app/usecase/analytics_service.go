@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+var ErrPlayerNotFound = errors.New("player has no sessions")
+
+type AnalyticsService struct {
+	repo ports.AnalyticsRepo
+}
+
+func NewAnalyticsService(repo ports.AnalyticsRepo) *AnalyticsService {
+	return &AnalyticsService{repo: repo}
+}
+
+// PlayerProgress aggregates the KPIs of every finished match the player
+// played, across all of their sessions, optionally only those of an
+// experiment or arm.
+func (s *AnalyticsService) PlayerProgress(ctx context.Context, playerID string, filter entity.ProgressFilter) (entity.PlayerProgress, error) {
+	if filter.Arm != nil && filter.Experiment == nil {
+		return entity.PlayerProgress{}, ErrMissingExperiment
+	}
+	sessions, matches, err := s.repo.PlayerTotals(ctx, playerID, filter)
+	if err != nil {
+		return entity.PlayerProgress{}, err
+	}
+	if sessions == 0 {
+		return entity.PlayerProgress{}, ErrPlayerNotFound
+	}
+
+	difficulties, err := s.repo.PlayerDifficulties(ctx, playerID, filter)
+	if err != nil {
+		return entity.PlayerProgress{}, err
+	}
+	for i := range difficulties {
+		if d := &difficulties[i]; d.Matches > 0 {
+			d.WinRate = float64(d.Wins) / float64(d.Matches)
+		}
+	}
+
+	trend, err := s.repo.PlayerTrend(ctx, playerID, filter)
+	if err != nil {
+		return entity.PlayerProgress{}, err
+	}
+
+	return entity.PlayerProgress{
+		PlayerID:     playerID,
+		Sessions:     sessions,
+		Matches:      matches,
+		Difficulties: nonNil(difficulties),
+		Trend:        nonNil(trend),
+	}, nil
+}
+
+// nonNil keeps empty lists encoding as [] rather than null.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+type stubAnalyticsRepo struct {
+	sessions     int
+	matches      int
+	difficulties []entity.DifficultyProgress
+	trend        []entity.ProgressPoint
+	// filters records the filter of every query.
+	filters *[]entity.ProgressFilter
+}
+
+func (r stubAnalyticsRepo) record(filter entity.ProgressFilter) {
+	if r.filters != nil {
+		*r.filters = append(*r.filters, filter)
+	}
+}
+
+func (r stubAnalyticsRepo) PlayerTotals(ctx context.Context, playerID string, filter entity.ProgressFilter) (int, int, error) {
+	r.record(filter)
+	return r.sessions, r.matches, nil
+}
+
+func (r stubAnalyticsRepo) PlayerTrend(ctx context.Context, playerID string, filter entity.ProgressFilter) ([]entity.ProgressPoint, error) {
+	r.record(filter)
+	return r.trend, nil
+}
+
+func (r stubAnalyticsRepo) PlayerDifficulties(ctx context.Context, playerID string, filter entity.ProgressFilter) ([]entity.DifficultyProgress, error) {
+	r.record(filter)
+	return r.difficulties, nil
+}
+
+func TestAnalyticsServicePlayerProgress(t *testing.T) {
+	svc := NewAnalyticsService(stubAnalyticsRepo{
+		sessions: 2,
+		matches:  4,
+		difficulties: []entity.DifficultyProgress{
+			{DifficultyID: 1, Matches: 4, Wins: 3},
+			{DifficultyID: 2},
+		},
+	})
+
+	progress, err := svc.PlayerProgress(context.Background(), "p-1", entity.ProgressFilter{})
+	require.NoError(t, err)
+	require.Equal(t, 2, progress.Sessions)
+	require.InDelta(t, 0.75, progress.Difficulties[0].WinRate, 1e-9)
+	require.Zero(t, progress.Difficulties[1].WinRate)
+	require.NotNil(t, progress.Trend)
+}
+
+func TestAnalyticsServiceUnknownPlayer(t *testing.T) {
+	_, err := NewAnalyticsService(stubAnalyticsRepo{}).PlayerProgress(context.Background(), "p-1", entity.ProgressFilter{})
+	require.ErrorIs(t, err, ErrPlayerNotFound)
+}
+
+func TestAnalyticsServiceArmFilter(t *testing.T) {
+	var filters []entity.ProgressFilter
+	svc := NewAnalyticsService(stubAnalyticsRepo{sessions: 1, filters: &filters})
+	experiment, arm := "hints", "on"
+
+	_, err := svc.PlayerProgress(context.Background(), "p-1", entity.ProgressFilter{Arm: &arm})
+	require.ErrorIs(t, err, ErrMissingExperiment)
+	require.Empty(t, filters)
+
+	filter := entity.ProgressFilter{Experiment: &experiment, Arm: &arm}
+	_, err = svc.PlayerProgress(context.Background(), "p-1", filter)
+	require.NoError(t, err)
+	require.Equal(t, []entity.ProgressFilter{filter, filter, filter}, filters, "every progress query is filtered")
+}
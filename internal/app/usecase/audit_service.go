@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+	"github.com/org/ranas-bdi-backend/internal/platform/logging"
+)
+
+var ErrInvalidAuditRange = errors.New("audit range must end after it starts")
+
+// AuditService records operations in the audit log and lists it. Match and
+// session updates are recorded by their repositories, with the fields that
+// changed.
+type AuditService struct {
+	repo ports.AuditRepo
+}
+
+func NewAuditService(repo ports.AuditRepo) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// Record writes an entry for an operation on an entity, attributed to the
+// actor and request ID carried by ctx.
+func (s *AuditService) Record(ctx context.Context, action, entityType string, entityID *string) (entity.AuditEntry, error) {
+	entry := entity.AuditEntry{
+		Actor:      logging.Actor(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+	}
+	if entry.Actor == "" {
+		entry.Actor = entity.AuditActorSystem
+	}
+	if id := logging.RequestID(ctx); id != "" {
+		entry.RequestID = &id
+	}
+	return s.repo.Record(ctx, entry)
+}
+
+func (s *AuditService) List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error) {
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return nil, ErrInvalidAuditRange
+	}
+	entries, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return nonNil(entries), nil
+}
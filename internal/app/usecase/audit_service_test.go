@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/platform/logging"
+)
+
+type stubAuditRepo struct {
+	entries []entity.AuditEntry
+	filter  entity.AuditFilter
+}
+
+func (r *stubAuditRepo) Record(ctx context.Context, entry entity.AuditEntry) (entity.AuditEntry, error) {
+	entry.ID = int64(len(r.entries) + 1)
+	r.entries = append(r.entries, entry)
+	return entry, nil
+}
+
+func (r *stubAuditRepo) List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error) {
+	r.filter = filter
+	return nil, nil
+}
+
+func TestAuditServiceRecordTakesActorFromContext(t *testing.T) {
+	repo := &stubAuditRepo{}
+	svc := NewAuditService(repo)
+	experimentID := "3"
+
+	ctx := logging.WithActor(logging.WithRequestID(context.Background(), "req-1"), "lab")
+	entry, err := svc.Record(ctx, "DELETE /admin/experiments/:experimentID", "experiment", &experimentID)
+	require.NoError(t, err)
+	require.Equal(t, "lab", entry.Actor)
+	require.Equal(t, "req-1", *entry.RequestID)
+	require.Equal(t, "experiment", entry.EntityType)
+
+	entry, err = svc.Record(context.Background(), "retention.archive", "match", nil)
+	require.NoError(t, err)
+	require.Equal(t, entity.AuditActorSystem, entry.Actor)
+	require.Nil(t, entry.RequestID)
+}
+
+func TestAuditServiceList(t *testing.T) {
+	repo := &stubAuditRepo{}
+	svc := NewAuditService(repo)
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+
+	_, err := svc.List(context.Background(), entity.AuditFilter{From: &from, To: &to, Limit: 10})
+	require.ErrorIs(t, err, ErrInvalidAuditRange)
+
+	entityType := "match"
+	entries, err := svc.List(context.Background(), entity.AuditFilter{EntityType: &entityType, Limit: 10})
+	require.NoError(t, err)
+	require.NotNil(t, entries)
+	require.Empty(t, entries)
+	require.Equal(t, "match", *repo.filter.EntityType)
+}
@@ -0,0 +1,13 @@
+package usecase
+
+import "time"
+
+// exponentialBackoff doubles from minDelay for every attempt already made,
+// capped at maxDelay.
+func exponentialBackoff(minDelay, maxDelay time.Duration, attempts int) time.Duration {
+	d := minDelay
+	for i := 1; i < attempts && d < maxDelay; i++ {
+		d *= 2
+	}
+	return min(d, maxDelay)
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+var ErrInvalidConsent = errors.New("invalid consent")
+
+// ConsentService records the research consent of players. Gameplay never
+// depends on it; without research consent in force moves are stored without
+// their boards.
+type ConsentService struct {
+	repo ports.ConsentRepo
+}
+
+func NewConsentService(repo ports.ConsentRepo) *ConsentService {
+	return &ConsentService{repo: repo}
+}
+
+// Record stores a newly signed consent form, which supersedes the previous
+// one.
+func (s *ConsentService) Record(ctx context.Context, consent entity.Consent) (entity.Consent, error) {
+	consent.FormVersion = strings.TrimSpace(consent.FormVersion)
+	if consent.FormVersion == "" || len(consent.FormVersion) > 32 {
+		return entity.Consent{}, fmt.Errorf("%w: form_version must have between 1 and 32 characters", ErrInvalidConsent)
+	}
+	if consent.ResearchConsent && !consent.GuardianConsent {
+		return entity.Consent{}, fmt.Errorf("%w: research consent needs guardian consent", ErrInvalidConsent)
+	}
+	if name := consent.GuardianName; name != nil {
+		trimmed := strings.TrimSpace(*name)
+		if len(trimmed) > 128 {
+			return entity.Consent{}, fmt.Errorf("%w: guardian_name must have at most 128 characters", ErrInvalidConsent)
+		}
+		consent.GuardianName = &trimmed
+		if trimmed == "" {
+			consent.GuardianName = nil
+		}
+	}
+
+	created, err := s.repo.Create(ctx, consent)
+	if err != nil {
+		return entity.Consent{}, err
+	}
+	slog.InfoContext(ctx, "consent recorded",
+		"player_id", created.PlayerID,
+		"form_version", created.FormVersion,
+		"research", created.AllowsResearch(),
+	)
+	return created, nil
+}
+
+// History lists the consents of a player, latest first.
+func (s *ConsentService) History(ctx context.Context, playerID string) ([]entity.Consent, error) {
+	consents, err := s.repo.ListByPlayer(ctx, playerID)
+	if err != nil {
+		return nil, err
+	}
+	return nonNil(consents), nil
+}
+
+// Withdraw withdraws the consent in force of request.PlayerID and deletes or
+// anonymizes the player's data as request.Action says. request carries the
+// actor and request ID for the privacy audit.
+func (s *ConsentService) Withdraw(ctx context.Context, request entity.PrivacyAudit) (entity.Consent, entity.PrivacyAudit, error) {
+	if request.Action != entity.WithdrawalDelete && request.Action != entity.WithdrawalAnonymize {
+		return entity.Consent{}, entity.PrivacyAudit{}, fmt.Errorf("%w: action must be %q or %q", ErrInvalidConsent, entity.WithdrawalDelete, entity.WithdrawalAnonymize)
+	}
+	request.Reason = entity.PrivacyReasonConsentWithdrawal
+	consent, audit, err := s.repo.Withdraw(ctx, request)
+	if err != nil {
+		return entity.Consent{}, entity.PrivacyAudit{}, err
+	}
+	slog.InfoContext(ctx, "consent withdrawn",
+		"player_id", request.PlayerID,
+		"action", request.Action,
+		"sessions", audit.SessionsAffected,
+	)
+	return consent, audit, nil
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+type stubConsentRepo struct {
+	created   []entity.Consent
+	withdrawn []string
+}
+
+func (r *stubConsentRepo) Create(ctx context.Context, consent entity.Consent) (entity.Consent, error) {
+	consent.ID = int64(len(r.created) + 1)
+	r.created = append(r.created, consent)
+	return consent, nil
+}
+
+func (r *stubConsentRepo) ListByPlayer(ctx context.Context, playerID string) ([]entity.Consent, error) {
+	return nil, nil
+}
+
+func (r *stubConsentRepo) Withdraw(ctx context.Context, audit entity.PrivacyAudit) (entity.Consent, entity.PrivacyAudit, error) {
+	r.withdrawn = append(r.withdrawn, audit.Action)
+	audit.SessionsAffected = 2
+	return entity.Consent{PlayerID: audit.PlayerID, WithdrawalAction: &audit.Action}, audit, nil
+}
+
+func TestConsentServiceRecord(t *testing.T) {
+	ctx := context.Background()
+	repo := &stubConsentRepo{}
+	svc := NewConsentService(repo)
+	blank := "  "
+
+	consent, err := svc.Record(ctx, entity.Consent{
+		PlayerID:        "p-1",
+		FormVersion:     " 2025-01 ",
+		ResearchConsent: true,
+		GuardianConsent: true,
+		GuardianName:    &blank,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "2025-01", consent.FormVersion)
+	require.Nil(t, consent.GuardianName)
+	require.True(t, consent.AllowsResearch())
+
+	_, err = svc.Record(ctx, entity.Consent{PlayerID: "p-1", FormVersion: "2025-01", ResearchConsent: true})
+	require.ErrorIs(t, err, ErrInvalidConsent)
+	_, err = svc.Record(ctx, entity.Consent{PlayerID: "p-1"})
+	require.ErrorIs(t, err, ErrInvalidConsent)
+	require.Len(t, repo.created, 1)
+
+	history, err := svc.History(ctx, "p-1")
+	require.NoError(t, err)
+	require.NotNil(t, history)
+}
+
+func TestConsentServiceWithdraw(t *testing.T) {
+	ctx := context.Background()
+	repo := &stubConsentRepo{}
+	svc := NewConsentService(repo)
+
+	_, _, err := svc.Withdraw(ctx, entity.PrivacyAudit{PlayerID: "p-1", Action: "forget"})
+	require.ErrorIs(t, err, ErrInvalidConsent)
+	require.Empty(t, repo.withdrawn)
+
+	consent, audit, err := svc.Withdraw(ctx, entity.PrivacyAudit{PlayerID: "p-1", Action: entity.WithdrawalAnonymize, Actor: "lab"})
+	require.NoError(t, err)
+	require.Equal(t, 2, audit.SessionsAffected)
+	require.Equal(t, entity.PrivacyReasonConsentWithdrawal, audit.Reason)
+	require.Equal(t, entity.WithdrawalAnonymize, *consent.WithdrawalAction)
+}
@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+var ErrInvalidCurriculum = errors.New("invalid curriculum")
+
+// CurriculumPosition is where a session stands in its curriculum: the level
+// its next match is played on and the progress made on it.
+type CurriculumPosition struct {
+	SessionID  string            `json:"session_id"`
+	Curriculum entity.Curriculum `json:"curriculum"`
+	LevelN     int               `json:"level_n"`
+	// DifficultyID is the difficulty of LevelN.
+	DifficultyID int `json:"difficulty_id"`
+	// Wins counts the wins on the current level; AdvanceAfter of them move
+	// the session up.
+	Wins int `json:"wins"`
+	// Failures counts the losses and timeouts in a row on the current level.
+	Failures int `json:"failures"`
+	// Completed is set once the last level has been passed. The session then
+	// keeps playing the last level.
+	Completed     bool `json:"completed"`
+	MatchesPlayed int  `json:"matches_played"`
+}
+
+type CurriculumService struct {
+	curricula    ports.CurriculumRepo
+	sessions     ports.SessionRepo
+	matches      ports.MatchRepo
+	difficulties ports.DifficultyRepo
+}
+
+func NewCurriculumService(
+	curricula ports.CurriculumRepo,
+	sessions ports.SessionRepo,
+	matches ports.MatchRepo,
+	difficulties ports.DifficultyRepo,
+) *CurriculumService {
+	return &CurriculumService{
+		curricula:    curricula,
+		sessions:     sessions,
+		matches:      matches,
+		difficulties: difficulties,
+	}
+}
+
+// Create validates and stores a curriculum. Levels are numbered from 1 in
+// the given order.
+func (s *CurriculumService) Create(ctx context.Context, curriculum entity.Curriculum) (entity.Curriculum, error) {
+	curriculum.Name = strings.TrimSpace(curriculum.Name)
+	if curriculum.Name == "" || len(curriculum.Name) > 64 {
+		return entity.Curriculum{}, fmt.Errorf("%w: name must have between 1 and 64 characters", ErrInvalidCurriculum)
+	}
+	if curriculum.AdvanceAfter == 0 {
+		curriculum.AdvanceAfter = 1
+	}
+	if curriculum.AdvanceAfter < 0 {
+		return entity.Curriculum{}, fmt.Errorf("%w: advance_after must be positive", ErrInvalidCurriculum)
+	}
+	if n := curriculum.StepBackAfter; n != nil && *n <= 0 {
+		return entity.Curriculum{}, fmt.Errorf("%w: step_back_after must be positive", ErrInvalidCurriculum)
+	}
+	if len(curriculum.Levels) == 0 {
+		return entity.Curriculum{}, fmt.Errorf("%w: at least one level is required", ErrInvalidCurriculum)
+	}
+	for i, level := range curriculum.Levels {
+		difficulty, err := s.difficulties.GetByID(ctx, level.DifficultyID)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return entity.Curriculum{}, fmt.Errorf("%w: level %d: difficulty %d does not exist", ErrInvalidCurriculum, i+1, level.DifficultyID)
+		case err != nil:
+			return entity.Curriculum{}, err
+		case difficulty.RetiredAt != nil:
+			return entity.Curriculum{}, fmt.Errorf("%w: level %d: difficulty %d is retired", ErrInvalidCurriculum, i+1, level.DifficultyID)
+		}
+	}
+
+	created, err := s.curricula.Create(ctx, curriculum)
+	if err != nil {
+		return entity.Curriculum{}, err
+	}
+	slog.InfoContext(ctx, "curriculum created", "curriculum_id", created.ID, "levels", len(created.Levels))
+	return created, nil
+}
+
+func (s *CurriculumService) Get(ctx context.Context, id int) (entity.Curriculum, error) {
+	return s.curricula.Get(ctx, id)
+}
+
+func (s *CurriculumService) List(ctx context.Context) ([]entity.Curriculum, error) {
+	curricula, err := s.curricula.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return nonNil(curricula), nil
+}
+
+// Position works out the level of the next match of a session from the
+// outcomes of the matches it already finished.
+func (s *CurriculumService) Position(ctx context.Context, sessionID string) (CurriculumPosition, error) {
+	session, err := s.sessions.Get(ctx, sessionID)
+	if err != nil {
+		return CurriculumPosition{}, err
+	}
+	curriculum, err := s.forSession(ctx, session)
+	if err != nil {
+		return CurriculumPosition{}, err
+	}
+	matches, err := s.matches.ListBySession(ctx, sessionID)
+	if err != nil {
+		return CurriculumPosition{}, err
+	}
+	position := curriculumPosition(curriculum, matches)
+	position.SessionID = sessionID
+	return position, nil
+}
+
+func (s *CurriculumService) forSession(ctx context.Context, session entity.Session) (entity.Curriculum, error) {
+	if session.CurriculumID != nil {
+		return s.curricula.Get(ctx, *session.CurriculumID)
+	}
+	return s.curricula.GetDefault(ctx)
+}
+
+// curriculumPosition replays the finished matches of a session over the
+// curriculum rules. The level recorded on each match wins over the computed
+// one, so matches started with an explicit level are followed too.
+func curriculumPosition(curriculum entity.Curriculum, matches []entity.Match) CurriculumPosition {
+	position := CurriculumPosition{Curriculum: curriculum, LevelN: 1}
+	last := len(curriculum.Levels)
+	for _, m := range matches {
+		if m.IsActive {
+			continue
+		}
+		position.MatchesPlayed++
+		if m.LevelN >= 1 && m.LevelN <= last && m.LevelN != position.LevelN {
+			position.LevelN, position.Wins, position.Failures = m.LevelN, 0, 0
+		}
+
+		outcome := ""
+		if m.Outcome != nil {
+			outcome = *m.Outcome
+		}
+		switch outcome {
+		case entity.OutcomeWin:
+			position.Failures = 0
+			position.Wins++
+			if position.Wins < curriculum.AdvanceAfter {
+				continue
+			}
+			position.Wins = 0
+			if position.LevelN == last {
+				position.Completed = true
+			} else {
+				position.LevelN++
+			}
+		case entity.OutcomeLose, entity.OutcomeTimeout:
+			position.Failures++
+			if n := curriculum.StepBackAfter; n != nil && position.Failures >= *n {
+				position.Failures, position.Wins = 0, 0
+				position.LevelN = max(1, position.LevelN-1)
+			}
+		}
+	}
+	if last > 0 {
+		position.DifficultyID = curriculum.Levels[position.LevelN-1].DifficultyID
+	}
+	return position
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+type stubCurriculumRepo struct {
+	curricula []entity.Curriculum
+}
+
+func (r stubCurriculumRepo) Create(ctx context.Context, curriculum entity.Curriculum) (entity.Curriculum, error) {
+	curriculum.ID = len(r.curricula) + 1
+	for i := range curriculum.Levels {
+		curriculum.Levels[i].LevelN = i + 1
+	}
+	return curriculum, nil
+}
+
+func (r stubCurriculumRepo) Get(ctx context.Context, id int) (entity.Curriculum, error) {
+	for _, c := range r.curricula {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return entity.Curriculum{}, pgx.ErrNoRows
+}
+
+func (r stubCurriculumRepo) GetDefault(ctx context.Context) (entity.Curriculum, error) {
+	for _, c := range r.curricula {
+		if c.IsDefault {
+			return c, nil
+		}
+	}
+	return entity.Curriculum{}, pgx.ErrNoRows
+}
+
+func (r stubCurriculumRepo) List(ctx context.Context) ([]entity.Curriculum, error) {
+	return r.curricula, nil
+}
+
+func TestCurriculumPosition(t *testing.T) {
+	two := 2
+	curriculum := entity.Curriculum{
+		AdvanceAfter:  1,
+		StepBackAfter: &two,
+		Levels:        []entity.CurriculumLevel{{LevelN: 1, DifficultyID: 10}, {LevelN: 2, DifficultyID: 20}, {LevelN: 3, DifficultyID: 30}},
+	}
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	played := func(outcomes ...string) []entity.Match {
+		var matches []entity.Match
+		level := 1
+		for i, outcome := range outcomes {
+			o := outcome
+			matches = append(matches, entity.Match{LevelN: level, StartedAt: start.Add(time.Duration(i) * time.Minute), Outcome: &o})
+			level = curriculumPosition(curriculum, matches).LevelN
+		}
+		return matches
+	}
+
+	cases := []struct {
+		name      string
+		outcomes  []string
+		level     int
+		failures  int
+		completed bool
+	}{
+		{name: "new session", level: 1},
+		{name: "win advances", outcomes: []string{"win"}, level: 2},
+		{name: "loss repeats", outcomes: []string{"win", "lose"}, level: 2, failures: 1},
+		{name: "two failures step back", outcomes: []string{"win", "lose", entity.OutcomeTimeout}, level: 1},
+		{name: "never below the first level", outcomes: []string{"lose", "lose"}, level: 1},
+		{name: "aborted matches change nothing", outcomes: []string{"win", "lose", "aborted", "lose"}, level: 1},
+		{name: "a win resets failures", outcomes: []string{"lose", "win", "lose"}, level: 2, failures: 1},
+		{name: "last level", outcomes: []string{"win", "win", "win"}, level: 3, completed: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := curriculumPosition(curriculum, played(tc.outcomes...))
+			require.Equal(t, tc.level, got.LevelN)
+			require.Equal(t, tc.level*10, got.DifficultyID)
+			require.Equal(t, tc.failures, got.Failures)
+			require.Equal(t, tc.completed, got.Completed)
+			require.Equal(t, len(tc.outcomes), got.MatchesPlayed)
+		})
+	}
+
+	// Two wins are needed per level, and active matches do not count.
+	curriculum.AdvanceAfter = 2
+	matches := append(played("win"), entity.Match{LevelN: 1, IsActive: true})
+	got := curriculumPosition(curriculum, matches)
+	require.Equal(t, 1, got.LevelN)
+	require.Equal(t, 1, got.Wins)
+	require.Equal(t, 1, got.MatchesPlayed)
+}
+
+func TestCurriculumServicePosition(t *testing.T) {
+	win := "win"
+	custom := 2
+	repo := stubCurriculumRepo{curricula: []entity.Curriculum{
+		{ID: 1, Name: "default", AdvanceAfter: 1, IsDefault: true, Levels: []entity.CurriculumLevel{{LevelN: 1, DifficultyID: 1}, {LevelN: 2, DifficultyID: 2}}},
+		{ID: 2, Name: "hard first", AdvanceAfter: 1, Levels: []entity.CurriculumLevel{{LevelN: 1, DifficultyID: 3}}},
+	}}
+	sessions := stubSessionRepo{getFn: func(ctx context.Context, id string) (entity.Session, error) {
+		switch id {
+		case "s-1":
+			return entity.Session{ID: id}, nil
+		case "s-2":
+			return entity.Session{ID: id, CurriculumID: &custom}, nil
+		}
+		return entity.Session{}, pgx.ErrNoRows
+	}}
+	matches := stubMatchRepo{matches: map[string]entity.Match{
+		"m-1": {ID: "m-1", SessionID: "s-1", LevelN: 1, Outcome: &win},
+	}}
+	svc := NewCurriculumService(repo, sessions, matches, stubDifficultyRepo{})
+
+	position, err := svc.Position(context.Background(), "s-1")
+	require.NoError(t, err)
+	require.Equal(t, "s-1", position.SessionID)
+	require.Equal(t, "default", position.Curriculum.Name)
+	require.Equal(t, 2, position.LevelN)
+	require.Equal(t, 2, position.DifficultyID)
+
+	position, err = svc.Position(context.Background(), "s-2")
+	require.NoError(t, err)
+	require.Equal(t, 3, position.DifficultyID)
+
+	_, err = svc.Position(context.Background(), "missing")
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestCurriculumServiceCreateValidates(t *testing.T) {
+	retiredAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	svc := NewCurriculumService(stubCurriculumRepo{}, stubSessionRepo{}, stubMatchRepo{}, stubDifficultyRepo{difficulties: []entity.Difficulty{
+		{ID: 1, Name: "easy"},
+		{ID: 2, Name: "old", RetiredAt: &retiredAt},
+	}})
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, entity.Curriculum{Name: "warm-up", Levels: []entity.CurriculumLevel{{DifficultyID: 1}, {DifficultyID: 1}}})
+	require.NoError(t, err)
+	require.Equal(t, 1, created.AdvanceAfter)
+	require.Equal(t, 2, created.Levels[1].LevelN)
+
+	zero := 0
+	invalid := map[string]entity.Curriculum{
+		"no name":            {Levels: []entity.CurriculumLevel{{DifficultyID: 1}}},
+		"no levels":          {Name: "x"},
+		"unknown difficulty": {Name: "x", Levels: []entity.CurriculumLevel{{DifficultyID: 9}}},
+		"retired difficulty": {Name: "x", Levels: []entity.CurriculumLevel{{DifficultyID: 2}}},
+		"bad step back":      {Name: "x", StepBackAfter: &zero, Levels: []entity.CurriculumLevel{{DifficultyID: 1}}},
+	}
+	for name, c := range invalid {
+		_, err := svc.Create(ctx, c)
+		require.ErrorIs(t, err, ErrInvalidCurriculum, name)
+	}
+}
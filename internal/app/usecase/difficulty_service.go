@@ -2,11 +2,30 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
 
 	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/game"
 	"github.com/org/ranas-bdi-backend/internal/domain/ports"
 )
 
+// maxDifficultyBlocks bounds the boards researchers can define, which keeps
+// the solvability check fast.
+const maxDifficultyBlocks = 25
+
+// maxLayoutBytes bounds the JSON of a layout before it is parsed, leaving
+// room for any formatting of maxDifficultyBlocks cells.
+const maxLayoutBytes = 64 * maxDifficultyBlocks
+
+var (
+	ErrInvalidDifficulty = errors.New("invalid difficulty")
+	ErrDifficultyRetired = errors.New("difficulty is retired")
+)
+
 type DifficultyService struct {
 	repo ports.DifficultyRepo
 }
@@ -22,3 +41,135 @@ func (s *DifficultyService) GetAll(ctx context.Context) ([]entity.Difficulty, er
 func (s *DifficultyService) GetByID(ctx context.Context, id int) (entity.Difficulty, error) {
 	return s.repo.GetByID(ctx, id)
 }
+
+// ForMatch returns the difficulty version match is played on.
+func (s *DifficultyService) ForMatch(ctx context.Context, match entity.Match) (entity.Difficulty, error) {
+	return s.repo.GetVersion(ctx, match.DifficultyID, match.DifficultyVersion)
+}
+
+// Versions lists every version of a difficulty, oldest first.
+func (s *DifficultyService) Versions(ctx context.Context, id int) ([]entity.Difficulty, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	versions, err := s.repo.ListVersions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return nonNil(versions), nil
+}
+
+// Create validates and stores a new difficulty at version 1.
+func (s *DifficultyService) Create(ctx context.Context, difficulty entity.Difficulty) (entity.Difficulty, error) {
+	if err := s.validate(ctx, &difficulty); err != nil {
+		return entity.Difficulty{}, err
+	}
+	created, err := s.repo.Create(ctx, difficulty)
+	if err != nil {
+		return entity.Difficulty{}, err
+	}
+	slog.InfoContext(ctx, "difficulty created",
+		"difficulty_id", created.ID,
+		"name", created.Name,
+		"number_of_blocks", created.NumberOfBlocks,
+	)
+	return created, nil
+}
+
+// Update replaces the definition of a difficulty. Matches already played
+// keep the version they started on.
+func (s *DifficultyService) Update(ctx context.Context, difficulty entity.Difficulty) (entity.Difficulty, error) {
+	current, err := s.repo.GetByID(ctx, difficulty.ID)
+	if err != nil {
+		return entity.Difficulty{}, err
+	}
+	if current.RetiredAt != nil {
+		return entity.Difficulty{}, ErrDifficultyRetired
+	}
+	if err := s.validate(ctx, &difficulty); err != nil {
+		return entity.Difficulty{}, err
+	}
+	updated, err := s.repo.Update(ctx, difficulty)
+	if err != nil {
+		return entity.Difficulty{}, err
+	}
+	slog.InfoContext(ctx, "difficulty updated",
+		"difficulty_id", updated.ID,
+		"version", updated.Version,
+	)
+	return updated, nil
+}
+
+// Retire stops new matches on a difficulty. Its history stays available.
+func (s *DifficultyService) Retire(ctx context.Context, id int) (entity.Difficulty, error) {
+	retired, err := s.repo.Retire(ctx, id)
+	if err != nil {
+		return entity.Difficulty{}, err
+	}
+	slog.InfoContext(ctx, "difficulty retired", "difficulty_id", retired.ID)
+	return retired, nil
+}
+
+// validate normalizes the name, the block count and the layout of a
+// difficulty and checks that its starting board can be solved. Without a
+// layout the standard board for NumberOfBlocks is used; with one,
+// NumberOfBlocks may be left out.
+func (s *DifficultyService) validate(ctx context.Context, difficulty *entity.Difficulty) error {
+	difficulty.Name = strings.TrimSpace(difficulty.Name)
+	if difficulty.Name == "" || len(difficulty.Name) > 64 {
+		return fmt.Errorf("%w: name must have between 1 and 64 characters", ErrInvalidDifficulty)
+	}
+	if limit := difficulty.TimeLimitMs; limit != nil && *limit <= 0 {
+		return fmt.Errorf("%w: time_limit_ms must be positive", ErrInvalidDifficulty)
+	}
+
+	// Sizes are checked before any board is built, so an oversized payload
+	// is refused without allocating it.
+	if difficulty.NumberOfBlocks > maxDifficultyBlocks {
+		return fmt.Errorf("%w: at most %d blocks", ErrInvalidDifficulty, maxDifficultyBlocks)
+	}
+	if len(difficulty.Layout) > maxLayoutBytes {
+		return fmt.Errorf("%w: layout: at most %d blocks", ErrInvalidDifficulty, maxDifficultyBlocks)
+	}
+
+	var initial game.Board
+	if len(difficulty.Layout) == 0 || string(difficulty.Layout) == "null" {
+		board, err := game.NewBoard(difficulty.NumberOfBlocks)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidDifficulty, err)
+		}
+		initial, difficulty.Layout = board, nil
+	} else {
+		board, err := game.ParseBoard(difficulty.Layout)
+		if err != nil {
+			return fmt.Errorf("%w: layout: %w", ErrInvalidDifficulty, err)
+		}
+		if len(board) > maxDifficultyBlocks {
+			return fmt.Errorf("%w: layout: at most %d blocks", ErrInvalidDifficulty, maxDifficultyBlocks)
+		}
+		if difficulty.NumberOfBlocks == 0 {
+			difficulty.NumberOfBlocks = len(board)
+		}
+		if len(board) != difficulty.NumberOfBlocks {
+			return fmt.Errorf("%w: layout has %d blocks but number_of_blocks is %d", ErrInvalidDifficulty, len(board), difficulty.NumberOfBlocks)
+		}
+		if difficulty.Layout, err = json.Marshal(board); err != nil {
+			return err
+		}
+		initial = board
+	}
+	if err := initial.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidDifficulty, err)
+	}
+
+	existing, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, d := range existing {
+		if d.ID != difficulty.ID && strings.EqualFold(d.Name, difficulty.Name) {
+			return fmt.Errorf("%w: name %q is already used", ErrInvalidDifficulty, difficulty.Name)
+		}
+	}
+	return nil
+}
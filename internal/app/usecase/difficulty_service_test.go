@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+func TestDifficultyServiceCreateValidates(t *testing.T) {
+	svc := NewDifficultyService(stubDifficultyRepo{difficulties: []entity.Difficulty{{ID: 1, Name: "easy", NumberOfBlocks: 7}}})
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, entity.Difficulty{Name: " lopsided ", Layout: json.RawMessage(`[1, 1, 1, 0, 2]`)})
+	require.NoError(t, err)
+	require.Equal(t, "lopsided", created.Name)
+	require.Equal(t, 5, created.NumberOfBlocks, "taken from the layout")
+	require.JSONEq(t, `[1,1,1,0,2]`, string(created.Layout))
+	require.Equal(t, 1, created.Version)
+
+	created, err = svc.Create(ctx, entity.Difficulty{Name: "expert", NumberOfBlocks: 13})
+	require.NoError(t, err)
+	require.Nil(t, created.Layout, "standard boards need no layout")
+
+	zero := 0
+	invalid := map[string]entity.Difficulty{
+		"no name":           {NumberOfBlocks: 7},
+		"name taken":        {Name: "Easy", NumberOfBlocks: 7},
+		"even blocks":       {Name: "x", NumberOfBlocks: 8},
+		"too many blocks":   {Name: "x", NumberOfBlocks: 27},
+		"huge block count":  {Name: "x", NumberOfBlocks: 100_000_001},
+		"long layout":       {Name: "x", Layout: json.RawMessage("[" + strings.Repeat("0,", maxDifficultyBlocks+1) + "0]")},
+		"oversized layout":  {Name: "x", Layout: json.RawMessage(strings.Repeat(" ", maxLayoutBytes) + "[1,0,2]")},
+		"bad time limit":    {Name: "x", NumberOfBlocks: 7, TimeLimitMs: &zero},
+		"size mismatch":     {Name: "x", NumberOfBlocks: 7, Layout: json.RawMessage(`[1,0,2]`)},
+		"unknown cell":      {Name: "x", Layout: json.RawMessage(`[1,0,5]`)},
+		"no empty block":    {Name: "x", Layout: json.RawMessage(`[1,3,2]`)},
+		"already solved":    {Name: "x", Layout: json.RawMessage(`[2,0,1]`)},
+		"blocked by a rock": {Name: "x", Layout: json.RawMessage(`[1,0,3,0,2]`)},
+	}
+	for name, d := range invalid {
+		_, err := svc.Create(ctx, d)
+		require.ErrorIs(t, err, ErrInvalidDifficulty, name)
+	}
+}
+
+func TestDifficultyServiceUpdateAndVersions(t *testing.T) {
+	retiredAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	repo := stubDifficultyRepo{
+		difficulties: []entity.Difficulty{
+			{ID: 1, Name: "easy", NumberOfBlocks: 7, Version: 2},
+			{ID: 2, Name: "old", NumberOfBlocks: 5, Version: 1, RetiredAt: &retiredAt},
+		},
+		versions: []entity.Difficulty{
+			{ID: 1, Name: "easy", NumberOfBlocks: 5, Version: 1},
+			{ID: 1, Name: "easy", NumberOfBlocks: 7, Version: 2},
+		},
+	}
+	svc := NewDifficultyService(repo)
+	ctx := context.Background()
+
+	updated, err := svc.Update(ctx, entity.Difficulty{ID: 1, Name: "easy", NumberOfBlocks: 9})
+	require.NoError(t, err)
+	require.Equal(t, 3, updated.Version)
+
+	_, err = svc.Update(ctx, entity.Difficulty{ID: 2, Name: "old", NumberOfBlocks: 5})
+	require.ErrorIs(t, err, ErrDifficultyRetired)
+	_, err = svc.Update(ctx, entity.Difficulty{ID: 9, Name: "new", NumberOfBlocks: 5})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	versions, err := svc.Versions(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+
+	// A match started on version 1 keeps its five-block board.
+	played, err := svc.ForMatch(ctx, entity.Match{DifficultyID: 1, DifficultyVersion: 1})
+	require.NoError(t, err)
+	require.Equal(t, 5, played.NumberOfBlocks)
+}
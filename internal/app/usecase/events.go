@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+type nopEventPublisher struct{}
+
+func (nopEventPublisher) Publish(context.Context, entity.MatchEvent) error { return nil }
+
+func eventPublisherOrNop(p ports.EventPublisher) ports.EventPublisher {
+	if p == nil {
+		return nopEventPublisher{}
+	}
+	return p
+}
+
+// publish notifies observers without failing the write that produced the
+// event; the change is already persisted at this point.
+func publish(ctx context.Context, p ports.EventPublisher, event entity.MatchEvent) {
+	if err := p.Publish(ctx, event); err != nil {
+		slog.WarnContext(ctx, "failed to publish match event",
+			"event_type", event.Type,
+			"match_id", event.MatchID,
+			"error", err,
+		)
+	}
+}
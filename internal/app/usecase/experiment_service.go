@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+// maxExperimentName bounds experiment keys and arm names, which end up as
+// JSON keys in matches.meta and as report query parameters.
+const maxExperimentName = 64
+
+var ErrInvalidExperiment = errors.New("invalid experiment")
+
+// ExperimentService splits players or sessions between the arms of the
+// active experiments. Assignment is a hash of the unit, so the same unit
+// always lands in the same arm, and it is stored the first time it is made.
+type ExperimentService struct {
+	repo ports.ExperimentRepo
+}
+
+func NewExperimentService(repo ports.ExperimentRepo) *ExperimentService {
+	return &ExperimentService{repo: repo}
+}
+
+func (s *ExperimentService) List(ctx context.Context) ([]entity.Experiment, error) {
+	experiments, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return nonNil(experiments), nil
+}
+
+// Create validates and stores an experiment. Units default to players and
+// arm weights to 1.
+func (s *ExperimentService) Create(ctx context.Context, experiment entity.Experiment) (entity.Experiment, error) {
+	if err := s.validate(ctx, &experiment); err != nil {
+		return entity.Experiment{}, err
+	}
+	created, err := s.repo.Create(ctx, experiment)
+	if err != nil {
+		return entity.Experiment{}, err
+	}
+	slog.InfoContext(ctx, "experiment created", "experiment_id", created.ID, "key", created.Key, "arms", len(created.Arms))
+	return created, nil
+}
+
+// Deactivate stops assigning units to the experiment. Existing assignments
+// and the tags on matches are kept for analysis.
+func (s *ExperimentService) Deactivate(ctx context.Context, id int) (entity.Experiment, error) {
+	experiment, err := s.repo.Deactivate(ctx, id)
+	if err != nil {
+		return entity.Experiment{}, err
+	}
+	slog.InfoContext(ctx, "experiment deactivated", "experiment_id", experiment.ID, "key", experiment.Key)
+	return experiment, nil
+}
+
+// AssignSession returns the arm of every active experiment for session,
+// assigning it on first use. Player experiments fall back to the session
+// when it has no player.
+func (s *ExperimentService) AssignSession(ctx context.Context, session entity.Session) ([]entity.ExperimentAssignment, error) {
+	experiments, err := s.repo.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assignments := make([]entity.ExperimentAssignment, 0, len(experiments))
+	for _, experiment := range experiments {
+		unitID := session.ID
+		if experiment.Unit == entity.ExperimentUnitPlayer && session.PlayerID != nil {
+			unitID = *session.PlayerID
+		}
+		assigned, err := s.repo.Assign(ctx, entity.ExperimentAssignment{
+			ExperimentID: experiment.ID,
+			UnitID:       unitID,
+			Arm:          pickArm(experiment, unitID),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("assign experiment %s: %w", experiment.Key, err)
+		}
+		assignments = append(assignments, assigned)
+	}
+	return assignments, nil
+}
+
+// MatchMeta is the matches.meta tag recording the arms a match was played
+// under, e.g. {"experiments":{"hints":"agent"}}. It is nil without
+// assignments.
+func MatchMeta(assignments []entity.ExperimentAssignment) json.RawMessage {
+	if len(assignments) == 0 {
+		return nil
+	}
+	arms := make(map[string]string, len(assignments))
+	for _, a := range assignments {
+		arms[a.Experiment] = a.Arm
+	}
+	meta, _ := json.Marshal(map[string]any{"experiments": arms})
+	return meta
+}
+
+// pickArm hashes the experiment key and the unit into [0, total weight) and
+// returns the arm owning that slot. Keying the hash by experiment keeps the
+// arms of different experiments independent.
+func pickArm(experiment entity.Experiment, unitID string) string {
+	total := 0
+	for _, arm := range experiment.Arms {
+		total += arm.Weight
+	}
+	if total <= 0 {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(experiment.Key + ":" + unitID))
+	slot := int(binary.BigEndian.Uint64(sum[:8]) % uint64(total))
+	for _, arm := range experiment.Arms {
+		if slot < arm.Weight {
+			return arm.Name
+		}
+		slot -= arm.Weight
+	}
+	return experiment.Arms[len(experiment.Arms)-1].Name
+}
+
+func (s *ExperimentService) validate(ctx context.Context, experiment *entity.Experiment) error {
+	experiment.Key = strings.TrimSpace(experiment.Key)
+	if !validExperimentName(experiment.Key) {
+		return fmt.Errorf("%w: key must have between 1 and %d letters, digits, '-' or '_'", ErrInvalidExperiment, maxExperimentName)
+	}
+	switch experiment.Unit {
+	case "":
+		experiment.Unit = entity.ExperimentUnitPlayer
+	case entity.ExperimentUnitPlayer, entity.ExperimentUnitSession:
+	default:
+		return fmt.Errorf("%w: unit must be %q or %q", ErrInvalidExperiment, entity.ExperimentUnitPlayer, entity.ExperimentUnitSession)
+	}
+	if len(experiment.Arms) < 2 {
+		return fmt.Errorf("%w: at least two arms are required", ErrInvalidExperiment)
+	}
+	seen := make(map[string]bool, len(experiment.Arms))
+	for i := range experiment.Arms {
+		arm := &experiment.Arms[i]
+		arm.Name = strings.TrimSpace(arm.Name)
+		if !validExperimentName(arm.Name) {
+			return fmt.Errorf("%w: arm names must have between 1 and %d letters, digits, '-' or '_'", ErrInvalidExperiment, maxExperimentName)
+		}
+		if seen[arm.Name] {
+			return fmt.Errorf("%w: arm %q is repeated", ErrInvalidExperiment, arm.Name)
+		}
+		seen[arm.Name] = true
+		if arm.Weight == 0 {
+			arm.Weight = 1
+		}
+		if arm.Weight < 0 {
+			return fmt.Errorf("%w: arm %q: weight must be positive", ErrInvalidExperiment, arm.Name)
+		}
+		if len(arm.Config) > 0 && string(arm.Config) != "null" {
+			var config map[string]any
+			if err := json.Unmarshal(arm.Config, &config); err != nil {
+				return fmt.Errorf("%w: arm %q: config must be a JSON object", ErrInvalidExperiment, arm.Name)
+			}
+		} else {
+			arm.Config = nil
+		}
+	}
+
+	existing, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, e := range existing {
+		if e.Key == experiment.Key {
+			return fmt.Errorf("%w: key %q is already used", ErrInvalidExperiment, experiment.Key)
+		}
+	}
+	return nil
+}
+
+func validExperimentName(name string) bool {
+	if name == "" || len(name) > maxExperimentName {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
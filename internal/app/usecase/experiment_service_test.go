@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+type stubExperimentRepo struct {
+	experiments []entity.Experiment
+	assignments map[string]entity.ExperimentAssignment
+}
+
+func (r *stubExperimentRepo) Create(ctx context.Context, experiment entity.Experiment) (entity.Experiment, error) {
+	experiment.ID = len(r.experiments) + 1
+	experiment.IsActive = true
+	r.experiments = append(r.experiments, experiment)
+	return experiment, nil
+}
+
+func (r *stubExperimentRepo) List(ctx context.Context) ([]entity.Experiment, error) {
+	return r.experiments, nil
+}
+
+func (r *stubExperimentRepo) ListActive(ctx context.Context) ([]entity.Experiment, error) {
+	var active []entity.Experiment
+	for _, e := range r.experiments {
+		if e.IsActive {
+			active = append(active, e)
+		}
+	}
+	return active, nil
+}
+
+func (r *stubExperimentRepo) Deactivate(ctx context.Context, id int) (entity.Experiment, error) {
+	r.experiments[id-1].IsActive = false
+	return r.experiments[id-1], nil
+}
+
+func (r *stubExperimentRepo) Assign(ctx context.Context, assignment entity.ExperimentAssignment) (entity.ExperimentAssignment, error) {
+	if r.assignments == nil {
+		r.assignments = make(map[string]entity.ExperimentAssignment)
+	}
+	key := fmt.Sprintf("%d/%s", assignment.ExperimentID, assignment.UnitID)
+	if existing, ok := r.assignments[key]; ok {
+		return existing, nil
+	}
+	assignment.Experiment = r.experiments[assignment.ExperimentID-1].Key
+	r.assignments[key] = assignment
+	return assignment, nil
+}
+
+func TestPickArmFollowsWeights(t *testing.T) {
+	experiment := entity.Experiment{
+		Key: "hints",
+		Arms: []entity.ExperimentArm{
+			{Name: "control", Weight: 1},
+			{Name: "agent", Weight: 3},
+		},
+	}
+
+	counts := map[string]int{}
+	for i := range 4000 {
+		unit := fmt.Sprintf("player-%d", i)
+		arm := pickArm(experiment, unit)
+		require.Equal(t, arm, pickArm(experiment, unit))
+		counts[arm]++
+	}
+	require.InDelta(t, 1000, counts["control"], 150)
+	require.InDelta(t, 3000, counts["agent"], 150)
+}
+
+func TestExperimentServiceCreateValidates(t *testing.T) {
+	ctx := context.Background()
+	svc := NewExperimentService(&stubExperimentRepo{})
+
+	created, err := svc.Create(ctx, entity.Experiment{
+		Key:  "hints",
+		Arms: []entity.ExperimentArm{{Name: "control"}, {Name: "agent", Config: json.RawMessage(`{"hints":"agent"}`)}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, entity.ExperimentUnitPlayer, created.Unit)
+	require.Equal(t, 1, created.Arms[0].Weight)
+
+	for _, bad := range []entity.Experiment{
+		{Key: "hints", Arms: []entity.ExperimentArm{{Name: "a"}, {Name: "b"}}},
+		{Key: "two words", Arms: []entity.ExperimentArm{{Name: "a"}, {Name: "b"}}},
+		{Key: "solo", Arms: []entity.ExperimentArm{{Name: "a"}}},
+		{Key: "dup", Arms: []entity.ExperimentArm{{Name: "a"}, {Name: "a"}}},
+		{Key: "unit", Unit: "classroom", Arms: []entity.ExperimentArm{{Name: "a"}, {Name: "b"}}},
+		{Key: "config", Arms: []entity.ExperimentArm{{Name: "a", Config: json.RawMessage(`[1]`)}, {Name: "b"}}},
+	} {
+		_, err := svc.Create(ctx, bad)
+		require.ErrorIs(t, err, ErrInvalidExperiment, bad.Key)
+	}
+}
+
+func TestExperimentServiceAssignSession(t *testing.T) {
+	ctx := context.Background()
+	repo := &stubExperimentRepo{}
+	svc := NewExperimentService(repo)
+	arms := []entity.ExperimentArm{{Name: "a"}, {Name: "b"}}
+	_, err := svc.Create(ctx, entity.Experiment{Key: "by-player", Arms: arms})
+	require.NoError(t, err)
+	_, err = svc.Create(ctx, entity.Experiment{Key: "by-session", Unit: entity.ExperimentUnitSession, Arms: arms})
+	require.NoError(t, err)
+	_, err = svc.Create(ctx, entity.Experiment{Key: "stopped", Arms: arms})
+	require.NoError(t, err)
+	_, err = svc.Deactivate(ctx, 3)
+	require.NoError(t, err)
+
+	player := "player-1"
+	first, err := svc.AssignSession(ctx, entity.Session{ID: "s-1", PlayerID: &player})
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+	require.Equal(t, player, first[0].UnitID)
+	require.Equal(t, "s-1", first[1].UnitID)
+
+	second, err := svc.AssignSession(ctx, entity.Session{ID: "s-2", PlayerID: &player})
+	require.NoError(t, err)
+	require.Equal(t, first[0], second[0])
+	require.Equal(t, "s-2", second[1].UnitID)
+
+	var meta struct {
+		Experiments map[string]string `json:"experiments"`
+	}
+	require.NoError(t, json.Unmarshal(MatchMeta(first), &meta))
+	require.Equal(t, map[string]string{"by-player": first[0].Arm, "by-session": first[1].Arm}, meta.Experiments)
+	require.Nil(t, MatchMeta(nil))
+}
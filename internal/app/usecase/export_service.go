@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+var ErrInvalidExportRange = errors.New("export range must end after it starts")
+
+// ExportService streams raw game data for analysts. Rows are handed to the
+// callback as they are read from the database.
+type ExportService struct {
+	repo ports.ExportRepo
+}
+
+func NewExportService(repo ports.ExportRepo) *ExportService {
+	return &ExportService{repo: repo}
+}
+
+func (s *ExportService) Validate(filter entity.ExportFilter) error {
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return ErrInvalidExportRange
+	}
+	if filter.Arm != nil && filter.Experiment == nil {
+		return ErrMissingExperiment
+	}
+	return nil
+}
+
+func (s *ExportService) EachSession(ctx context.Context, filter entity.ExportFilter, fn func(entity.Session) error) error {
+	if err := s.Validate(filter); err != nil {
+		return err
+	}
+	return s.repo.EachSession(ctx, filter, fn)
+}
+
+func (s *ExportService) EachMatch(ctx context.Context, filter entity.ExportFilter, fn func(entity.Match) error) error {
+	if err := s.Validate(filter); err != nil {
+		return err
+	}
+	return s.repo.EachMatch(ctx, filter, fn)
+}
+
+func (s *ExportService) EachMove(ctx context.Context, filter entity.ExportFilter, fn func(entity.Move) error) error {
+	if err := s.Validate(filter); err != nil {
+		return err
+	}
+	return s.repo.EachMove(ctx, filter, fn)
+}
+
+func (s *ExportService) EachMatchKPI(ctx context.Context, filter entity.ExportFilter, fn func(entity.MatchKPI) error) error {
+	if err := s.Validate(filter); err != nil {
+		return err
+	}
+	return s.repo.EachMatchKPI(ctx, filter, fn)
+}
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+type Readiness struct {
+	Ready            bool            `json:"ready"`
+	Database         string          `json:"database"`
+	Schema           string          `json:"schema"`
+	MissingRelations []string        `json:"missing_relations,omitempty"`
+	MissingColumns   []string        `json:"missing_columns,omitempty"`
+	Pool             ports.PoolStats `json:"pool"`
+	Error            string          `json:"error,omitempty"`
+}
+
+type HealthService struct {
+	db ports.DatabaseProbe
+}
+
+func NewHealthService(db ports.DatabaseProbe) *HealthService {
+	return &HealthService{db: db}
+}
+
+func (s *HealthService) Readiness(ctx context.Context) Readiness {
+	r := Readiness{Database: "down", Schema: "unknown"}
+
+	if err := s.db.Ping(ctx); err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	r.Database = "up"
+
+	if stats, err := s.db.Stats(ctx); err == nil {
+		r.Pool = stats
+	}
+
+	missing, err := s.db.MissingRelations(ctx)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	columns, err := s.db.MissingColumns(ctx)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	if len(missing) > 0 || len(columns) > 0 {
+		r.Schema = "outdated"
+		r.MissingRelations = missing
+		r.MissingColumns = columns
+		return r
+	}
+	r.Schema = "current"
+	r.Ready = true
+	return r
+}
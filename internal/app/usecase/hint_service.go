@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+// HintEventTypes are the outbox events used to tell whether a hint was
+// followed.
+var HintEventTypes = []string{entity.EventMoveRecorded}
+
+var ErrInvalidHint = errors.New("invalid hint")
+
+type HintService struct {
+	matches ports.MatchRepo
+	hints   ports.HintRepo
+}
+
+func NewHintService(matches ports.MatchRepo, hints ports.HintRepo) *HintService {
+	return &HintService{matches: matches, hints: hints}
+}
+
+// Record stores a hint shown in an active match. The server assigns the seq,
+// so the hint applies to the next move recorded.
+func (s *HintService) Record(ctx context.Context, hint entity.Hint) (entity.Hint, error) {
+	if err := validateHint(hint); err != nil {
+		return entity.Hint{}, err
+	}
+	match, err := s.matches.Get(ctx, hint.MatchID)
+	if err != nil {
+		return entity.Hint{}, err
+	}
+	if !match.IsActive {
+		return entity.Hint{}, ErrMatchFinished
+	}
+	return s.hints.Create(ctx, hint)
+}
+
+func validateHint(hint entity.Hint) error {
+	switch hint.Source {
+	case entity.HintSourcePlayer, entity.HintSourceAgent:
+	default:
+		return fmt.Errorf("%w: source must be %s or %s", ErrInvalidHint, entity.HintSourcePlayer, entity.HintSourceAgent)
+	}
+	switch hint.HintType {
+	case entity.HintTypeNextMove:
+		if hint.FromIdx == nil || hint.ToIdx == nil {
+			return fmt.Errorf("%w: %s hints need from_idx and to_idx", ErrInvalidHint, hint.HintType)
+		}
+	case entity.HintTypeFrog:
+		if hint.FromIdx == nil || hint.ToIdx != nil {
+			return fmt.Errorf("%w: %s hints need from_idx only", ErrInvalidHint, hint.HintType)
+		}
+	case entity.HintTypeStrategy:
+		if hint.FromIdx != nil || hint.ToIdx != nil {
+			return fmt.Errorf("%w: %s hints have no target", ErrInvalidHint, hint.HintType)
+		}
+	default:
+		return fmt.Errorf("%w: hint_type must be %s, %s or %s", ErrInvalidHint,
+			entity.HintTypeNextMove, entity.HintTypeFrog, entity.HintTypeStrategy)
+	}
+	for _, idx := range []*int{hint.FromIdx, hint.ToIdx} {
+		if idx != nil && *idx < 0 {
+			return fmt.Errorf("%w: block indexes cannot be negative", ErrInvalidHint)
+		}
+	}
+	return nil
+}
+
+// List returns the hints of a match, checking that the match exists.
+func (s *HintService) List(ctx context.Context, matchID string) ([]entity.Hint, error) {
+	if _, err := s.matches.Get(ctx, matchID); err != nil {
+		return nil, err
+	}
+	hints, err := s.hints.ListByMatch(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+	return nonNil(hints), nil
+}
+
+func (s *HintService) Dismiss(ctx context.Context, matchID, id string) (entity.Hint, error) {
+	return s.hints.Dismiss(ctx, matchID, id)
+}
+
+// Resolve is registered as an outbox handler for MoveRecorded. Resolving is
+// idempotent: hints that already have an answer are skipped.
+func (s *HintService) Resolve(ctx context.Context, event entity.OutboxEvent) error {
+	var move entity.Move
+	if err := json.Unmarshal(event.Payload, &move); err != nil {
+		return err
+	}
+	hints, err := s.hints.ListUnresolved(ctx, move.MatchID, move.Seq-1)
+	if err != nil {
+		return err
+	}
+	for _, hint := range hints {
+		if err := s.hints.Resolve(ctx, move.MatchID, hint.ID, hint.FollowedBy(move)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
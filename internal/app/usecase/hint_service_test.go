@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+type stubHintRepo struct {
+	created  []entity.Hint
+	pending  []entity.Hint
+	resolved map[string]bool
+	summary  entity.HintSummary
+}
+
+func (r *stubHintRepo) Create(ctx context.Context, hint entity.Hint) (entity.Hint, error) {
+	r.created = append(r.created, hint)
+	return hint, nil
+}
+
+func (r *stubHintRepo) ListByMatch(ctx context.Context, matchID string) ([]entity.Hint, error) {
+	var hints []entity.Hint
+	for _, h := range r.created {
+		if h.MatchID == matchID {
+			hints = append(hints, h)
+		}
+	}
+	return hints, nil
+}
+
+func (r *stubHintRepo) Dismiss(ctx context.Context, matchID, id string) (entity.Hint, error) {
+	return entity.Hint{}, pgx.ErrNoRows
+}
+
+func (r *stubHintRepo) ListUnresolved(ctx context.Context, matchID string, seq int) ([]entity.Hint, error) {
+	var hints []entity.Hint
+	for _, h := range r.pending {
+		if h.MatchID == matchID && h.Seq == seq {
+			hints = append(hints, h)
+		}
+	}
+	return hints, nil
+}
+
+func (r *stubHintRepo) Resolve(ctx context.Context, matchID, id string, followed bool) error {
+	if r.resolved == nil {
+		r.resolved = map[string]bool{}
+	}
+	r.resolved[id] = followed
+	return nil
+}
+
+func (r *stubHintRepo) SummaryByMatch(ctx context.Context, matchID string) (entity.HintSummary, error) {
+	return r.summary, nil
+}
+
+func TestHintServiceRecord(t *testing.T) {
+	repo := &stubHintRepo{}
+	svc := NewHintService(stubMatchRepo{matches: map[string]entity.Match{
+		"active":   {ID: "active", IsActive: true},
+		"finished": {ID: "finished"},
+	}}, repo)
+	ctx := context.Background()
+	idx := func(v int) *int { return &v }
+
+	_, err := svc.Record(ctx, entity.Hint{MatchID: "active", HintType: entity.HintTypeNextMove, Source: entity.HintSourceAgent, FromIdx: idx(2), ToIdx: idx(3)})
+	require.NoError(t, err)
+	require.Len(t, repo.created, 1)
+
+	for name, hint := range map[string]entity.Hint{
+		"unknown source":     {HintType: entity.HintTypeStrategy, Source: "tutor"},
+		"unknown type":       {HintType: "glow", Source: entity.HintSourcePlayer},
+		"move without to":    {HintType: entity.HintTypeNextMove, Source: entity.HintSourcePlayer, FromIdx: idx(1)},
+		"frog with target":   {HintType: entity.HintTypeFrog, Source: entity.HintSourcePlayer, FromIdx: idx(1), ToIdx: idx(2)},
+		"strategy with frog": {HintType: entity.HintTypeStrategy, Source: entity.HintSourcePlayer, FromIdx: idx(1)},
+		"negative index":     {HintType: entity.HintTypeFrog, Source: entity.HintSourcePlayer, FromIdx: idx(-1)},
+	} {
+		hint.MatchID = "active"
+		_, err := svc.Record(ctx, hint)
+		require.ErrorIs(t, err, ErrInvalidHint, name)
+	}
+
+	strategy := entity.Hint{HintType: entity.HintTypeStrategy, Source: entity.HintSourcePlayer}
+	strategy.MatchID = "finished"
+	_, err = svc.Record(ctx, strategy)
+	require.ErrorIs(t, err, ErrMatchFinished)
+	strategy.MatchID = "missing"
+	_, err = svc.Record(ctx, strategy)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	require.Len(t, repo.created, 1)
+
+	hints, err := svc.List(ctx, "active")
+	require.NoError(t, err)
+	require.NotNil(t, hints)
+}
+
+func TestHintServiceResolve(t *testing.T) {
+	idx := func(v int) *int { return &v }
+	repo := &stubHintRepo{pending: []entity.Hint{
+		{ID: "move", MatchID: "m-1", Seq: 3, HintType: entity.HintTypeNextMove, FromIdx: idx(2), ToIdx: idx(3)},
+		{ID: "frog", MatchID: "m-1", Seq: 3, HintType: entity.HintTypeFrog, FromIdx: idx(4)},
+		{ID: "strategy", MatchID: "m-1", Seq: 3, HintType: entity.HintTypeStrategy},
+		{ID: "earlier", MatchID: "m-1", Seq: 2, HintType: entity.HintTypeStrategy},
+	}}
+	svc := NewHintService(stubMatchRepo{}, repo)
+
+	payload, _ := json.Marshal(entity.Move{MatchID: "m-1", Seq: 4, FromIdx: 2, ToIdx: 3, MoveKind: entity.MoveKindStep, IsCorrect: true})
+	require.NoError(t, svc.Resolve(context.Background(), entity.OutboxEvent{Type: entity.EventMoveRecorded, Payload: payload}))
+	require.Equal(t, map[string]bool{"move": true, "frog": false, "strategy": true}, repo.resolved)
+}
+
+func TestHintFollowedBy(t *testing.T) {
+	idx := func(v int) *int { return &v }
+	frog := entity.Hint{HintType: entity.HintTypeFrog, FromIdx: idx(2)}
+	require.True(t, frog.FollowedBy(entity.Move{FromIdx: 2, ToIdx: 4, MoveKind: entity.MoveKindJump}))
+	require.False(t, frog.FollowedBy(entity.Move{FromIdx: 1, ToIdx: 2, MoveKind: entity.MoveKindStep}))
+	require.False(t, frog.FollowedBy(entity.Move{FromIdx: 2, ToIdx: 1, MoveKind: entity.MoveKindUndo}))
+
+	strategy := entity.Hint{HintType: entity.HintTypeStrategy}
+	require.False(t, strategy.FollowedBy(entity.Move{FromIdx: 1, ToIdx: 2, MoveKind: entity.MoveKindStep}))
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/game"
+)
+
+// matchBoard is the server-side board of a match, rebuilt from its move log.
+// It keeps every position reached since the last restart so undo can roll
+// back one frog move at a time, and remembers every position of the match to
+// spot loops.
+type matchBoard struct {
+	initial game.Board
+	boards  []game.Board
+	played  []entity.Move // frog moves still on the board, one per board after the first
+	visited map[string]bool
+}
+
+func newMatchBoard(initial game.Board) *matchBoard {
+	return &matchBoard{
+		initial: initial,
+		boards:  []game.Board{initial},
+		visited: map[string]bool{initial.Key(): true},
+	}
+}
+
+// seen reports whether the match has already been in position p.
+func (b *matchBoard) seen(p game.Board) bool {
+	return b.visited[p.Key()]
+}
+
+func (b *matchBoard) current() game.Board {
+	return b.boards[len(b.boards)-1]
+}
+
+// lastPlayed returns the frog move an undo would revert.
+func (b *matchBoard) lastPlayed() (entity.Move, bool) {
+	if len(b.played) == 0 {
+		return entity.Move{}, false
+	}
+	return b.played[len(b.played)-1], true
+}
+
+func (b *matchBoard) apply(mv entity.Move) {
+	switch mv.MoveKind {
+	case entity.MoveKindUndo:
+		if len(b.played) > 0 {
+			b.boards = b.boards[:len(b.boards)-1]
+			b.played = b.played[:len(b.played)-1]
+		}
+	case entity.MoveKindRestart:
+		b.restart()
+	default:
+		b.push(mv, boardAfter(b.current(), mv))
+	}
+}
+
+func (b *matchBoard) push(mv entity.Move, after game.Board) {
+	b.boards = append(b.boards, after)
+	b.played = append(b.played, mv)
+	b.visited[after.Key()] = true
+}
+
+func (b *matchBoard) restart() {
+	b.boards = []game.Board{b.initial}
+	b.played = nil
+}
+
+// boardAfter prefers the board recorded by the client and otherwise replays
+// the move on the previous board. Moves that cannot be applied leave the
+// board unchanged so one bad row does not break the whole timeline.
+func boardAfter(previous game.Board, mv entity.Move) game.Board {
+	if len(mv.BoardAfter) > 0 {
+		if recorded, err := game.ParseBoard(mv.BoardAfter); err == nil && len(recorded) == len(previous) {
+			return recorded
+		}
+	}
+	next, err := previous.Apply(mv.FromIdx, mv.ToIdx)
+	if err != nil {
+		return previous
+	}
+	return next
+}
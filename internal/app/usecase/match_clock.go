@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+// MatchClock is the time budget of a match. ElapsedMs is the time played,
+// excluding pauses; RemainingMs is nil when the difficulty has no limit.
+type MatchClock struct {
+	MatchID     string              `json:"match_id"`
+	Paused      bool                `json:"paused"`
+	ElapsedMs   int                 `json:"elapsed_ms"`
+	PausedMs    int                 `json:"paused_ms"`
+	TimeLimitMs *int                `json:"time_limit_ms"`
+	RemainingMs *int                `json:"remaining_ms"`
+	Expired     bool                `json:"expired"`
+	Pauses      []entity.MatchPause `json:"pauses"`
+}
+
+func newMatchClock(match entity.Match, difficulty entity.Difficulty, pauses []entity.MatchPause, now time.Time) MatchClock {
+	end := now
+	if match.EndedAt != nil {
+		end = *match.EndedAt
+	}
+	paused := pausedBetween(pauses, match.StartedAt, end)
+	elapsed := max(0, end.Sub(match.StartedAt)-paused)
+
+	clock := MatchClock{
+		MatchID:     match.ID,
+		ElapsedMs:   int(elapsed.Milliseconds()),
+		PausedMs:    int(paused.Milliseconds()),
+		TimeLimitMs: difficulty.TimeLimitMs,
+		Pauses:      nonNil(pauses),
+	}
+	if n := len(pauses); n > 0 && pauses[n-1].ResumedAt == nil {
+		clock.Paused = true
+	}
+	if limit := difficulty.TimeLimitMs; limit != nil {
+		remaining := max(0, *limit-clock.ElapsedMs)
+		clock.RemainingMs = &remaining
+		clock.Expired = clock.ElapsedMs >= *limit
+	}
+	return clock
+}
+
+// pausedBetween returns how much of [from, to] the match spent paused. An
+// open pause lasts until to.
+func pausedBetween(pauses []entity.MatchPause, from, to time.Time) time.Duration {
+	var total time.Duration
+	for _, p := range pauses {
+		start, end := p.PausedAt, to
+		if p.ResumedAt != nil && p.ResumedAt.Before(to) {
+			end = *p.ResumedAt
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	return total
+}
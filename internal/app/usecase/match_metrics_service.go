@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/game"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+// MatchMetricsEventTypes are the outbox events that trigger a metrics
+// computation.
+var MatchMetricsEventTypes = []string{entity.EventMatchFinished}
+
+// minOutlierSample is the number of timed moves needed before think-time
+// quartiles mean anything.
+const minOutlierSample = 4
+
+// MatchStats is everything known about a match's performance: the KPIs kept
+// by the match_stats trigger, the hints shown and the metrics computed once
+// the match closes. KPI and Metrics are nil until they exist.
+type MatchStats struct {
+	KPI     *entity.MatchKPI     `json:"kpi"`
+	Hints   entity.HintSummary   `json:"hints"`
+	Metrics *entity.MatchMetrics `json:"metrics"`
+}
+
+type MatchMetricsService struct {
+	matches      ports.MatchRepo
+	moves        ports.MoveRepo
+	difficulties ports.DifficultyRepo
+	stats        ports.MatchStatsRepo
+	metrics      ports.MatchMetricsRepo
+	hints        ports.HintRepo
+}
+
+func NewMatchMetricsService(
+	matches ports.MatchRepo,
+	moves ports.MoveRepo,
+	difficulties ports.DifficultyRepo,
+	stats ports.MatchStatsRepo,
+	metrics ports.MatchMetricsRepo,
+	hints ports.HintRepo,
+) *MatchMetricsService {
+	return &MatchMetricsService{
+		matches:      matches,
+		moves:        moves,
+		difficulties: difficulties,
+		stats:        stats,
+		metrics:      metrics,
+		hints:        hints,
+	}
+}
+
+// Refresh is registered as an outbox handler for MatchFinished. Computing is
+// idempotent, so redelivered events just overwrite the row.
+func (s *MatchMetricsService) Refresh(ctx context.Context, event entity.OutboxEvent) error {
+	var match entity.Match
+	if err := json.Unmarshal(event.Payload, &match); err != nil {
+		return err
+	}
+	_, err := s.Compute(ctx, match.ID)
+	return err
+}
+
+// Compute derives the metrics of a match from its moves and stores them.
+func (s *MatchMetricsService) Compute(ctx context.Context, matchID string) (entity.MatchMetrics, error) {
+	match, err := s.matches.Get(ctx, matchID)
+	if err != nil {
+		return entity.MatchMetrics{}, err
+	}
+	difficulty, err := s.difficulties.GetVersion(ctx, match.DifficultyID, match.DifficultyVersion)
+	if err != nil {
+		return entity.MatchMetrics{}, err
+	}
+	moves, err := s.moves.GetByMatch(ctx, matchID)
+	if err != nil {
+		return entity.MatchMetrics{}, err
+	}
+	initial, err := game.FromLayout(difficulty.Layout, difficulty.NumberOfBlocks)
+	if err != nil {
+		return entity.MatchMetrics{}, err
+	}
+	return s.metrics.Upsert(ctx, computeMatchMetrics(match, initial, moves))
+}
+
+// Stats returns the KPIs and metrics of a match.
+func (s *MatchMetricsService) Stats(ctx context.Context, matchID string) (MatchStats, error) {
+	if _, err := s.matches.Get(ctx, matchID); err != nil {
+		return MatchStats{}, err
+	}
+	var stats MatchStats
+	kpi, err := s.stats.GetByMatch(ctx, matchID)
+	switch {
+	case err == nil:
+		stats.KPI = &kpi
+	case !errors.Is(err, pgx.ErrNoRows):
+		return MatchStats{}, err
+	}
+	if stats.Hints, err = s.hints.SummaryByMatch(ctx, matchID); err != nil {
+		return MatchStats{}, err
+	}
+	metrics, err := s.metrics.GetByMatch(ctx, matchID)
+	switch {
+	case err == nil:
+		stats.Metrics = &metrics
+	case !errors.Is(err, pgx.ErrNoRows):
+		return MatchStats{}, err
+	}
+	return stats, nil
+}
+
+func computeMatchMetrics(match entity.Match, initial game.Board, moves []entity.Move) entity.MatchMetrics {
+	metrics := entity.MatchMetrics{MatchID: match.ID}
+	if len(moves) == 0 {
+		return metrics
+	}
+
+	// ElapsedMs of the first move is the time since the start without the
+	// pauses.
+	latency := moves[0].ElapsedMs
+	metrics.FirstMoveLatencyMs = &latency
+
+	solver := game.NewSolver()
+	board := newMatchBoard(initial)
+	streak := 0
+	var (
+		recoveries   []int
+		recovering   bool
+		recoveryMs   int
+		timedElapsed []int
+	)
+	for i, mv := range moves {
+		// Undos and restarts are actions, not frog moves: they only change
+		// the board and end the current streak.
+		switch mv.MoveKind {
+		case entity.MoveKindRestart:
+			metrics.Restarts++
+			fallthrough
+		case entity.MoveKindUndo:
+			board.apply(mv)
+			streak = 0
+			if recovering {
+				recoveryMs += mv.ElapsedMs
+			}
+			continue
+		}
+
+		before, restarted := boardBefore(board.current(), initial, mv, i == 0)
+		if restarted {
+			metrics.Restarts++
+			streak = 0
+			board.restart()
+		}
+
+		if solver.Optimal(before, mv.FromIdx, mv.ToIdx) {
+			metrics.OptimalMoves++
+			streak++
+			metrics.LongestOptimalStreak = max(metrics.LongestOptimalStreak, streak)
+		} else {
+			streak = 0
+		}
+
+		switch mv.ToIdx - mv.FromIdx {
+		case -2, 2:
+			metrics.Jumps++
+		case -1, 1:
+			metrics.Steps++
+		}
+
+		// A recovery episode starts at an error and ends at the next
+		// correct move; consecutive errors belong to the same episode.
+		switch {
+		case !mv.IsCorrect && !recovering:
+			recovering, recoveryMs = true, 0
+		case mv.IsCorrect && recovering:
+			recoveries = append(recoveries, recoveryMs+mv.ElapsedMs)
+			recovering = false
+		case recovering:
+			recoveryMs += mv.ElapsedMs
+		}
+
+		if !mv.Interruption {
+			timedElapsed = append(timedElapsed, mv.ElapsedMs)
+		}
+		board.push(mv, boardAfter(before, mv))
+	}
+
+	if n := metrics.Jumps + metrics.Steps; n > 0 {
+		v := float64(metrics.Jumps) / float64(n)
+		metrics.JumpRatio = &v
+	}
+	if len(recoveries) > 0 {
+		total := 0
+		for _, ms := range recoveries {
+			total += ms
+		}
+		v := float64(total) / float64(len(recoveries))
+		metrics.AvgRecoveryMs = &v
+	}
+	if len(timedElapsed) >= minOutlierSample {
+		threshold := thinkOutlierThreshold(timedElapsed)
+		metrics.ThinkOutlierThresholdMs = &threshold
+		for _, mv := range moves {
+			if frogMove(mv) && mv.IsCorrect && !mv.Interruption && mv.ElapsedMs > threshold {
+				metrics.ThinkOutliers++
+			}
+		}
+	}
+	return metrics
+}
+
+func frogMove(mv entity.Move) bool {
+	return mv.MoveKind != entity.MoveKindUndo && mv.MoveKind != entity.MoveKindRestart
+}
+
+// boardBefore returns the board the move was played on. Older clients
+// restart a match by silently going back to the initial position instead of
+// recording a restart, which shows up as a
+// recorded board_before equal to the initial board, or as a move that only
+// makes sense on the initial board.
+func boardBefore(current, initial game.Board, mv entity.Move, first bool) (game.Board, bool) {
+	if len(mv.BoardBefore) > 0 {
+		if recorded, err := game.ParseBoard(mv.BoardBefore); err == nil && len(recorded) == len(current) {
+			return recorded, !first && slices.Equal(recorded, initial) && !slices.Equal(current, initial)
+		}
+	}
+	if first || slices.Equal(current, initial) {
+		return current, false
+	}
+	if _, err := current.Apply(mv.FromIdx, mv.ToIdx); err != nil {
+		if _, err := initial.Apply(mv.FromIdx, mv.ToIdx); err == nil {
+			return initial, true
+		}
+	}
+	return current, false
+}
+
+// thinkOutlierThreshold is Tukey's upper fence, Q3 + 1.5·IQR, rounded up to
+// the next millisecond.
+func thinkOutlierThreshold(elapsed []int) int {
+	sorted := slices.Clone(elapsed)
+	slices.Sort(sorted)
+	q1, q3 := quantile(sorted, 0.25), quantile(sorted, 0.75)
+	return int(math.Ceil(q3 + 1.5*(q3-q1)))
+}
+
+// quantile interpolates linearly between the closest ranks of sorted.
+func quantile(sorted []int, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	frac := pos - float64(lo)
+	return float64(sorted[lo]) + frac*float64(sorted[hi]-sorted[lo])
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+type stubMatchMetricsRepo struct {
+	saved map[string]entity.MatchMetrics
+}
+
+func (r *stubMatchMetricsRepo) Upsert(ctx context.Context, metrics entity.MatchMetrics) (entity.MatchMetrics, error) {
+	if r.saved == nil {
+		r.saved = make(map[string]entity.MatchMetrics)
+	}
+	r.saved[metrics.MatchID] = metrics
+	return metrics, nil
+}
+
+func (r *stubMatchMetricsRepo) GetByMatch(ctx context.Context, matchID string) (entity.MatchMetrics, error) {
+	metrics, ok := r.saved[matchID]
+	if !ok {
+		return entity.MatchMetrics{}, pgx.ErrNoRows
+	}
+	return metrics, nil
+}
+
+func TestMatchMetricsServiceRefresh(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	move := func(seq, from, to, elapsed int, correct bool) entity.Move {
+		return entity.Move{
+			MatchID:    "m-1",
+			Seq:        seq,
+			OccurredAt: start.Add(time.Duration(1500+seq*1000) * time.Millisecond),
+			ElapsedMs:  elapsed,
+			FromIdx:    from,
+			ToIdx:      to,
+			IsCorrect:  correct,
+		}
+	}
+	restart := move(4, 1, 2, 3000, true)
+	restart.BoardBefore = json.RawMessage(`[1,1,0,2,2]`)
+	moves := []entity.Move{
+		move(1, 1, 2, 1000, true),
+		move(2, 3, 1, 1000, true),
+		move(3, 2, 3, 2000, false),
+		restart,
+		move(5, 3, 1, 1000, true),
+		move(6, 4, 3, 20000, true),
+	}
+	// The player paused before the first move: the wall clock says 4.5s but
+	// only 1s of play went by.
+	moves[0].OccurredAt = start.Add(4500 * time.Millisecond)
+
+	repo := &stubMatchMetricsRepo{}
+	svc := NewMatchMetricsService(
+		stubMatchRepo{matches: map[string]entity.Match{"m-1": {ID: "m-1", DifficultyID: 1, StartedAt: start}}},
+		stubMoveRepo{moves: moves},
+		stubDifficultyRepo{difficulties: []entity.Difficulty{{ID: 1, NumberOfBlocks: 5}}},
+		stubMatchStatsRepo{},
+		repo,
+		&stubHintRepo{summary: entity.HintSummary{Total: 2, Followed: 1}},
+	)
+
+	payload, _ := json.Marshal(entity.Match{ID: "m-1"})
+	require.NoError(t, svc.Refresh(context.Background(), entity.OutboxEvent{Type: entity.EventMatchFinished, Payload: payload}))
+
+	got := repo.saved["m-1"]
+	require.Equal(t, 1000, *got.FirstMoveLatencyMs, "pauses do not count")
+	require.Equal(t, 1, got.Restarts)
+	require.Equal(t, 5, got.OptimalMoves)
+	require.Equal(t, 3, got.LongestOptimalStreak, "the restart starts a new streak")
+	require.Equal(t, 2, got.Jumps)
+	require.Equal(t, 4, got.Steps)
+	require.InDelta(t, 2.0/6.0, *got.JumpRatio, 1e-9)
+	require.InDelta(t, 3000, *got.AvgRecoveryMs, 1e-9)
+	// Quartiles of 1000,1000,1000,2000,3000,20000 are 1000 and 2750.
+	require.Equal(t, 5375, *got.ThinkOutlierThresholdMs)
+	require.Equal(t, 1, got.ThinkOutliers)
+
+	stats, err := svc.Stats(context.Background(), "m-1")
+	require.NoError(t, err)
+	require.Nil(t, stats.KPI)
+	require.Equal(t, 2, stats.Hints.Total)
+	require.Equal(t, got, *stats.Metrics)
+
+	_, err = svc.Stats(context.Background(), "missing")
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestComputeMatchMetricsShortMatch(t *testing.T) {
+	got := computeMatchMetrics(entity.Match{ID: "m-1"}, nil, nil)
+	require.Equal(t, entity.MatchMetrics{MatchID: "m-1"}, got)
+}
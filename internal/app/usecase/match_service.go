@@ -2,28 +2,81 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+
 	"github.com/org/ranas-bdi-backend/internal/domain/entity"
 	"github.com/org/ranas-bdi-backend/internal/domain/ports"
 )
 
+var (
+	ErrMatchFinished  = errors.New("match is already finished")
+	ErrMatchPaused    = errors.New("match is paused")
+	ErrMatchNotPaused = errors.New("match is not paused")
+	ErrMatchTimedOut  = errors.New("match ran out of time")
+	ErrMatchActive    = errors.New("session already has an active match")
+	ErrInvalidOutcome = errors.New("outcome must be win or lose")
+)
+
 type MatchService struct {
-	repo ports.MatchRepo
+	repo    ports.MatchRepo
+	pauses  ports.MatchPauseRepo
+	metrics ports.GameMetrics
+	events  ports.EventPublisher
+
+	// BatchSize and PollInterval drive the worker that finishes matches
+	// which ran out of time.
+	BatchSize    int
+	PollInterval time.Duration
+
+	now func() time.Time
 }
 
-func NewMatchService(repo ports.MatchRepo) *MatchService {
-	return &MatchService{repo: repo}
+func NewMatchService(repo ports.MatchRepo, pauses ports.MatchPauseRepo, metrics ports.GameMetrics, events ports.EventPublisher) *MatchService {
+	return &MatchService{
+		repo:         repo,
+		pauses:       pauses,
+		metrics:      gameMetricsOrNop(metrics),
+		events:       eventPublisherOrNop(events),
+		BatchSize:    100,
+		PollInterval: 5 * time.Second,
+		now:          time.Now,
+	}
 }
 
-func (s *MatchService) Create(ctx context.Context, sessionID string, difficultyID, level int) (entity.Match, error) {
+// Create starts a match. meta is stored as given, e.g. the experiment arms
+// from MatchMeta; it may be nil.
+// A session plays one match at a time; Create answers ErrMatchActive while
+// the previous one is still open.
+func (s *MatchService) Create(ctx context.Context, sessionID string, difficultyID, level int, meta json.RawMessage) (entity.Match, error) {
+	switch _, err := s.repo.GetActiveBySession(ctx, sessionID); {
+	case err == nil:
+		return entity.Match{}, ErrMatchActive
+	case !errors.Is(err, pgx.ErrNoRows):
+		return entity.Match{}, err
+	}
 	match := entity.Match{
 		SessionID:    sessionID,
 		DifficultyID: difficultyID,
 		LevelN:       level,
 		IsActive:     true,
+		Meta:         meta,
+	}
+	created, err := s.repo.Create(ctx, match)
+	if err != nil {
+		return entity.Match{}, err
 	}
-	return s.repo.Create(ctx, match)
+	slog.InfoContext(ctx, "match started",
+		"session_id", created.SessionID,
+		"match_id", created.ID,
+		"difficulty_id", created.DifficultyID,
+		"level_n", created.LevelN,
+	)
+	return created, nil
 }
 
 func (s *MatchService) GetActiveBySession(ctx context.Context, sessionID string) (entity.Match, error) {
@@ -34,16 +87,136 @@ func (s *MatchService) Get(ctx context.Context, id string) (entity.Match, error)
 	return s.repo.Get(ctx, id)
 }
 
-func (s *MatchService) FinishActive(ctx context.Context, sessionID string, outcome *string) (entity.Match, error) {
+// FinishActive closes the active match of a session with the outcome the
+// client reports, OutcomeWin or OutcomeLose.
+func (s *MatchService) FinishActive(ctx context.Context, sessionID, outcome string) (entity.Match, error) {
+	if outcome != entity.OutcomeWin && outcome != entity.OutcomeLose {
+		return entity.Match{}, ErrInvalidOutcome
+	}
 	match, err := s.repo.GetActiveBySession(ctx, sessionID)
 	if err != nil {
 		return entity.Match{}, err
 	}
-	now := time.Now().UTC()
+	return s.finish(ctx, match, &outcome)
+}
+
+// finish closes an active match. When another caller finished it first, e.g.
+// the timeout worker and the client at once, it returns ErrMatchFinished and
+// leaves the first outcome, metrics and events alone.
+func (s *MatchService) finish(ctx context.Context, match entity.Match, outcome *string) (entity.Match, error) {
+	now := s.now().UTC()
 	match.IsActive = false
 	match.EndedAt = &now
 	if outcome != nil {
 		match.Outcome = outcome
 	}
-	return s.repo.Update(ctx, match)
+	updated, err := s.repo.Finish(ctx, match)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Match{}, ErrMatchFinished
+		}
+		return entity.Match{}, err
+	}
+	finished := ""
+	if updated.Outcome != nil {
+		finished = *updated.Outcome
+	}
+	s.metrics.MatchFinished(updated.DifficultyID, finished)
+	slog.InfoContext(ctx, "match finished",
+		"session_id", updated.SessionID,
+		"match_id", updated.ID,
+		"difficulty_id", updated.DifficultyID,
+		"outcome", finished,
+	)
+	publish(ctx, s.events, entity.MatchEvent{
+		Type:       entity.MatchEventMatchFinished,
+		MatchID:    updated.ID,
+		OccurredAt: now,
+		Match:      &updated,
+	})
+	return updated, nil
+}
+
+// Clock returns the time budget of a match.
+func (s *MatchService) Clock(ctx context.Context, match entity.Match, difficulty entity.Difficulty) (MatchClock, error) {
+	pauses, err := s.pauses.ListByMatch(ctx, match.ID)
+	if err != nil {
+		return MatchClock{}, err
+	}
+	return newMatchClock(match, difficulty, pauses, s.now()), nil
+}
+
+// Pause stops the clock of an active match, e.g. while the player takes the
+// headset off.
+func (s *MatchService) Pause(ctx context.Context, match entity.Match, difficulty entity.Difficulty) (MatchClock, error) {
+	if !match.IsActive {
+		return MatchClock{}, ErrMatchFinished
+	}
+	if _, err := s.pauses.Pause(ctx, match.ID, s.now().UTC()); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return MatchClock{}, ErrMatchPaused
+		}
+		return MatchClock{}, err
+	}
+	slog.InfoContext(ctx, "match paused", "match_id", match.ID)
+	return s.Clock(ctx, match, difficulty)
+}
+
+func (s *MatchService) Resume(ctx context.Context, match entity.Match, difficulty entity.Difficulty) (MatchClock, error) {
+	if !match.IsActive {
+		return MatchClock{}, ErrMatchFinished
+	}
+	if _, err := s.pauses.Resume(ctx, match.ID, s.now().UTC()); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return MatchClock{}, ErrMatchNotPaused
+		}
+		return MatchClock{}, err
+	}
+	slog.InfoContext(ctx, "match resumed", "match_id", match.ID)
+	return s.Clock(ctx, match, difficulty)
+}
+
+// Expire finishes a match that ran out of time with the timeout outcome.
+func (s *MatchService) Expire(ctx context.Context, match entity.Match) (entity.Match, error) {
+	outcome := entity.OutcomeTimeout
+	return s.finish(ctx, match, &outcome)
+}
+
+// Run finishes matches that ran out of time until ctx is done, so they time
+// out even if the client never comes back.
+func (s *MatchService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.ExpireDue(ctx); err != nil && ctx.Err() == nil {
+			slog.WarnContext(ctx, "match timeout round failed", "error", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// ExpireDue finishes one batch of matches that ran out of time and returns
+// how many were finished.
+func (s *MatchService) ExpireDue(ctx context.Context) (int, error) {
+	due, err := s.pauses.ListTimedOut(ctx, s.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	expired := 0
+	for _, match := range due {
+		_, err := s.Expire(ctx, match)
+		switch {
+		case err == nil:
+			expired++
+		case errors.Is(err, ErrMatchFinished):
+		default:
+			return expired, err
+		}
+	}
+	return expired, nil
 }
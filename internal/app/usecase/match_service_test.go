@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+type stubPauseRepo struct {
+	pauses   []entity.MatchPause
+	timedOut []entity.Match
+}
+
+func (r *stubPauseRepo) Pause(ctx context.Context, matchID string, at time.Time) (entity.MatchPause, error) {
+	if n := len(r.pauses); n > 0 && r.pauses[n-1].ResumedAt == nil {
+		return entity.MatchPause{}, pgx.ErrNoRows
+	}
+	pause := entity.MatchPause{ID: int64(len(r.pauses) + 1), MatchID: matchID, PausedAt: at}
+	r.pauses = append(r.pauses, pause)
+	return pause, nil
+}
+
+func (r *stubPauseRepo) Resume(ctx context.Context, matchID string, at time.Time) (entity.MatchPause, error) {
+	n := len(r.pauses)
+	if n == 0 || r.pauses[n-1].ResumedAt != nil {
+		return entity.MatchPause{}, pgx.ErrNoRows
+	}
+	r.pauses[n-1].ResumedAt = &at
+	return r.pauses[n-1], nil
+}
+
+func (r *stubPauseRepo) ListByMatch(ctx context.Context, matchID string) ([]entity.MatchPause, error) {
+	return r.pauses, nil
+}
+
+func (r *stubPauseRepo) ListTimedOut(ctx context.Context, limit int) ([]entity.Match, error) {
+	return r.timedOut, nil
+}
+
+// recordingMatchRepo finishes each match once, like the guarded query.
+type recordingMatchRepo struct {
+	stubMatchRepo
+	updated []entity.Match
+}
+
+func (r *recordingMatchRepo) Finish(ctx context.Context, match entity.Match) (entity.Match, error) {
+	for _, m := range r.updated {
+		if m.ID == match.ID {
+			return entity.Match{}, pgx.ErrNoRows
+		}
+	}
+	r.updated = append(r.updated, match)
+	return match, nil
+}
+
+type countingGameMetrics struct {
+	finished []string
+}
+
+func (m *countingGameMetrics) MoveRecorded(correct bool)  {}
+func (m *countingGameMetrics) MoveRejected(reason string) {}
+func (m *countingGameMetrics) MatchFinished(difficultyID int, outcome string) {
+	m.finished = append(m.finished, outcome)
+}
+
+func TestMatchServicePauseResumeClock(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	limit := 60000
+	match := entity.Match{ID: "m-1", StartedAt: start, IsActive: true}
+	difficulty := entity.Difficulty{ID: 1, NumberOfBlocks: 7, TimeLimitMs: &limit}
+
+	pauses := &stubPauseRepo{}
+	svc := NewMatchService(stubMatchRepo{}, pauses, nil, nil)
+	clock := func(d time.Duration) { svc.now = func() time.Time { return start.Add(d) } }
+	ctx := context.Background()
+
+	clock(10 * time.Second)
+	got, err := svc.Pause(ctx, match, difficulty)
+	require.NoError(t, err)
+	require.True(t, got.Paused)
+	require.Equal(t, 10000, got.ElapsedMs)
+
+	_, err = svc.Pause(ctx, match, difficulty)
+	require.ErrorIs(t, err, ErrMatchPaused)
+
+	// Time spent paused does not count.
+	clock(5 * time.Minute)
+	got, err = svc.Clock(ctx, match, difficulty)
+	require.NoError(t, err)
+	require.Equal(t, 10000, got.ElapsedMs)
+	require.Equal(t, 50000, *got.RemainingMs)
+
+	got, err = svc.Resume(ctx, match, difficulty)
+	require.NoError(t, err)
+	require.False(t, got.Paused)
+	require.Equal(t, 290000, got.PausedMs)
+	_, err = svc.Resume(ctx, match, difficulty)
+	require.ErrorIs(t, err, ErrMatchNotPaused)
+
+	clock(5*time.Minute + 50*time.Second)
+	got, err = svc.Clock(ctx, match, difficulty)
+	require.NoError(t, err)
+	require.Equal(t, 60000, got.ElapsedMs)
+	require.Equal(t, 0, *got.RemainingMs)
+	require.True(t, got.Expired)
+
+	_, err = svc.Pause(ctx, entity.Match{ID: "m-2"}, difficulty)
+	require.ErrorIs(t, err, ErrMatchFinished)
+}
+
+func TestMatchServiceExpireDue(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	repo := &recordingMatchRepo{}
+	pauses := &stubPauseRepo{timedOut: []entity.Match{{ID: "m-1", StartedAt: start, IsActive: true}}}
+	svc := NewMatchService(repo, pauses, nil, nil)
+
+	n, err := svc.ExpireDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Len(t, repo.updated, 1)
+	require.False(t, repo.updated[0].IsActive)
+	require.Equal(t, entity.OutcomeTimeout, *repo.updated[0].Outcome)
+	require.NotNil(t, repo.updated[0].EndedAt)
+}
+
+func TestMatchServiceFinishesOnce(t *testing.T) {
+	ctx := context.Background()
+	repo := &recordingMatchRepo{}
+	metrics := &countingGameMetrics{}
+	svc := NewMatchService(repo, &stubPauseRepo{}, metrics, nil)
+	match := entity.Match{ID: "m-1", SessionID: "s-1", IsActive: true}
+
+	won := entity.OutcomeWin
+	finished, err := svc.finish(ctx, match, &won)
+	require.NoError(t, err)
+	require.Equal(t, entity.OutcomeWin, *finished.Outcome)
+
+	// The timeout worker still holds the match as it was before the win.
+	_, err = svc.Expire(ctx, match)
+	require.ErrorIs(t, err, ErrMatchFinished)
+	require.Len(t, repo.updated, 1)
+	require.Equal(t, []string{entity.OutcomeWin}, metrics.finished, "a lost race is not counted")
+
+	pauses := &stubPauseRepo{timedOut: []entity.Match{match}}
+	svc.pauses = pauses
+	n, err := svc.ExpireDue(ctx)
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/game"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+// MatchUpdateSnapshot is the event name of the first update sent to a new
+// observer, describing the match as it currently stands.
+const MatchUpdateSnapshot = "snapshot"
+
+// MatchUpdate is what observers of a match receive: the triggering move, the
+// board after it as the server sees it and the KPIs recomputed by the
+// match_stats trigger.
+type MatchUpdate struct {
+	Event string           `json:"event"`
+	Seq   int              `json:"seq"`
+	Match *entity.Match    `json:"match,omitempty"`
+	Move  *entity.Move     `json:"move,omitempty"`
+	Board json.RawMessage  `json:"board,omitempty"`
+	KPI   *entity.MatchKPI `json:"kpi,omitempty"`
+}
+
+type MatchStreamService struct {
+	matches      ports.MatchRepo
+	moves        ports.MoveRepo
+	difficulties ports.DifficultyRepo
+	stats        ports.MatchStatsRepo
+	events       ports.EventSubscriber
+}
+
+func NewMatchStreamService(
+	matches ports.MatchRepo,
+	moves ports.MoveRepo,
+	difficulties ports.DifficultyRepo,
+	stats ports.MatchStatsRepo,
+	events ports.EventSubscriber,
+) *MatchStreamService {
+	return &MatchStreamService{matches: matches, moves: moves, difficulties: difficulties, stats: stats, events: events}
+}
+
+func (s *MatchStreamService) Snapshot(ctx context.Context, matchID string) (MatchUpdate, error) {
+	match, err := s.matches.Get(ctx, matchID)
+	if err != nil {
+		return MatchUpdate{}, err
+	}
+	update := MatchUpdate{Event: MatchUpdateSnapshot, Match: &match}
+
+	last, err := s.moves.GetLastByMatch(ctx, matchID)
+	switch {
+	case err == nil:
+		update.Seq = last.Seq
+		update.Move = &last
+	case !errors.Is(err, pgx.ErrNoRows):
+		return MatchUpdate{}, err
+	}
+	if update.Board, err = s.board(ctx, match, update.Seq); err != nil {
+		return MatchUpdate{}, err
+	}
+
+	if update.KPI, err = s.kpi(ctx, matchID); err != nil {
+		return MatchUpdate{}, err
+	}
+	return update, nil
+}
+
+// Subscribe streams updates for matchID until ctx is done. The channel is
+// closed early if the observer cannot keep up with the publishers.
+func (s *MatchStreamService) Subscribe(ctx context.Context, matchID string) (<-chan MatchUpdate, error) {
+	events, err := s.events.Subscribe(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(chan MatchUpdate)
+	go func() {
+		defer close(updates)
+		for event := range events {
+			update, err := s.enrich(ctx, event)
+			if err != nil {
+				slog.WarnContext(ctx, "failed to build match update", "match_id", matchID, "error", err)
+				continue
+			}
+			select {
+			case updates <- update:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return updates, nil
+}
+
+// enrich turns an event into an update. Events relayed from other replicas
+// only carry identifiers, so the move or match is loaded when missing.
+func (s *MatchStreamService) enrich(ctx context.Context, event entity.MatchEvent) (MatchUpdate, error) {
+	update := MatchUpdate{Event: event.Type, Seq: event.Seq, Match: event.Match, Move: event.Move}
+
+	switch {
+	case event.Type == entity.MatchEventMoveRecorded && update.Move == nil:
+		move, err := s.moves.GetBySeq(ctx, event.MatchID, event.Seq)
+		if err != nil {
+			return MatchUpdate{}, err
+		}
+		update.Move = &move
+	case event.Type == entity.MatchEventMatchFinished && update.Match == nil:
+		match, err := s.matches.Get(ctx, event.MatchID)
+		if err != nil {
+			return MatchUpdate{}, err
+		}
+		update.Match = &match
+	}
+	if update.Move != nil {
+		match := update.Match
+		if match == nil {
+			m, err := s.matches.Get(ctx, event.MatchID)
+			if err != nil {
+				return MatchUpdate{}, err
+			}
+			match = &m
+		}
+		board, err := s.board(ctx, *match, update.Move.Seq)
+		if err != nil {
+			return MatchUpdate{}, err
+		}
+		update.Board = board
+	}
+
+	var err error
+	if update.KPI, err = s.kpi(ctx, event.MatchID); err != nil {
+		return MatchUpdate{}, err
+	}
+	return update, nil
+}
+
+// board rebuilds the board of match after move seq from the layout of its
+// difficulty version and the move log. Boards recorded on the moves are not
+// enough: they are left out for players without research consent.
+func (s *MatchStreamService) board(ctx context.Context, match entity.Match, seq int) (json.RawMessage, error) {
+	difficulty, err := s.difficulties.GetVersion(ctx, match.DifficultyID, match.DifficultyVersion)
+	if err != nil {
+		return nil, err
+	}
+	initial, err := game.FromLayout(difficulty.Layout, difficulty.NumberOfBlocks)
+	if err != nil {
+		return nil, err
+	}
+	moves, err := s.moves.GetByMatch(ctx, match.ID)
+	if err != nil {
+		return nil, err
+	}
+	board := newMatchBoard(initial)
+	for _, mv := range moves {
+		if mv.Seq > seq {
+			break
+		}
+		board.apply(mv)
+	}
+	return json.Marshal(board.current())
+}
+
+func (s *MatchStreamService) kpi(ctx context.Context, matchID string) (*entity.MatchKPI, error) {
+	kpi, err := s.stats.GetByMatch(ctx, matchID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &kpi, nil
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+func TestMatchStreamBoardIgnoresMissingRecordedBoards(t *testing.T) {
+	ctx := context.Background()
+	match := entity.Match{ID: "m-1", DifficultyID: 1, IsActive: true}
+	// Boards left out by the consent trigger.
+	moves := []entity.Move{
+		{MatchID: "m-1", Seq: 1, FromIdx: 1, ToIdx: 2, MoveKind: entity.MoveKindStep},
+		{MatchID: "m-1", Seq: 2, FromIdx: 3, ToIdx: 1, MoveKind: entity.MoveKindJump},
+	}
+	svc := NewMatchStreamService(
+		stubMatchRepo{matches: map[string]entity.Match{"m-1": match}},
+		stubMoveRepo{moves: moves},
+		stubDifficultyRepo{difficulties: []entity.Difficulty{{ID: 1, NumberOfBlocks: 5}}},
+		stubMatchStatsRepo{},
+		nil,
+	)
+
+	snapshot, err := svc.Snapshot(ctx, "m-1")
+	require.NoError(t, err)
+	require.Equal(t, 2, snapshot.Seq)
+	require.JSONEq(t, `[1,2,1,0,2]`, string(snapshot.Board))
+
+	update, err := svc.enrich(ctx, entity.MatchEvent{Type: entity.MatchEventMoveRecorded, MatchID: "m-1", Seq: 1})
+	require.NoError(t, err)
+	require.JSONEq(t, `[1,0,1,2,2]`, string(update.Board))
+}
@@ -0,0 +1,16 @@
+package usecase
+
+import "github.com/org/ranas-bdi-backend/internal/domain/ports"
+
+type nopGameMetrics struct{}
+
+func (nopGameMetrics) MoveRecorded(bool)         {}
+func (nopGameMetrics) MoveRejected(string)       {}
+func (nopGameMetrics) MatchFinished(int, string) {}
+
+func gameMetricsOrNop(m ports.GameMetrics) ports.GameMetrics {
+	if m == nil {
+		return nopGameMetrics{}
+	}
+	return m
+}
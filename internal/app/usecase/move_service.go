@@ -2,21 +2,258 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/game"
 	"github.com/org/ranas-bdi-backend/internal/domain/ports"
 )
 
+// Reasons reported to GameMetrics.MoveRejected.
+const (
+	RejectInvalidPayload = "invalid_payload"
+	RejectMatchNotFound  = "match_not_found"
+	RejectMatchFinished  = "match_finished"
+	RejectMatchPaused    = "match_paused"
+	RejectMatchTimedOut  = "match_timed_out"
+	RejectIllegalMove    = "illegal_move"
+)
+
+var (
+	// ErrBoardAtStart is returned when undoing or restarting a match whose
+	// board is still at the initial position.
+	ErrBoardAtStart = errors.New("board is already at the initial position")
+	// ErrIllegalMove wraps the rule of the puzzle a submitted move breaks.
+	ErrIllegalMove = errors.New("illegal move")
+	// ErrBoardNotSolved is returned when a win is reported for a match whose
+	// server board is not solved.
+	ErrBoardNotSolved = errors.New("board is not solved")
+)
+
 type MoveService struct {
-	repo ports.MoveRepo
+	repo    ports.MoveRepo
+	pauses  ports.MatchPauseRepo
+	metrics ports.GameMetrics
+	events  ports.EventPublisher
+
+	now func() time.Time
+}
+
+func NewMoveService(repo ports.MoveRepo, pauses ports.MatchPauseRepo, metrics ports.GameMetrics, events ports.EventPublisher) *MoveService {
+	return &MoveService{
+		repo:    repo,
+		pauses:  pauses,
+		metrics: gameMetricsOrNop(metrics),
+		events:  eventPublisherOrNop(events),
+		now:     time.Now,
+	}
+}
+
+// Create plays the frog on block from to block to on the server board of
+// match, rebuilt from its move log. The engine decides everything about the
+// move: its kind, the frog, the boards and whether it is correct, i.e. the
+// puzzle can still be solved after it. Moves breaking the rules return
+// ErrIllegalMove. The server assigns the seq and the timing; see stamp.
+func (s *MoveService) Create(ctx context.Context, match entity.Match, difficulty entity.Difficulty, from, to int) (entity.Move, error) {
+	board, moves, err := s.board(ctx, match.ID, difficulty)
+	if err != nil {
+		return entity.Move{}, err
+	}
+	before := board.current()
+	if err := before.Legal(from, to); err != nil {
+		return entity.Move{}, fmt.Errorf("%w: %w", ErrIllegalMove, err)
+	}
+	after, err := before.Apply(from, to)
+	if err != nil {
+		return entity.Move{}, err
+	}
+
+	branching := len(before.LegalMoves())
+	// Going back to a position already reached is a loop.
+	loop := 0.0
+	if board.seen(after) {
+		loop = 1
+	}
+	move := entity.Move{
+		FromIdx:         from,
+		ToIdx:           to,
+		MoveKind:        entity.MoveKindStep,
+		FrogSide:        int16(before[from]),
+		IsCorrect:       game.NewSolver().Distance(after) >= 0,
+		BranchingFactor: &branching,
+		Buclicidad:      &loop,
+	}
+	if to-from == 2 || from-to == 2 {
+		move.MoveKind = entity.MoveKindJump
+	}
+	if move.BoardBefore, err = json.Marshal(before); err != nil {
+		return entity.Move{}, err
+	}
+	if move.BoardAfter, err = json.Marshal(after); err != nil {
+		return entity.Move{}, err
+	}
+
+	var previous *entity.Move
+	if len(moves) > 0 {
+		previous = &moves[len(moves)-1]
+	}
+	if err := s.stamp(ctx, match, previous, &move); err != nil {
+		return entity.Move{}, err
+	}
+	created, err := s.record(ctx, move)
+	if err != nil {
+		return entity.Move{}, err
+	}
+	s.metrics.MoveRecorded(created.IsCorrect)
+	return created, nil
+}
+
+// Undo records an undo in the move log, rolling the board back to where it
+// was before the last frog move still on it. difficulty is the version the
+// match is played on.
+func (s *MoveService) Undo(ctx context.Context, match entity.Match, difficulty entity.Difficulty) (entity.Move, error) {
+	board, moves, err := s.board(ctx, match.ID, difficulty)
+	if err != nil {
+		return entity.Move{}, err
+	}
+	last, ok := board.lastPlayed()
+	if !ok {
+		return entity.Move{}, ErrBoardAtStart
+	}
+	before := board.current()
+	undo := entity.Move{
+		MoveKind: entity.MoveKindUndo,
+		FromIdx:  last.ToIdx,
+		ToIdx:    last.FromIdx,
+		FrogSide: last.FrogSide,
+	}
+	board.apply(undo)
+	// An undo is an explicit step back, the strongest form of buclicidad.
+	backtrack := 1.0
+	undo.Buclicidad = &backtrack
+	return s.recordAction(ctx, match, moves, undo, before, board.current())
+}
+
+// Restart records a restart in the move log and puts the board back to the
+// initial position.
+func (s *MoveService) Restart(ctx context.Context, match entity.Match, difficulty entity.Difficulty) (entity.Move, error) {
+	board, moves, err := s.board(ctx, match.ID, difficulty)
+	if err != nil {
+		return entity.Move{}, err
+	}
+	if _, ok := board.lastPlayed(); !ok {
+		return entity.Move{}, ErrBoardAtStart
+	}
+	before := board.current()
+	restart := entity.Move{MoveKind: entity.MoveKindRestart}
+	board.apply(restart)
+	return s.recordAction(ctx, match, moves, restart, before, board.current())
+}
+
+// Solved reports whether the server board of match, rebuilt from its move
+// log, is solved, so a reported win can be checked.
+func (s *MoveService) Solved(ctx context.Context, match entity.Match, difficulty entity.Difficulty) (bool, error) {
+	board, _, err := s.board(ctx, match.ID, difficulty)
+	if err != nil {
+		return false, err
+	}
+	return board.current().Solved(), nil
+}
+
+// board rebuilds the server board of a match from its move log.
+func (s *MoveService) board(ctx context.Context, matchID string, difficulty entity.Difficulty) (*matchBoard, []entity.Move, error) {
+	initial, err := game.FromLayout(difficulty.Layout, difficulty.NumberOfBlocks)
+	if err != nil {
+		return nil, nil, err
+	}
+	moves, err := s.repo.GetByMatch(ctx, matchID)
+	if err != nil {
+		return nil, nil, err
+	}
+	board := newMatchBoard(initial)
+	for _, mv := range moves {
+		board.apply(mv)
+	}
+	return board, moves, nil
+}
+
+// recordAction stamps an undo or restart with the boards it moved between
+// and stores it. Actions are never errors.
+func (s *MoveService) recordAction(ctx context.Context, match entity.Match, moves []entity.Move, action entity.Move, before, after game.Board) (entity.Move, error) {
+	var previous *entity.Move
+	if len(moves) > 0 {
+		previous = &moves[len(moves)-1]
+	}
+	if err := s.stamp(ctx, match, previous, &action); err != nil {
+		return entity.Move{}, err
+	}
+	action.IsCorrect = true
+
+	var err error
+	if action.BoardBefore, err = json.Marshal(before); err != nil {
+		return entity.Move{}, err
+	}
+	if action.BoardAfter, err = json.Marshal(after); err != nil {
+		return entity.Move{}, err
+	}
+	return s.record(ctx, action)
+}
+
+// stamp fills in the match, seq and timing of a move. ElapsedMs is the time
+// since the previous move, or the match start, minus the time spent paused,
+// so taking the headset off does not inflate think times. Paused matches
+// take no moves.
+func (s *MoveService) stamp(ctx context.Context, match entity.Match, previous *entity.Move, move *entity.Move) error {
+	pauses, err := s.pauses.ListByMatch(ctx, match.ID)
+	if err != nil {
+		return err
+	}
+	if n := len(pauses); n > 0 && pauses[n-1].ResumedAt == nil {
+		return ErrMatchPaused
+	}
+
+	now := s.now().UTC()
+	since := match.StartedAt
+	move.Seq = 1
+	if previous != nil {
+		since = previous.OccurredAt
+		move.Seq = previous.Seq + 1
+	}
+	move.MatchID = match.ID
+	move.OccurredAt = now
+	move.ElapsedMs = int(max(0, now.Sub(since)-pausedBetween(pauses, since, now)).Milliseconds())
+	return nil
 }
 
-func NewMoveService(repo ports.MoveRepo) *MoveService {
-	return &MoveService{repo: repo}
+func (s *MoveService) record(ctx context.Context, move entity.Move) (entity.Move, error) {
+	created, err := s.repo.Create(ctx, move)
+	if err != nil {
+		return entity.Move{}, err
+	}
+	slog.InfoContext(ctx, "move recorded",
+		"match_id", created.MatchID,
+		"seq", created.Seq,
+		"move_kind", created.MoveKind,
+		"is_correct", created.IsCorrect,
+	)
+	publish(ctx, s.events, entity.MatchEvent{
+		Type:       entity.MatchEventMoveRecorded,
+		MatchID:    created.MatchID,
+		Seq:        created.Seq,
+		OccurredAt: created.OccurredAt,
+		Move:       &created,
+	})
+	return created, nil
 }
 
-func (s *MoveService) Create(ctx context.Context, move entity.Move) (entity.Move, error) {
-	return s.repo.Create(ctx, move)
+// Reject records a move submission that was refused before reaching the
+// repository.
+func (s *MoveService) Reject(reason string) {
+	s.metrics.MoveRejected(reason)
 }
 
 func (s *MoveService) ListByMatch(ctx context.Context, matchID string) ([]entity.Move, error) {
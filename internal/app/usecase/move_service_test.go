@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/game"
+)
+
+func TestMoveServiceUndoAndRestart(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	match := entity.Match{ID: "m-1", StartedAt: start, IsActive: true}
+	tiny := entity.Difficulty{ID: 1, NumberOfBlocks: 3}
+	moves := []entity.Move{
+		{MatchID: "m-1", Seq: 1, OccurredAt: start.Add(time.Second), FromIdx: 0, ToIdx: 1, MoveKind: entity.MoveKindStep, FrogSide: 1, IsCorrect: true},
+		{MatchID: "m-1", Seq: 2, OccurredAt: start.Add(3 * time.Second), FromIdx: 2, ToIdx: 0, MoveKind: entity.MoveKindJump, FrogSide: 2, IsCorrect: true},
+	}
+	svc := NewMoveService(stubMoveRepo{moves: moves}, &stubPauseRepo{}, nil, nil)
+	svc.now = func() time.Time { return start.Add(5 * time.Second) }
+
+	undo, err := svc.Undo(context.Background(), match, tiny)
+	require.NoError(t, err)
+	require.Equal(t, entity.MoveKindUndo, undo.MoveKind)
+	require.Equal(t, 3, undo.Seq)
+	require.Equal(t, 0, undo.FromIdx)
+	require.Equal(t, 2, undo.ToIdx)
+	require.Equal(t, int16(2), undo.FrogSide)
+	require.Equal(t, 2000, undo.ElapsedMs)
+	require.True(t, undo.IsCorrect, "undos are not errors")
+	require.Equal(t, 1.0, *undo.Buclicidad)
+	require.JSONEq(t, `[2,1,0]`, string(undo.BoardBefore))
+	require.JSONEq(t, `[0,1,2]`, string(undo.BoardAfter))
+
+	// With the undo in the log, a restart goes back past the first move.
+	svc = NewMoveService(stubMoveRepo{moves: append(moves, undo)}, &stubPauseRepo{}, nil, nil)
+	restart, err := svc.Restart(context.Background(), match, tiny)
+	require.NoError(t, err)
+	require.Equal(t, entity.MoveKindRestart, restart.MoveKind)
+	require.Equal(t, 4, restart.Seq)
+	require.JSONEq(t, `[0,1,2]`, string(restart.BoardBefore))
+	require.JSONEq(t, `[1,0,2]`, string(restart.BoardAfter))
+
+	svc = NewMoveService(stubMoveRepo{moves: append(moves, undo, restart)}, &stubPauseRepo{}, nil, nil)
+	_, err = svc.Undo(context.Background(), match, tiny)
+	require.ErrorIs(t, err, ErrBoardAtStart)
+	_, err = svc.Restart(context.Background(), match, tiny)
+	require.ErrorIs(t, err, ErrBoardAtStart)
+}
+
+func TestMoveServiceCreateExcludesPauses(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	match := entity.Match{ID: "m-1", StartedAt: start, IsActive: true}
+	tiny := entity.Difficulty{ID: 1, NumberOfBlocks: 3}
+	previous := entity.Move{MatchID: "m-1", Seq: 1, OccurredAt: start.Add(2 * time.Second), FromIdx: 0, ToIdx: 1, MoveKind: entity.MoveKindStep}
+	resumedAt := start.Add(62 * time.Second)
+	pauses := &stubPauseRepo{pauses: []entity.MatchPause{{MatchID: "m-1", PausedAt: start.Add(3 * time.Second), ResumedAt: &resumedAt}}}
+
+	svc := NewMoveService(stubMoveRepo{moves: []entity.Move{previous}}, pauses, nil, nil)
+	svc.now = func() time.Time { return start.Add(65 * time.Second) }
+
+	created, err := svc.Create(context.Background(), match, tiny, 2, 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, created.Seq)
+	require.Equal(t, "m-1", created.MatchID)
+	require.Equal(t, 4000, created.ElapsedMs, "63s since the last move minus the 59s pause")
+
+	_, err = pauses.Pause(context.Background(), "m-1", start.Add(66*time.Second))
+	require.NoError(t, err)
+	_, err = svc.Create(context.Background(), match, tiny, 2, 0)
+	require.ErrorIs(t, err, ErrMatchPaused)
+}
+
+func TestMoveServiceCreateUsesTheEngine(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	match := entity.Match{ID: "m-1", StartedAt: start, IsActive: true}
+	standard := entity.Difficulty{ID: 1, NumberOfBlocks: 5}
+	svc := NewMoveService(stubMoveRepo{}, &stubPauseRepo{}, nil, nil)
+	svc.now = func() time.Time { return start.Add(time.Second) }
+
+	// [1,1,0,2,2]: the left frog on block 1 steps into the empty block.
+	step, err := svc.Create(context.Background(), match, standard, 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, entity.MoveKindStep, step.MoveKind)
+	require.Equal(t, int16(game.Left), step.FrogSide)
+	require.True(t, step.IsCorrect)
+	require.Equal(t, 4, *step.BranchingFactor)
+	require.Equal(t, 0.0, *step.Buclicidad)
+	require.JSONEq(t, `[1,1,0,2,2]`, string(step.BoardBefore))
+	require.JSONEq(t, `[1,0,1,2,2]`, string(step.BoardAfter))
+
+	svc = NewMoveService(stubMoveRepo{moves: []entity.Move{step}}, &stubPauseRepo{}, nil, nil)
+	jump, err := svc.Create(context.Background(), match, standard, 3, 1)
+	require.NoError(t, err)
+	require.Equal(t, entity.MoveKindJump, jump.MoveKind)
+	require.Equal(t, int16(game.Right), jump.FrogSide)
+	require.True(t, jump.IsCorrect)
+
+	// Stepping the last left frog instead fills the left side: nobody can
+	// move any more.
+	dead, err := svc.Create(context.Background(), match, standard, 0, 1)
+	require.NoError(t, err)
+	require.False(t, dead.IsCorrect)
+
+	_, err = svc.Create(context.Background(), match, standard, 3, 2)
+	require.ErrorIs(t, err, ErrIllegalMove)
+	require.ErrorIs(t, err, game.ErrOccupied)
+	_, err = svc.Create(context.Background(), match, standard, 2, 1)
+	require.ErrorIs(t, err, game.ErrWrongDirection)
+
+	// Replaying the first move after an undo goes back to a position already
+	// reached.
+	undo := entity.Move{MatchID: "m-1", Seq: 2, MoveKind: entity.MoveKindUndo, FromIdx: 2, ToIdx: 1}
+	svc = NewMoveService(stubMoveRepo{moves: []entity.Move{step, undo}}, &stubPauseRepo{}, nil, nil)
+	again, err := svc.Create(context.Background(), match, standard, 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, 1.0, *again.Buclicidad)
+}
@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+// OutboxHandler consumes a domain event. Delivery is at-least-once, so
+// handlers must tolerate seeing the same event ID more than once.
+type OutboxHandler func(ctx context.Context, event entity.OutboxEvent) error
+
+type outboxSubscription struct {
+	name   string
+	types  map[string]bool
+	handle OutboxHandler
+}
+
+func (s outboxSubscription) wants(eventType string) bool {
+	return len(s.types) == 0 || s.types[eventType]
+}
+
+// OutboxRelay polls the outbox and dispatches due events to the registered
+// handlers. An event is marked delivered once every interested handler has
+// succeeded; otherwise it is retried for all of them with exponential backoff
+// until MaxAttempts, after which it is kept as a dead letter.
+type OutboxRelay struct {
+	repo          ports.OutboxRepo
+	subscriptions []outboxSubscription
+
+	BatchSize    int
+	PollInterval time.Duration
+	Lease        time.Duration
+	MinBackoff   time.Duration
+	MaxBackoff   time.Duration
+	MaxAttempts  int
+
+	// Delivered events are deleted once they are older than
+	// DeliveredRetention, checked every PruneInterval. Zero keeps them.
+	DeliveredRetention time.Duration
+	PruneInterval      time.Duration
+
+	now func() time.Time
+}
+
+func NewOutboxRelay(repo ports.OutboxRepo) *OutboxRelay {
+	return &OutboxRelay{
+		repo:               repo,
+		BatchSize:          100,
+		PollInterval:       time.Second,
+		Lease:              time.Minute,
+		MinBackoff:         time.Second,
+		MaxBackoff:         time.Hour,
+		MaxAttempts:        20,
+		DeliveredRetention: 7 * 24 * time.Hour,
+		PruneInterval:      time.Hour,
+		now:                time.Now,
+	}
+}
+
+// Register subscribes handle to the given event types, or to every type when
+// none are given. It must be called before Run.
+func (r *OutboxRelay) Register(name string, handle OutboxHandler, eventTypes ...string) {
+	sub := outboxSubscription{name: name, handle: handle}
+	if len(eventTypes) > 0 {
+		sub.types = make(map[string]bool, len(eventTypes))
+		for _, t := range eventTypes {
+			sub.types[t] = true
+		}
+	}
+	r.subscriptions = append(r.subscriptions, sub)
+}
+
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.PollInterval)
+	defer ticker.Stop()
+
+	var prune <-chan time.Time
+	if r.DeliveredRetention > 0 {
+		pruneTicker := time.NewTicker(r.PruneInterval)
+		defer pruneTicker.Stop()
+		prune = pruneTicker.C
+	}
+
+	for {
+		for {
+			n, err := r.DispatchPending(ctx)
+			if err != nil && ctx.Err() == nil {
+				slog.WarnContext(ctx, "outbox dispatch failed", "error", err)
+			}
+			// A full batch suggests a backlog; keep draining without waiting.
+			if err != nil || n < r.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-prune:
+			if _, err := r.Prune(ctx); err != nil && ctx.Err() == nil {
+				slog.WarnContext(ctx, "outbox prune failed", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Prune deletes the events delivered more than DeliveredRetention ago, a
+// batch at a time, and returns how many were deleted.
+func (r *OutboxRelay) Prune(ctx context.Context) (int, error) {
+	before := r.now().Add(-r.DeliveredRetention)
+	pruned := 0
+	for {
+		n, err := r.repo.PruneDelivered(ctx, before, r.BatchSize)
+		pruned += n
+		if err != nil || n < r.BatchSize {
+			return pruned, err
+		}
+	}
+}
+
+// DispatchPending claims one batch of due events and dispatches it, returning
+// how many events were claimed.
+func (r *OutboxRelay) DispatchPending(ctx context.Context) (int, error) {
+	events, err := r.repo.ClaimPending(ctx, r.BatchSize, r.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		if err := r.dispatch(ctx, event); err != nil {
+			var retryAt *time.Time
+			if event.Attempts < r.MaxAttempts {
+				next := r.now().Add(r.backoff(event.Attempts))
+				retryAt = &next
+			}
+			slog.WarnContext(ctx, "outbox event delivery failed",
+				"event_id", event.ID,
+				"event_type", event.Type,
+				"attempts", event.Attempts,
+				"retry_at", retryAt,
+				"give_up", retryAt == nil,
+				"error", err,
+			)
+			if err := r.repo.MarkFailed(ctx, event.ID, retryAt, err.Error()); err != nil {
+				return len(events), err
+			}
+			continue
+		}
+		if err := r.repo.MarkDelivered(ctx, event.ID); err != nil {
+			return len(events), err
+		}
+	}
+	return len(events), nil
+}
+
+func (r *OutboxRelay) dispatch(ctx context.Context, event entity.OutboxEvent) error {
+	var errs []error
+	for _, sub := range r.subscriptions {
+		if !sub.wants(event.Type) {
+			continue
+		}
+		if err := sub.handle(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	return exponentialBackoff(r.MinBackoff, r.MaxBackoff, attempts)
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+type stubOutboxRepo struct {
+	pending     []entity.OutboxEvent
+	delivered   []int64
+	failed      map[int64]*time.Time
+	deliveredAt map[int64]time.Time
+	pruneBefore []time.Time
+}
+
+func (r *stubOutboxRepo) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxEvent, error) {
+	events := r.pending
+	r.pending = nil
+	return events, nil
+}
+
+func (r *stubOutboxRepo) MarkDelivered(ctx context.Context, id int64) error {
+	r.delivered = append(r.delivered, id)
+	return nil
+}
+
+func (r *stubOutboxRepo) MarkFailed(ctx context.Context, id int64, retryAt *time.Time, cause string) error {
+	if r.failed == nil {
+		r.failed = make(map[int64]*time.Time)
+	}
+	r.failed[id] = retryAt
+	return nil
+}
+
+func (r *stubOutboxRepo) PruneDelivered(ctx context.Context, before time.Time, limit int) (int, error) {
+	r.pruneBefore = append(r.pruneBefore, before)
+	pruned := 0
+	for id, at := range r.deliveredAt {
+		if at.Before(before) && pruned < limit {
+			delete(r.deliveredAt, id)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+func TestOutboxRelayDispatchPending(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := &stubOutboxRepo{pending: []entity.OutboxEvent{
+		{ID: 1, Type: entity.EventMoveRecorded, Attempts: 1},
+		{ID: 2, Type: entity.EventMatchFinished, Attempts: 3},
+		{ID: 3, Type: entity.EventSessionFinished, Attempts: 1},
+		{ID: 4, Type: entity.EventMatchFinished, Attempts: 20},
+	}}
+
+	relay := NewOutboxRelay(repo)
+	relay.now = func() time.Time { return now }
+
+	var all, finished []int64
+	relay.Register("all", func(ctx context.Context, e entity.OutboxEvent) error {
+		all = append(all, e.ID)
+		return nil
+	})
+	relay.Register("finished", func(ctx context.Context, e entity.OutboxEvent) error {
+		finished = append(finished, e.ID)
+		if e.ID == 2 || e.ID == 4 {
+			return errors.New("downstream unavailable")
+		}
+		return nil
+	}, entity.EventMatchFinished, entity.EventSessionFinished)
+
+	n, err := relay.DispatchPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 4, n)
+
+	require.Equal(t, []int64{1, 2, 3, 4}, all)
+	require.Equal(t, []int64{2, 3, 4}, finished)
+	require.Equal(t, []int64{1, 3}, repo.delivered)
+	retryAt := now.Add(4 * time.Second)
+	// Event 4 used its last attempt and becomes a dead letter.
+	require.Equal(t, map[int64]*time.Time{2: &retryAt, 4: nil}, repo.failed)
+}
+
+func TestOutboxRelayPrune(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	repo := &stubOutboxRepo{deliveredAt: map[int64]time.Time{
+		1: now.Add(-30 * 24 * time.Hour),
+		2: now.Add(-8 * 24 * time.Hour),
+		3: now.Add(-8 * 24 * time.Hour),
+		4: now.Add(-time.Hour),
+	}}
+	relay := NewOutboxRelay(repo)
+	relay.BatchSize = 2
+	relay.now = func() time.Time { return now }
+
+	n, err := relay.Prune(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.Len(t, repo.pruneBefore, 2, "a full batch asks for another")
+	require.Equal(t, now.Add(-7*24*time.Hour), repo.pruneBefore[0])
+	require.Contains(t, repo.deliveredAt, int64(4))
+}
+
+func TestOutboxRelayBackoffIsCapped(t *testing.T) {
+	relay := NewOutboxRelay(&stubOutboxRepo{})
+	require.Equal(t, time.Second, relay.backoff(1))
+	require.Equal(t, 8*time.Second, relay.backoff(4))
+	require.Equal(t, time.Hour, relay.backoff(50))
+}
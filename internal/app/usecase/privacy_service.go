@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+// PrivacyService answers data subject requests: access to everything stored
+// about a player, deletion and anonymization. Every operation leaves a
+// privacy audit record.
+type PrivacyService struct {
+	repo     ports.PrivacyRepo
+	exports  ports.ExportRepo
+	cohorts  ports.CohortRepo
+	consents ports.ConsentRepo
+
+	now func() time.Time
+}
+
+func NewPrivacyService(repo ports.PrivacyRepo, exports ports.ExportRepo, cohorts ports.CohortRepo, consents ports.ConsentRepo) *PrivacyService {
+	return &PrivacyService{
+		repo:     repo,
+		exports:  exports,
+		cohorts:  cohorts,
+		consents: consents,
+		now:      time.Now,
+	}
+}
+
+// Export gathers the data of request.PlayerID. Sessions, matches, moves and
+// stats are read through the export queries, so the bundle has the same
+// fields as the data exports.
+func (s *PrivacyService) Export(ctx context.Context, request entity.PrivacyAudit) (entity.PlayerData, error) {
+	data := entity.PlayerData{
+		PlayerID:    request.PlayerID,
+		GeneratedAt: s.now().UTC(),
+		Sessions:    []entity.Session{},
+		Matches:     []entity.Match{},
+		Moves:       []entity.Move{},
+		Stats:       []entity.MatchKPI{},
+	}
+	filter := entity.ExportFilter{PlayerID: &request.PlayerID}
+	if err := s.exports.EachSession(ctx, filter, collect(&data.Sessions)); err != nil {
+		return entity.PlayerData{}, err
+	}
+	if err := s.exports.EachMatch(ctx, filter, collect(&data.Matches)); err != nil {
+		return entity.PlayerData{}, err
+	}
+	if err := s.exports.EachMove(ctx, filter, collect(&data.Moves)); err != nil {
+		return entity.PlayerData{}, err
+	}
+	if err := s.exports.EachMatchKPI(ctx, filter, collect(&data.Stats)); err != nil {
+		return entity.PlayerData{}, err
+	}
+	cohorts, err := s.cohorts.PlayerCohorts(ctx, request.PlayerID)
+	if err != nil {
+		return entity.PlayerData{}, err
+	}
+	data.Cohorts = nonNil(cohorts)
+	consents, err := s.consents.ListByPlayer(ctx, request.PlayerID)
+	if err != nil {
+		return entity.PlayerData{}, err
+	}
+	data.Consents = nonNil(consents)
+
+	request.Action = entity.PrivacyExport
+	request.SessionsAffected = len(data.Sessions)
+	if _, err := s.record(ctx, request, s.repo.RecordAudit); err != nil {
+		return entity.PlayerData{}, err
+	}
+	return data, nil
+}
+
+// Delete removes the player's data. Consent records are kept as evidence of
+// what the player agreed to.
+func (s *PrivacyService) Delete(ctx context.Context, request entity.PrivacyAudit) (entity.PrivacyAudit, error) {
+	request.Action = entity.PrivacyDelete
+	return s.record(ctx, request, s.repo.Delete)
+}
+
+// Anonymize detaches the player's data from them, keeping it for analytics.
+func (s *PrivacyService) Anonymize(ctx context.Context, request entity.PrivacyAudit) (entity.PrivacyAudit, error) {
+	request.Action = entity.PrivacyAnonymize
+	return s.record(ctx, request, s.repo.Anonymize)
+}
+
+// Audit lists the latest privacy audit records, only those of playerID when
+// it is set.
+func (s *PrivacyService) Audit(ctx context.Context, playerID *string, limit int) ([]entity.PrivacyAudit, error) {
+	records, err := s.repo.ListAudit(ctx, playerID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return nonNil(records), nil
+}
+
+func (s *PrivacyService) record(
+	ctx context.Context,
+	request entity.PrivacyAudit,
+	run func(context.Context, entity.PrivacyAudit) (entity.PrivacyAudit, error),
+) (entity.PrivacyAudit, error) {
+	if request.Reason == "" {
+		request.Reason = entity.PrivacyReasonAdminRequest
+	}
+	audit, err := run(ctx, request)
+	if err != nil {
+		return entity.PrivacyAudit{}, err
+	}
+	slog.InfoContext(ctx, "privacy operation",
+		"action", audit.Action,
+		"player_id", audit.PlayerID,
+		"sessions", audit.SessionsAffected,
+		"audit_id", audit.ID,
+	)
+	return audit, nil
+}
+
+func collect[T any](dst *[]T) func(T) error {
+	return func(v T) error {
+		*dst = append(*dst, v)
+		return nil
+	}
+}
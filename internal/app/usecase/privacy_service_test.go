@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+type stubPrivacyRepo struct {
+	audits []entity.PrivacyAudit
+}
+
+func (r *stubPrivacyRepo) Delete(ctx context.Context, audit entity.PrivacyAudit) (entity.PrivacyAudit, error) {
+	audit.SessionsAffected = 3
+	return r.RecordAudit(ctx, audit)
+}
+
+func (r *stubPrivacyRepo) Anonymize(ctx context.Context, audit entity.PrivacyAudit) (entity.PrivacyAudit, error) {
+	audit.SessionsAffected = 3
+	return r.RecordAudit(ctx, audit)
+}
+
+func (r *stubPrivacyRepo) RecordAudit(ctx context.Context, audit entity.PrivacyAudit) (entity.PrivacyAudit, error) {
+	audit.ID = int64(len(r.audits) + 1)
+	r.audits = append(r.audits, audit)
+	return audit, nil
+}
+
+func (r *stubPrivacyRepo) ListAudit(ctx context.Context, playerID *string, limit int) ([]entity.PrivacyAudit, error) {
+	return nil, nil
+}
+
+// stubPlayerExportRepo serves one session with one match of a single move.
+type stubPlayerExportRepo struct {
+	filters []entity.ExportFilter
+}
+
+func (r *stubPlayerExportRepo) EachSession(ctx context.Context, filter entity.ExportFilter, fn func(entity.Session) error) error {
+	r.filters = append(r.filters, filter)
+	return fn(entity.Session{ID: "s-1", PlayerID: filter.PlayerID})
+}
+
+func (r *stubPlayerExportRepo) EachMatch(ctx context.Context, filter entity.ExportFilter, fn func(entity.Match) error) error {
+	return fn(entity.Match{ID: "m-1", SessionID: "s-1"})
+}
+
+func (r *stubPlayerExportRepo) EachMove(ctx context.Context, filter entity.ExportFilter, fn func(entity.Move) error) error {
+	return fn(entity.Move{MatchID: "m-1", Seq: 1})
+}
+
+func (r *stubPlayerExportRepo) EachMatchKPI(ctx context.Context, filter entity.ExportFilter, fn func(entity.MatchKPI) error) error {
+	return nil
+}
+
+func TestPrivacyServiceExport(t *testing.T) {
+	ctx := context.Background()
+	audits := &stubPrivacyRepo{}
+	exports := &stubPlayerExportRepo{}
+	cohorts := &stubCohortRepo{set: map[string][]string{"p-1": {"class-a"}}}
+	svc := NewPrivacyService(audits, exports, cohorts, &stubConsentRepo{})
+	svc.now = func() time.Time { return time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC) }
+
+	data, err := svc.Export(ctx, entity.PrivacyAudit{PlayerID: "p-1", Actor: "lab"})
+	require.NoError(t, err)
+	require.Equal(t, "p-1", *exports.filters[0].PlayerID)
+	require.Len(t, data.Sessions, 1)
+	require.Len(t, data.Matches, 1)
+	require.Len(t, data.Moves, 1)
+	require.NotNil(t, data.Stats)
+	require.NotNil(t, data.Consents)
+	require.Equal(t, []string{"class-a"}, data.Cohorts)
+	require.Equal(t, svc.now(), data.GeneratedAt)
+
+	require.Len(t, audits.audits, 1)
+	require.Equal(t, entity.PrivacyExport, audits.audits[0].Action)
+	require.Equal(t, entity.PrivacyReasonAdminRequest, audits.audits[0].Reason)
+	require.Equal(t, 1, audits.audits[0].SessionsAffected)
+}
+
+func TestPrivacyServiceDeleteAndAnonymize(t *testing.T) {
+	ctx := context.Background()
+	audits := &stubPrivacyRepo{}
+	svc := NewPrivacyService(audits, &stubPlayerExportRepo{}, &stubCohortRepo{}, &stubConsentRepo{})
+
+	deleted, err := svc.Delete(ctx, entity.PrivacyAudit{PlayerID: "p-1", Actor: "lab"})
+	require.NoError(t, err)
+	require.Equal(t, entity.PrivacyDelete, deleted.Action)
+	require.Equal(t, 3, deleted.SessionsAffected)
+
+	anonymized, err := svc.Anonymize(ctx, entity.PrivacyAudit{PlayerID: "p-2", Actor: "lab"})
+	require.NoError(t, err)
+	require.Equal(t, entity.PrivacyAnonymize, anonymized.Action)
+	require.Equal(t, entity.PrivacyReasonAdminRequest, anonymized.Reason)
+
+	records, err := svc.Audit(ctx, nil, 10)
+	require.NoError(t, err)
+	require.NotNil(t, records)
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/game"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+// Replay is a compact timeline of a match that clients can play back frame
+// by frame.
+type Replay struct {
+	MatchID      string     `json:"match_id"`
+	DifficultyID int        `json:"difficulty_id"`
+	Blocks       int        `json:"blocks"`
+	StartedAt    time.Time  `json:"started_at"`
+	EndedAt      *time.Time `json:"ended_at"`
+	Outcome      *string    `json:"outcome"`
+	DurationMs   int        `json:"duration_ms"`
+	Solved       bool       `json:"solved"`
+	Initial      game.Board `json:"initial"`
+	// InitialHints were shown before the first move.
+	InitialHints []ReplayHint  `json:"initial_hints"`
+	Frames       []ReplayFrame `json:"frames"`
+}
+
+// ReplayFrame is one move and the board it left behind. AtMs is the time
+// since the match started, accumulated from elapsed_ms.
+type ReplayFrame struct {
+	Seq          int        `json:"seq"`
+	AtMs         int        `json:"at_ms"`
+	ElapsedMs    int        `json:"elapsed_ms"`
+	From         int        `json:"from"`
+	To           int        `json:"to"`
+	MoveKind     int16      `json:"move_kind"`
+	FrogSide     int16      `json:"frog_side"`
+	IsCorrect    bool       `json:"is_correct"`
+	Interruption bool       `json:"interruption"`
+	Board        game.Board `json:"board"`
+	// Hints were shown on Board, before the next move.
+	Hints []ReplayHint `json:"hints"`
+}
+
+// ReplayHint is a hint as drawn over the board it was shown on.
+type ReplayHint struct {
+	ID        string `json:"id"`
+	HintType  string `json:"hint_type"`
+	Source    string `json:"source"`
+	FromIdx   *int   `json:"from_idx"`
+	ToIdx     *int   `json:"to_idx"`
+	Dismissed bool   `json:"dismissed"`
+	Followed  *bool  `json:"followed"`
+}
+
+type ReplayService struct {
+	matches      ports.MatchRepo
+	moves        ports.MoveRepo
+	difficulties ports.DifficultyRepo
+	hints        ports.HintRepo
+}
+
+func NewReplayService(matches ports.MatchRepo, moves ports.MoveRepo, difficulties ports.DifficultyRepo, hints ports.HintRepo) *ReplayService {
+	return &ReplayService{matches: matches, moves: moves, difficulties: difficulties, hints: hints}
+}
+
+func (s *ReplayService) Replay(ctx context.Context, matchID string) (Replay, error) {
+	match, err := s.matches.Get(ctx, matchID)
+	if err != nil {
+		return Replay{}, err
+	}
+	difficulty, err := s.difficulties.GetVersion(ctx, match.DifficultyID, match.DifficultyVersion)
+	if err != nil {
+		return Replay{}, err
+	}
+	moves, err := s.moves.GetByMatch(ctx, matchID)
+	if err != nil {
+		return Replay{}, err
+	}
+	hints, err := s.hints.ListByMatch(ctx, matchID)
+	if err != nil {
+		return Replay{}, err
+	}
+	initial, err := game.FromLayout(difficulty.Layout, difficulty.NumberOfBlocks)
+	if err != nil {
+		return Replay{}, err
+	}
+	// A hint's seq is the last move before it was shown, so it belongs to
+	// the frame of that move, or to the initial board for seq 0.
+	bySeq := make(map[int][]ReplayHint)
+	for _, h := range hints {
+		bySeq[h.Seq] = append(bySeq[h.Seq], replayHint(h))
+	}
+
+	replay := Replay{
+		MatchID:      match.ID,
+		DifficultyID: match.DifficultyID,
+		Blocks:       difficulty.NumberOfBlocks,
+		StartedAt:    match.StartedAt,
+		EndedAt:      match.EndedAt,
+		Outcome:      match.Outcome,
+		Initial:      initial,
+		InitialHints: nonNil(bySeq[0]),
+		Frames:       make([]ReplayFrame, 0, len(moves)),
+	}
+	board := newMatchBoard(initial)
+	for _, mv := range moves {
+		board.apply(mv)
+		replay.DurationMs += mv.ElapsedMs
+		replay.Frames = append(replay.Frames, ReplayFrame{
+			Seq:          mv.Seq,
+			AtMs:         replay.DurationMs,
+			ElapsedMs:    mv.ElapsedMs,
+			From:         mv.FromIdx,
+			To:           mv.ToIdx,
+			MoveKind:     mv.MoveKind,
+			FrogSide:     mv.FrogSide,
+			IsCorrect:    mv.IsCorrect,
+			Interruption: mv.Interruption,
+			Board:        board.current(),
+			Hints:        nonNil(bySeq[mv.Seq]),
+		})
+	}
+	replay.Solved = board.current().Solved()
+	return replay, nil
+}
+
+func replayHint(h entity.Hint) ReplayHint {
+	return ReplayHint{
+		ID:        h.ID,
+		HintType:  h.HintType,
+		Source:    h.Source,
+		FromIdx:   h.FromIdx,
+		ToIdx:     h.ToIdx,
+		Dismissed: h.Dismissed,
+		Followed:  h.Followed,
+	}
+}
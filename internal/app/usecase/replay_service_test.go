@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/game"
+)
+
+type stubMatchRepo struct {
+	matches map[string]entity.Match
+}
+
+func (r stubMatchRepo) Create(ctx context.Context, match entity.Match) (entity.Match, error) {
+	return match, nil
+}
+
+func (r stubMatchRepo) Get(ctx context.Context, id string) (entity.Match, error) {
+	match, ok := r.matches[id]
+	if !ok {
+		return entity.Match{}, pgx.ErrNoRows
+	}
+	return match, nil
+}
+
+func (r stubMatchRepo) Update(ctx context.Context, match entity.Match) (entity.Match, error) {
+	return match, nil
+}
+
+func (r stubMatchRepo) Finish(ctx context.Context, match entity.Match) (entity.Match, error) {
+	return match, nil
+}
+
+func (r stubMatchRepo) GetActiveBySession(ctx context.Context, sessionID string) (entity.Match, error) {
+	return entity.Match{}, pgx.ErrNoRows
+}
+
+func (r stubMatchRepo) ListBySession(ctx context.Context, sessionID string) ([]entity.Match, error) {
+	var matches []entity.Match
+	for _, m := range r.matches {
+		if m.SessionID == sessionID {
+			matches = append(matches, m)
+		}
+	}
+	slices.SortFunc(matches, func(a, b entity.Match) int { return a.StartedAt.Compare(b.StartedAt) })
+	return matches, nil
+}
+
+type stubMoveRepo struct {
+	moves []entity.Move
+}
+
+func (r stubMoveRepo) Create(ctx context.Context, move entity.Move) (entity.Move, error) {
+	return move, nil
+}
+
+func (r stubMoveRepo) GetByMatch(ctx context.Context, matchID string) ([]entity.Move, error) {
+	return r.moves, nil
+}
+
+func (r stubMoveRepo) GetLastByMatch(ctx context.Context, matchID string) (entity.Move, error) {
+	if len(r.moves) == 0 {
+		return entity.Move{}, pgx.ErrNoRows
+	}
+	return r.moves[len(r.moves)-1], nil
+}
+
+func (r stubMoveRepo) GetBySeq(ctx context.Context, matchID string, seq int) (entity.Move, error) {
+	for _, mv := range r.moves {
+		if mv.Seq == seq {
+			return mv, nil
+		}
+	}
+	return entity.Move{}, pgx.ErrNoRows
+}
+
+type stubDifficultyRepo struct {
+	difficulties []entity.Difficulty
+	versions     []entity.Difficulty
+}
+
+func (r stubDifficultyRepo) GetByID(ctx context.Context, id int) (entity.Difficulty, error) {
+	for _, d := range r.difficulties {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return entity.Difficulty{}, pgx.ErrNoRows
+}
+
+func (r stubDifficultyRepo) GetAll(ctx context.Context) ([]entity.Difficulty, error) {
+	return r.difficulties, nil
+}
+
+// GetVersion ignores the version unless versions are given.
+func (r stubDifficultyRepo) GetVersion(ctx context.Context, id, version int) (entity.Difficulty, error) {
+	if len(r.versions) == 0 {
+		return r.GetByID(ctx, id)
+	}
+	for _, d := range r.versions {
+		if d.ID == id && d.Version == version {
+			return d, nil
+		}
+	}
+	return entity.Difficulty{}, pgx.ErrNoRows
+}
+
+func (r stubDifficultyRepo) ListVersions(ctx context.Context, id int) ([]entity.Difficulty, error) {
+	var versions []entity.Difficulty
+	for _, d := range r.versions {
+		if d.ID == id {
+			versions = append(versions, d)
+		}
+	}
+	return versions, nil
+}
+
+func (r stubDifficultyRepo) Create(ctx context.Context, difficulty entity.Difficulty) (entity.Difficulty, error) {
+	difficulty.ID = len(r.difficulties) + 1
+	difficulty.Version = 1
+	return difficulty, nil
+}
+
+func (r stubDifficultyRepo) Update(ctx context.Context, difficulty entity.Difficulty) (entity.Difficulty, error) {
+	current, err := r.GetByID(ctx, difficulty.ID)
+	if err != nil {
+		return entity.Difficulty{}, err
+	}
+	difficulty.Version = current.Version + 1
+	return difficulty, nil
+}
+
+func (r stubDifficultyRepo) Retire(ctx context.Context, id int) (entity.Difficulty, error) {
+	difficulty, err := r.GetByID(ctx, id)
+	if err != nil {
+		return entity.Difficulty{}, err
+	}
+	now := time.Now()
+	difficulty.RetiredAt = &now
+	return difficulty, nil
+}
+
+func TestReplayServiceReplay(t *testing.T) {
+	hintFrom := 1
+	started := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc := NewReplayService(
+		stubMatchRepo{matches: map[string]entity.Match{"m-1": {ID: "m-1", DifficultyID: 1, StartedAt: started}}},
+		stubMoveRepo{moves: []entity.Move{
+			{Seq: 1, ElapsedMs: 1200, FromIdx: 0, ToIdx: 1, IsCorrect: true},
+			// Recorded boards win over replaying the move.
+			{Seq: 2, ElapsedMs: 800, FromIdx: 2, ToIdx: 0, BoardAfter: json.RawMessage(`[2,1,0]`), Interruption: true},
+			// Impossible moves leave the board unchanged.
+			{Seq: 3, ElapsedMs: 500, FromIdx: 2, ToIdx: 1, IsCorrect: false},
+		}},
+		stubDifficultyRepo{difficulties: []entity.Difficulty{{ID: 1, Name: "tiny", NumberOfBlocks: 3}}},
+		&stubHintRepo{created: []entity.Hint{
+			{ID: "h-1", MatchID: "m-1", Seq: 0, HintType: entity.HintTypeStrategy, Source: entity.HintSourcePlayer},
+			{ID: "h-2", MatchID: "m-1", Seq: 2, HintType: entity.HintTypeFrog, Source: entity.HintSourceAgent, FromIdx: &hintFrom},
+			{ID: "other", MatchID: "m-2", Seq: 1, HintType: entity.HintTypeStrategy},
+		}},
+	)
+
+	replay, err := svc.Replay(context.Background(), "m-1")
+	require.NoError(t, err)
+	require.Equal(t, 3, replay.Blocks)
+	require.Equal(t, game.Board{game.Left, game.Empty, game.Right}, replay.Initial)
+	require.Equal(t, 2500, replay.DurationMs)
+	require.False(t, replay.Solved)
+
+	require.Len(t, replay.Frames, 3)
+	require.Equal(t, game.Board{game.Empty, game.Left, game.Right}, replay.Frames[0].Board)
+	require.Equal(t, 1200, replay.Frames[0].AtMs)
+	require.Equal(t, game.Board{game.Right, game.Left, game.Empty}, replay.Frames[1].Board)
+	require.True(t, replay.Frames[1].Interruption)
+	require.Equal(t, replay.Frames[1].Board, replay.Frames[2].Board)
+	require.Equal(t, 2500, replay.Frames[2].AtMs)
+
+	// Hints land on the board they were shown on.
+	require.Len(t, replay.InitialHints, 1)
+	require.Equal(t, "h-1", replay.InitialHints[0].ID)
+	require.Empty(t, replay.Frames[0].Hints)
+	require.Len(t, replay.Frames[1].Hints, 1)
+	require.Equal(t, "h-2", replay.Frames[1].Hints[0].ID)
+	require.Equal(t, &hintFrom, replay.Frames[1].Hints[0].FromIdx)
+	require.Empty(t, replay.Frames[2].Hints)
+
+	_, err = svc.Replay(context.Background(), "missing")
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+const maxCohortLength = 64
+
+var (
+	ErrInvalidCohort      = errors.New("invalid cohort")
+	ErrInvalidReportRange = errors.New("report range must end after it starts")
+	ErrMissingExperiment  = errors.New("experiment is required")
+)
+
+// ReportService builds aggregate reports over finished matches for
+// researchers comparing difficulties and groups of players.
+type ReportService struct {
+	reports ports.ReportRepo
+	cohorts ports.CohortRepo
+}
+
+func NewReportService(reports ports.ReportRepo, cohorts ports.CohortRepo) *ReportService {
+	return &ReportService{reports: reports, cohorts: cohorts}
+}
+
+func (s *ReportService) Difficulties(ctx context.Context, filter entity.ReportFilter) ([]entity.DifficultyReport, error) {
+	if err := validateReportFilter(filter); err != nil {
+		return nil, err
+	}
+	reports, err := s.reports.DifficultyReport(ctx, filter)
+	return nonNil(reports), err
+}
+
+func (s *ReportService) Cohorts(ctx context.Context, filter entity.ReportFilter) ([]entity.CohortReport, error) {
+	if err := validateReportFilter(filter); err != nil {
+		return nil, err
+	}
+	reports, err := s.reports.CohortReport(ctx, filter)
+	return nonNil(reports), err
+}
+
+// Arms compares the arms of filter.Experiment.
+func (s *ReportService) Arms(ctx context.Context, filter entity.ReportFilter) ([]entity.ArmReport, error) {
+	if filter.Experiment == nil {
+		return nil, ErrMissingExperiment
+	}
+	if err := validateReportFilter(filter); err != nil {
+		return nil, err
+	}
+	reports, err := s.reports.ArmReport(ctx, filter)
+	return nonNil(reports), err
+}
+
+// SetPlayerCohorts replaces the cohorts of a player. Names are trimmed and
+// duplicates dropped; an empty list removes the player from every cohort.
+func (s *ReportService) SetPlayerCohorts(ctx context.Context, playerID string, cohorts []string) ([]string, error) {
+	clean := make([]string, 0, len(cohorts))
+	for _, c := range cohorts {
+		c = strings.TrimSpace(c)
+		if c == "" || len(c) > maxCohortLength {
+			return nil, fmt.Errorf("%w: names must be 1 to %d characters", ErrInvalidCohort, maxCohortLength)
+		}
+		clean = append(clean, c)
+	}
+	slices.Sort(clean)
+	clean = slices.Compact(clean)
+
+	if err := s.cohorts.SetPlayerCohorts(ctx, playerID, clean); err != nil {
+		return nil, err
+	}
+	return clean, nil
+}
+
+func (s *ReportService) PlayerCohorts(ctx context.Context, playerID string) ([]string, error) {
+	cohorts, err := s.cohorts.PlayerCohorts(ctx, playerID)
+	return nonNil(cohorts), err
+}
+
+func validateReportFilter(filter entity.ReportFilter) error {
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return ErrInvalidReportRange
+	}
+	if filter.Arm != nil && filter.Experiment == nil {
+		return ErrMissingExperiment
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+type stubCohortRepo struct {
+	set map[string][]string
+}
+
+func (r *stubCohortRepo) SetPlayerCohorts(ctx context.Context, playerID string, cohorts []string) error {
+	if r.set == nil {
+		r.set = make(map[string][]string)
+	}
+	r.set[playerID] = cohorts
+	return nil
+}
+
+func (r *stubCohortRepo) PlayerCohorts(ctx context.Context, playerID string) ([]string, error) {
+	return r.set[playerID], nil
+}
+
+type stubReportRepo struct{}
+
+func (stubReportRepo) DifficultyReport(ctx context.Context, filter entity.ReportFilter) ([]entity.DifficultyReport, error) {
+	return nil, nil
+}
+
+func (stubReportRepo) CohortReport(ctx context.Context, filter entity.ReportFilter) ([]entity.CohortReport, error) {
+	return nil, nil
+}
+
+func (stubReportRepo) ArmReport(ctx context.Context, filter entity.ReportFilter) ([]entity.ArmReport, error) {
+	return nil, nil
+}
+
+func TestReportServiceSetPlayerCohorts(t *testing.T) {
+	cohorts := &stubCohortRepo{}
+	svc := NewReportService(stubReportRepo{}, cohorts)
+
+	got, err := svc.SetPlayerCohorts(context.Background(), "p-1", []string{" class-b", "age-8-10", "class-b"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"age-8-10", "class-b"}, got)
+	require.Equal(t, got, cohorts.set["p-1"])
+
+	_, err = svc.SetPlayerCohorts(context.Background(), "p-1", []string{"  "})
+	require.ErrorIs(t, err, ErrInvalidCohort)
+}
+
+func TestReportServiceRejectsEmptyRange(t *testing.T) {
+	svc := NewReportService(stubReportRepo{}, &stubCohortRepo{})
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := svc.Difficulties(context.Background(), entity.ReportFilter{From: &day, To: &day})
+	require.ErrorIs(t, err, ErrInvalidReportRange)
+
+	reports, err := svc.Cohorts(context.Background(), entity.ReportFilter{})
+	require.NoError(t, err)
+	require.Empty(t, reports)
+	require.NotNil(t, reports)
+}
+
+func TestReportServiceArmsNeedsExperiment(t *testing.T) {
+	svc := NewReportService(stubReportRepo{}, &stubCohortRepo{})
+	arm := "agent"
+
+	_, err := svc.Arms(context.Background(), entity.ReportFilter{})
+	require.ErrorIs(t, err, ErrMissingExperiment)
+	_, err = svc.Cohorts(context.Background(), entity.ReportFilter{Arm: &arm})
+	require.ErrorIs(t, err, ErrMissingExperiment)
+
+	experiment := "hints"
+	reports, err := svc.Arms(context.Background(), entity.ReportFilter{Experiment: &experiment, Arm: &arm})
+	require.NoError(t, err)
+	require.NotNil(t, reports)
+}
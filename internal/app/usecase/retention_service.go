@@ -0,0 +1,289 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+var (
+	ErrMatchStillActive = errors.New("match is still active")
+	ErrNothingToArchive = errors.New("match has no moves to archive")
+)
+
+// RetentionService moves the moves of old matches out of the database into
+// gzip compressed JSON Lines objects, one per match, and brings them back on
+// demand. match_stats is left untouched, so reports still cover archived
+// matches.
+type RetentionService struct {
+	archives ports.ArchiveRepo
+	moves    ports.MoveRepo
+	matches  ports.MatchRepo
+	store    ports.ObjectStore
+
+	// MoveRetention is how long the moves of a finished match stay in the
+	// database. Zero disables archiving; restores and the cleanup of existing
+	// archives keep working.
+	MoveRetention time.Duration
+	BatchSize     int
+	PollInterval  time.Duration
+
+	now func() time.Time
+}
+
+func NewRetentionService(archives ports.ArchiveRepo, moves ports.MoveRepo, matches ports.MatchRepo, store ports.ObjectStore) *RetentionService {
+	return &RetentionService{
+		archives:     archives,
+		moves:        moves,
+		matches:      matches,
+		store:        store,
+		BatchSize:    100,
+		PollInterval: time.Hour,
+		now:          time.Now,
+	}
+}
+
+// Run removes the archives of deleted matches and clears the boards of
+// anonymized ones every PollInterval until ctx is done. It also archives due
+// matches when MoveRetention is set; archives made by hand or before
+// archiving was disabled are still cleaned up without it.
+func (s *RetentionService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if s.MoveRetention > 0 {
+			if _, err := s.ArchiveDue(ctx); err != nil && ctx.Err() == nil {
+				slog.WarnContext(ctx, "archive round failed", "error", err)
+			}
+		}
+		if _, err := s.PurgeOrphaned(ctx); err != nil && ctx.Err() == nil {
+			slog.WarnContext(ctx, "archive purge failed", "error", err)
+		}
+		if _, err := s.ClearBoards(ctx); err != nil && ctx.Err() == nil {
+			slog.WarnContext(ctx, "archive board clearing failed", "error", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// ArchiveDue archives one batch of matches older than MoveRetention and
+// returns how many were archived.
+func (s *RetentionService) ArchiveDue(ctx context.Context) (int, error) {
+	cutoff := s.now().Add(-s.MoveRetention)
+	due, err := s.archives.ListArchivable(ctx, cutoff, s.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	archived := 0
+	for _, matchID := range due {
+		_, err := s.Archive(ctx, matchID)
+		switch {
+		case err == nil:
+			archived++
+		case errors.Is(err, ErrNothingToArchive):
+		default:
+			return archived, fmt.Errorf("archive match %s: %w", matchID, err)
+		}
+	}
+	return archived, nil
+}
+
+// Archive writes the moves of a finished match to the object store and then
+// deletes them from the database. A failure in between leaves the moves in
+// place, and the object is overwritten by the next attempt.
+func (s *RetentionService) Archive(ctx context.Context, matchID string) (entity.MatchArchive, error) {
+	match, err := s.matches.Get(ctx, matchID)
+	if err != nil {
+		return entity.MatchArchive{}, err
+	}
+	if match.IsActive {
+		return entity.MatchArchive{}, ErrMatchStillActive
+	}
+	moves, err := s.moves.GetByMatch(ctx, matchID)
+	if err != nil {
+		return entity.MatchArchive{}, err
+	}
+	if len(moves) == 0 {
+		return entity.MatchArchive{}, ErrNothingToArchive
+	}
+
+	body, err := encodeMoves(moves)
+	if err != nil {
+		return entity.MatchArchive{}, err
+	}
+	archive := entity.MatchArchive{
+		MatchID:   matchID,
+		ObjectKey: "moves/" + matchID + ".jsonl.gz",
+		Moves:     len(moves),
+		LastSeq:   moves[len(moves)-1].Seq,
+	}
+	if err := s.store.Put(ctx, archive.ObjectKey, bytes.NewReader(body)); err != nil {
+		return entity.MatchArchive{}, err
+	}
+	deleted, err := s.archives.Archive(ctx, archive)
+	if err != nil {
+		return entity.MatchArchive{}, err
+	}
+	archive.ArchivedAt = s.now().UTC()
+	slog.InfoContext(ctx, "match moves archived",
+		"match_id", matchID,
+		"moves", deleted,
+		"bytes", len(body),
+	)
+	return archive, nil
+}
+
+// Restore puts the archived moves of a match back in the database, e.g. to
+// replay it. It returns pgx.ErrNoRows when the match is not archived.
+func (s *RetentionService) Restore(ctx context.Context, matchID string) (entity.MatchArchive, error) {
+	archive, err := s.archives.Get(ctx, matchID)
+	if err != nil {
+		return entity.MatchArchive{}, err
+	}
+	r, err := s.store.Get(ctx, archive.ObjectKey)
+	if err != nil {
+		return entity.MatchArchive{}, err
+	}
+	moves, err := decodeMoves(r)
+	r.Close()
+	if err != nil {
+		return entity.MatchArchive{}, fmt.Errorf("read archive %s: %w", archive.ObjectKey, err)
+	}
+	restored, err := s.archives.Restore(ctx, matchID, moves)
+	if err != nil {
+		return entity.MatchArchive{}, err
+	}
+	now := s.now().UTC()
+	archive.RestoredAt = &now
+	slog.InfoContext(ctx, "match moves restored", "match_id", matchID, "moves", restored)
+	return archive, nil
+}
+
+// Archived reports whether the moves of a match are in the archive.
+func (s *RetentionService) Archived(ctx context.Context, matchID string) (bool, error) {
+	_, err := s.archives.Get(ctx, matchID)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, pgx.ErrNoRows):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// PurgeOrphaned deletes the archives of matches that no longer exist, e.g.
+// after a player's data was deleted, and returns how many were removed.
+func (s *RetentionService) PurgeOrphaned(ctx context.Context) (int, error) {
+	orphaned, err := s.archives.ListOrphaned(ctx, s.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for i, archive := range orphaned {
+		if err := s.store.Delete(ctx, archive.ObjectKey); err != nil {
+			return i, err
+		}
+		if err := s.archives.Delete(ctx, archive.MatchID); err != nil {
+			return i, err
+		}
+	}
+	return len(orphaned), nil
+}
+
+// ClearBoards rewrites the archives of matches whose boards were cleared in
+// the database, when a player withdrew consent and was anonymized, without
+// the boards. It returns how many were rewritten.
+func (s *RetentionService) ClearBoards(ctx context.Context) (int, error) {
+	pending, err := s.archives.ListBoardsToClear(ctx, s.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for i, archive := range pending {
+		if err := s.clearBoards(ctx, archive); err != nil {
+			return i, fmt.Errorf("clear boards of %s: %w", archive.ObjectKey, err)
+		}
+	}
+	return len(pending), nil
+}
+
+func (s *RetentionService) clearBoards(ctx context.Context, archive entity.MatchArchive) error {
+	r, err := s.store.Get(ctx, archive.ObjectKey)
+	if err != nil {
+		return err
+	}
+	moves, err := decodeMoves(r)
+	r.Close()
+	if err != nil {
+		return err
+	}
+	for i := range moves {
+		moves[i].BoardBefore, moves[i].BoardAfter = nil, nil
+	}
+	body, err := encodeMoves(moves)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Put(ctx, archive.ObjectKey, bytes.NewReader(body)); err != nil {
+		return err
+	}
+	return s.archives.MarkBoardsCleared(ctx, archive.MatchID)
+}
+
+// encodeMoves writes one JSON object per move and line, gzip compressed.
+func encodeMoves(moves []entity.Move) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(zw)
+	for _, mv := range moves {
+		if err := enc.Encode(mv); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeMoves(r io.Reader) ([]entity.Move, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var moves []entity.Move
+	scanner := bufio.NewScanner(zr)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var mv entity.Move
+		if err := json.Unmarshal(scanner.Bytes(), &mv); err != nil {
+			return nil, err
+		}
+		moves = append(moves, mv)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return moves, nil
+}
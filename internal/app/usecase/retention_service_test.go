@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+type memoryObjectStore struct {
+	objects map[string][]byte
+}
+
+func (s *memoryObjectStore) Put(ctx context.Context, key string, body io.Reader) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	s.objects[key] = data
+	return nil
+}
+
+func (s *memoryObjectStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memoryObjectStore) Delete(ctx context.Context, key string) error {
+	delete(s.objects, key)
+	return nil
+}
+
+type stubArchiveRepo struct {
+	archives map[string]entity.MatchArchive
+	restored []entity.Move
+	orphaned []entity.MatchArchive
+	boards   []entity.MatchArchive
+	cleared  []string
+	cutoff   time.Time
+}
+
+func (r *stubArchiveRepo) ListArchivable(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	r.cutoff = before
+	return []string{"m-1"}, nil
+}
+
+func (r *stubArchiveRepo) Archive(ctx context.Context, archive entity.MatchArchive) (int, error) {
+	r.archives[archive.MatchID] = archive
+	return archive.Moves, nil
+}
+
+func (r *stubArchiveRepo) Get(ctx context.Context, matchID string) (entity.MatchArchive, error) {
+	archive, ok := r.archives[matchID]
+	if !ok || archive.RestoredAt != nil {
+		return entity.MatchArchive{}, pgx.ErrNoRows
+	}
+	return archive, nil
+}
+
+func (r *stubArchiveRepo) Restore(ctx context.Context, matchID string, moves []entity.Move) (int, error) {
+	r.restored = moves
+	return len(moves), nil
+}
+
+func (r *stubArchiveRepo) ListOrphaned(ctx context.Context, limit int) ([]entity.MatchArchive, error) {
+	return r.orphaned, nil
+}
+
+func (r *stubArchiveRepo) Delete(ctx context.Context, matchID string) error {
+	delete(r.archives, matchID)
+	return nil
+}
+
+func (r *stubArchiveRepo) ListBoardsToClear(ctx context.Context, limit int) ([]entity.MatchArchive, error) {
+	return r.boards, nil
+}
+
+func (r *stubArchiveRepo) MarkBoardsCleared(ctx context.Context, matchID string) error {
+	r.cleared = append(r.cleared, matchID)
+	return nil
+}
+
+func newRetentionFixture(active bool) (*RetentionService, *stubArchiveRepo, *memoryObjectStore) {
+	start := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	matches := stubMatchRepo{matches: map[string]entity.Match{
+		"m-1": {ID: "m-1", IsActive: active, StartedAt: start},
+	}}
+	moves := stubMoveRepo{moves: []entity.Move{
+		{ID: "mv-1", MatchID: "m-1", Seq: 1, OccurredAt: start, FromIdx: 2, ToIdx: 3, MoveKind: entity.MoveKindStep, IsCorrect: true, BoardBefore: json.RawMessage(`[1,1,0,2,2]`), BoardAfter: json.RawMessage(`[1,0,1,2,2]`)},
+		{ID: "mv-2", MatchID: "m-1", Seq: 2, OccurredAt: start.Add(time.Second), ElapsedMs: 1000, FromIdx: 3, ToIdx: 1, MoveKind: entity.MoveKindJump},
+	}}
+	archives := &stubArchiveRepo{archives: map[string]entity.MatchArchive{}}
+	store := &memoryObjectStore{objects: map[string][]byte{}}
+	svc := NewRetentionService(archives, moves, matches, store)
+	svc.now = func() time.Time { return time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC) }
+	return svc, archives, store
+}
+
+func TestRetentionServiceArchiveWritesGzipJSONLines(t *testing.T) {
+	svc, archives, store := newRetentionFixture(false)
+
+	archive, err := svc.Archive(context.Background(), "m-1")
+	require.NoError(t, err)
+	require.Equal(t, "moves/m-1.jsonl.gz", archive.ObjectKey)
+	require.Equal(t, 2, archive.Moves)
+	require.Equal(t, 2, archive.LastSeq)
+	require.Contains(t, archives.archives, "m-1")
+
+	zr, err := gzip.NewReader(bytes.NewReader(store.objects[archive.ObjectKey]))
+	require.NoError(t, err)
+	raw, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	require.Len(t, lines, 2)
+	var first entity.Move
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	require.Equal(t, "mv-1", first.ID)
+}
+
+func TestRetentionServiceRestoreRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	svc, archives, _ := newRetentionFixture(false)
+
+	_, err := svc.Archive(ctx, "m-1")
+	require.NoError(t, err)
+	archived, err := svc.Archived(ctx, "m-1")
+	require.NoError(t, err)
+	require.True(t, archived)
+
+	restored, err := svc.Restore(ctx, "m-1")
+	require.NoError(t, err)
+	require.NotNil(t, restored.RestoredAt)
+	require.Len(t, archives.restored, 2)
+	require.Equal(t, "mv-2", archives.restored[1].ID)
+	require.Equal(t, entity.MoveKindJump, archives.restored[1].MoveKind)
+	require.JSONEq(t, `[1,0,1,2,2]`, string(archives.restored[0].BoardAfter))
+	require.True(t, archives.restored[1].OccurredAt.Equal(time.Date(2025, 1, 10, 9, 0, 1, 0, time.UTC)))
+
+	_, err = svc.Restore(ctx, "m-2")
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestRetentionServiceRejectsActiveMatch(t *testing.T) {
+	svc, archives, _ := newRetentionFixture(true)
+
+	_, err := svc.Archive(context.Background(), "m-1")
+	require.ErrorIs(t, err, ErrMatchStillActive)
+	require.Empty(t, archives.archives)
+}
+
+func TestRetentionServiceArchiveDueUsesCutoff(t *testing.T) {
+	svc, archives, _ := newRetentionFixture(false)
+	svc.MoveRetention = 90 * 24 * time.Hour
+
+	n, err := svc.ArchiveDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), archives.cutoff)
+}
+
+func TestRetentionServicePurgeOrphaned(t *testing.T) {
+	ctx := context.Background()
+	svc, archives, store := newRetentionFixture(false)
+	_, err := svc.Archive(ctx, "m-1")
+	require.NoError(t, err)
+	archives.orphaned = []entity.MatchArchive{archives.archives["m-1"]}
+
+	n, err := svc.PurgeOrphaned(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Empty(t, store.objects)
+	require.Empty(t, archives.archives)
+}
+
+func TestRetentionServiceClearBoards(t *testing.T) {
+	ctx := context.Background()
+	svc, archives, store := newRetentionFixture(false)
+	archive, err := svc.Archive(ctx, "m-1")
+	require.NoError(t, err)
+	archives.boards = []entity.MatchArchive{archive}
+
+	n, err := svc.ClearBoards(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, []string{"m-1"}, archives.cleared)
+
+	moves, err := decodeMoves(bytes.NewReader(store.objects[archive.ObjectKey]))
+	require.NoError(t, err)
+	require.Len(t, moves, 2)
+	for _, mv := range moves {
+		require.JSONEq(t, "null", string(mv.BoardBefore))
+		require.JSONEq(t, "null", string(mv.BoardAfter))
+	}
+	require.Equal(t, 3, moves[0].ToIdx, "only the boards are cleared")
+}
+
+func TestRetentionServiceRunPurgesWithoutArchiving(t *testing.T) {
+	svc, archives, store := newRetentionFixture(false)
+	store.objects["moves/m-9.jsonl.gz"] = []byte("x")
+	archives.orphaned = []entity.MatchArchive{{MatchID: "m-9", ObjectKey: "moves/m-9.jsonl.gz"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	svc.Run(ctx)
+
+	require.Empty(t, store.objects, "orphaned archives are purged")
+	require.True(t, archives.cutoff.IsZero(), "nothing is archived without MoveRetention")
+}
@@ -2,12 +2,16 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"log/slog"
 	"time"
 
 	"github.com/org/ranas-bdi-backend/internal/domain/entity"
 	"github.com/org/ranas-bdi-backend/internal/domain/ports"
 )
 
+var ErrSessionFinished = errors.New("session is already finished")
+
 type SessionService struct {
 	repo ports.SessionRepo
 }
@@ -16,9 +20,12 @@ func NewSessionService(repo ports.SessionRepo) *SessionService {
 	return &SessionService{repo: repo}
 }
 
-func (s *SessionService) Create(ctx context.Context, playerID, device string) (entity.Session, error) {
+// Create starts a session. curriculumID is optional; without it the session
+// follows the default curriculum.
+func (s *SessionService) Create(ctx context.Context, playerID, device string, curriculumID *int) (entity.Session, error) {
 	session := entity.Session{
-		IsFinished: false,
+		CurriculumID: curriculumID,
+		IsFinished:   false,
 	}
 	if playerID != "" {
 		session.PlayerID = &playerID
@@ -26,20 +33,35 @@ func (s *SessionService) Create(ctx context.Context, playerID, device string) (e
 	if device != "" {
 		session.Device = &device
 	}
-	return s.repo.Create(ctx, session)
+	created, err := s.repo.Create(ctx, session)
+	if err != nil {
+		return entity.Session{}, err
+	}
+	slog.InfoContext(ctx, "session created", "session_id", created.ID, "player_id", playerID, "device", device)
+	return created, nil
 }
 
 func (s *SessionService) Get(ctx context.Context, id string) (entity.Session, error) {
 	return s.repo.Get(ctx, id)
 }
 
+// Finish closes a session. The SessionFinished event it records is what
+// webhooks and the xAPI export pick up.
 func (s *SessionService) Finish(ctx context.Context, id string) (entity.Session, error) {
 	session, err := s.repo.Get(ctx, id)
 	if err != nil {
 		return entity.Session{}, err
 	}
+	if session.IsFinished {
+		return entity.Session{}, ErrSessionFinished
+	}
 	now := time.Now().UTC()
 	session.IsFinished = true
 	session.EndedAt = &now
-	return s.repo.Update(ctx, session)
+	updated, err := s.repo.Update(ctx, session)
+	if err != nil {
+		return entity.Session{}, err
+	}
+	slog.InfoContext(ctx, "session finished", "session_id", updated.ID)
+	return updated, nil
 }
@@ -50,7 +50,7 @@ func TestSessionServiceCreate(t *testing.T) {
 	}
 
 	svc := NewSessionService(repo)
-	session, err := svc.Create(ctx, "player-id", "Meta Quest 3")
+	session, err := svc.Create(ctx, "player-id", "Meta Quest 3", nil)
 	require.NoError(t, err)
 	require.Equal(t, "session-id", session.ID)
 	require.False(t, session.IsFinished)
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+// WebhookEventTypes are the outbox events webhooks may subscribe to.
+var WebhookEventTypes = []string{entity.EventMatchFinished, entity.EventSessionFinished}
+
+var ErrInvalidWebhook = errors.New("invalid webhook")
+
+// WebhookService manages webhook registrations and delivers the events they
+// subscribe to. Each webhook gets its own delivery row, so a failing receiver
+// is retried on its own without re-sending to the others.
+type WebhookService struct {
+	repo   ports.WebhookRepo
+	sender ports.WebhookSender
+
+	BatchSize    int
+	PollInterval time.Duration
+	Lease        time.Duration
+	MinBackoff   time.Duration
+	MaxBackoff   time.Duration
+	MaxAttempts  int
+
+	now func() time.Time
+}
+
+func NewWebhookService(repo ports.WebhookRepo, sender ports.WebhookSender) *WebhookService {
+	return &WebhookService{
+		repo:         repo,
+		sender:       sender,
+		BatchSize:    50,
+		PollInterval: 2 * time.Second,
+		Lease:        time.Minute,
+		MinBackoff:   10 * time.Second,
+		MaxBackoff:   time.Hour,
+		MaxAttempts:  10,
+		now:          time.Now,
+	}
+}
+
+// Register validates and stores a webhook with a freshly generated secret.
+func (s *WebhookService) Register(ctx context.Context, rawURL string, eventTypes []string) (entity.Webhook, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return entity.Webhook{}, fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidWebhook)
+	}
+	if len(eventTypes) == 0 {
+		return entity.Webhook{}, fmt.Errorf("%w: at least one event type is required", ErrInvalidWebhook)
+	}
+	for _, t := range eventTypes {
+		if !slices.Contains(WebhookEventTypes, t) {
+			return entity.Webhook{}, fmt.Errorf("%w: unsupported event type %q", ErrInvalidWebhook, t)
+		}
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return entity.Webhook{}, err
+	}
+	created, err := s.repo.Create(ctx, entity.Webhook{
+		URL:        u.String(),
+		Secret:     secret,
+		EventTypes: slices.Compact(slices.Sorted(slices.Values(eventTypes))),
+		IsActive:   true,
+	})
+	if err != nil {
+		return entity.Webhook{}, err
+	}
+	slog.InfoContext(ctx, "webhook registered", "webhook_id", created.ID, "url", created.URL)
+	return created, nil
+}
+
+func (s *WebhookService) List(ctx context.Context) ([]entity.Webhook, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *WebhookService) Deactivate(ctx context.Context, id string) (entity.Webhook, error) {
+	return s.repo.Deactivate(ctx, id)
+}
+
+func (s *WebhookService) Deliveries(ctx context.Context, webhookID string, limit int) ([]entity.WebhookDelivery, error) {
+	if _, err := s.repo.Get(ctx, webhookID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, webhookID, limit)
+}
+
+// Enqueue is registered as an outbox handler.
+func (s *WebhookService) Enqueue(ctx context.Context, event entity.OutboxEvent) error {
+	_, err := s.repo.EnqueueDeliveries(ctx, event)
+	return err
+}
+
+func (s *WebhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			slog.WarnContext(ctx, "webhook delivery round failed", "error", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// DeliverDue sends one batch of due deliveries and returns how many were
+// attempted.
+func (s *WebhookService) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.ClaimDueDeliveries(ctx, s.BatchSize, s.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	webhooks := make(map[string]entity.Webhook)
+	for _, d := range deliveries {
+		webhook, ok := webhooks[d.WebhookID]
+		if !ok {
+			if webhook, err = s.repo.Get(ctx, d.WebhookID); err != nil {
+				return len(deliveries), err
+			}
+			webhooks[d.WebhookID] = webhook
+		}
+		if err := s.deliver(ctx, webhook, d); err != nil {
+			return len(deliveries), err
+		}
+	}
+	return len(deliveries), nil
+}
+
+func (s *WebhookService) deliver(ctx context.Context, webhook entity.Webhook, d entity.WebhookDelivery) error {
+	if !webhook.IsActive {
+		return s.repo.MarkFailed(ctx, d.ID, nil, "webhook deactivated", nil)
+	}
+
+	status, sendErr := s.sender.Send(ctx, webhook, d)
+	if sendErr == nil {
+		return s.repo.MarkDelivered(ctx, d.ID, status)
+	}
+
+	var statusCode *int
+	if status != 0 {
+		statusCode = &status
+	}
+	var retryAt *time.Time
+	if d.Attempts < s.MaxAttempts {
+		next := s.now().Add(exponentialBackoff(s.MinBackoff, s.MaxBackoff, d.Attempts))
+		retryAt = &next
+	}
+	slog.WarnContext(ctx, "webhook delivery failed",
+		"webhook_id", webhook.ID,
+		"delivery_id", d.ID,
+		"attempts", d.Attempts,
+		"status_code", status,
+		"give_up", retryAt == nil,
+		"error", sendErr,
+	)
+	return s.repo.MarkFailed(ctx, d.ID, statusCode, sendErr.Error(), retryAt)
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
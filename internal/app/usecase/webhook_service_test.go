@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+type stubWebhookRepo struct {
+	webhooks  map[string]entity.Webhook
+	due       []entity.WebhookDelivery
+	created   []entity.Webhook
+	delivered []int64
+	failed    map[int64]*time.Time
+}
+
+func (r *stubWebhookRepo) Create(ctx context.Context, webhook entity.Webhook) (entity.Webhook, error) {
+	webhook.ID = "wh-new"
+	r.created = append(r.created, webhook)
+	return webhook, nil
+}
+
+func (r *stubWebhookRepo) Get(ctx context.Context, id string) (entity.Webhook, error) {
+	return r.webhooks[id], nil
+}
+
+func (r *stubWebhookRepo) List(ctx context.Context) ([]entity.Webhook, error) {
+	return nil, nil
+}
+
+func (r *stubWebhookRepo) Deactivate(ctx context.Context, id string) (entity.Webhook, error) {
+	return entity.Webhook{}, nil
+}
+
+func (r *stubWebhookRepo) EnqueueDeliveries(ctx context.Context, event entity.OutboxEvent) (int, error) {
+	return 0, nil
+}
+
+func (r *stubWebhookRepo) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.WebhookDelivery, error) {
+	due := r.due
+	r.due = nil
+	return due, nil
+}
+
+func (r *stubWebhookRepo) MarkDelivered(ctx context.Context, id int64, statusCode int) error {
+	r.delivered = append(r.delivered, id)
+	return nil
+}
+
+func (r *stubWebhookRepo) MarkFailed(ctx context.Context, id int64, statusCode *int, cause string, retryAt *time.Time) error {
+	if r.failed == nil {
+		r.failed = make(map[int64]*time.Time)
+	}
+	r.failed[id] = retryAt
+	return nil
+}
+
+func (r *stubWebhookRepo) ListDeliveries(ctx context.Context, webhookID string, limit int) ([]entity.WebhookDelivery, error) {
+	return nil, nil
+}
+
+type stubWebhookSender struct {
+	failFor map[string]bool
+}
+
+func (s stubWebhookSender) Send(ctx context.Context, webhook entity.Webhook, delivery entity.WebhookDelivery) (int, error) {
+	if s.failFor[webhook.ID] {
+		return 503, errors.New("webhook responded 503 Service Unavailable")
+	}
+	return 200, nil
+}
+
+func TestWebhookServiceDeliverDue(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := &stubWebhookRepo{
+		webhooks: map[string]entity.Webhook{
+			"ok":       {ID: "ok", IsActive: true},
+			"down":     {ID: "down", IsActive: true},
+			"disabled": {ID: "disabled"},
+		},
+		due: []entity.WebhookDelivery{
+			{ID: 1, WebhookID: "ok", Attempts: 1},
+			{ID: 2, WebhookID: "down", Attempts: 2},
+			{ID: 3, WebhookID: "down", Attempts: 10},
+			{ID: 4, WebhookID: "disabled", Attempts: 1},
+		},
+	}
+
+	svc := NewWebhookService(repo, stubWebhookSender{failFor: map[string]bool{"down": true}})
+	svc.now = func() time.Time { return now }
+
+	n, err := svc.DeliverDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 4, n)
+
+	retryAt := now.Add(20 * time.Second)
+	require.Equal(t, []int64{1}, repo.delivered)
+	require.Equal(t, map[int64]*time.Time{2: &retryAt, 3: nil, 4: nil}, repo.failed)
+}
+
+func TestWebhookServiceRegisterValidates(t *testing.T) {
+	repo := &stubWebhookRepo{}
+	svc := NewWebhookService(repo, stubWebhookSender{})
+
+	_, err := svc.Register(context.Background(), "ftp://example.com", []string{entity.EventMatchFinished})
+	require.ErrorIs(t, err, ErrInvalidWebhook)
+
+	_, err = svc.Register(context.Background(), "https://example.com/hook", []string{entity.EventMoveRecorded})
+	require.ErrorIs(t, err, ErrInvalidWebhook)
+
+	created, err := svc.Register(context.Background(), "https://example.com/hook",
+		[]string{entity.EventSessionFinished, entity.EventMatchFinished, entity.EventMatchFinished})
+	require.NoError(t, err)
+	require.Len(t, created.Secret, 64)
+	require.Equal(t, []string{entity.EventMatchFinished, entity.EventSessionFinished}, created.EventTypes)
+	require.True(t, created.IsActive)
+}
@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+	"github.com/org/ranas-bdi-backend/internal/domain/xapi"
+)
+
+// XAPIEventTypes are the outbox events mapped to xAPI statements.
+var XAPIEventTypes = []string{
+	entity.EventSessionStarted,
+	entity.EventMatchStarted,
+	entity.EventMoveRecorded,
+	entity.EventHintRecorded,
+	entity.EventMatchFinished,
+	entity.EventSessionFinished,
+}
+
+// XAPIService maps game activity to xAPI statements, queues them and sends
+// them to the learning record store in batches.
+type XAPIService struct {
+	sessions     ports.SessionRepo
+	matches      ports.MatchRepo
+	moves        ports.MoveRepo
+	hints        ports.HintRepo
+	difficulties ports.DifficultyRepo
+	stats        ports.MatchStatsRepo
+	queue        ports.XAPIQueue
+	lrs          ports.LRSClient
+	builder      xapi.Builder
+
+	BatchSize    int
+	PollInterval time.Duration
+	Lease        time.Duration
+	MinBackoff   time.Duration
+	MaxBackoff   time.Duration
+	MaxAttempts  int
+
+	now func() time.Time
+}
+
+// NewXAPIService builds the service. lrs may be nil when no LRS is
+// configured, in which case statements can only be previewed.
+func NewXAPIService(
+	sessions ports.SessionRepo,
+	matches ports.MatchRepo,
+	moves ports.MoveRepo,
+	hints ports.HintRepo,
+	difficulties ports.DifficultyRepo,
+	stats ports.MatchStatsRepo,
+	queue ports.XAPIQueue,
+	lrs ports.LRSClient,
+	builder xapi.Builder,
+) *XAPIService {
+	return &XAPIService{
+		sessions:     sessions,
+		matches:      matches,
+		moves:        moves,
+		hints:        hints,
+		difficulties: difficulties,
+		stats:        stats,
+		queue:        queue,
+		lrs:          lrs,
+		builder:      builder,
+		BatchSize:    50,
+		PollInterval: 5 * time.Second,
+		Lease:        time.Minute,
+		MinBackoff:   30 * time.Second,
+		MaxBackoff:   6 * time.Hour,
+		MaxAttempts:  20,
+		now:          time.Now,
+	}
+}
+
+// Preview returns the statements a match produces, in the order they would
+// be sent.
+func (s *XAPIService) Preview(ctx context.Context, matchID string) ([]xapi.Statement, error) {
+	match, err := s.matches.Get(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+	session, difficulty, err := s.matchContext(ctx, match)
+	if err != nil {
+		return nil, err
+	}
+	moves, err := s.moves.GetByMatch(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+	hints, err := s.hints.ListByMatch(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hints come right after the move they followed, as they were shown.
+	statements := make([]xapi.Statement, 0, len(moves)+len(hints)+2)
+	statements = append(statements, s.builder.MatchStarted(session, match, difficulty))
+	next := 0
+	shown := func(seq int) {
+		for ; next < len(hints) && hints[next].Seq <= seq; next++ {
+			statements = append(statements, s.builder.HintShown(session, match, difficulty, hints[next]))
+		}
+	}
+	shown(0)
+	for _, mv := range moves {
+		statements = append(statements, s.builder.MoveRecorded(session, match, difficulty, mv))
+		shown(mv.Seq)
+	}
+	shown(math.MaxInt)
+	if !match.IsActive {
+		kpi, err := s.kpi(ctx, matchID)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, s.builder.MatchFinished(session, match, difficulty, kpi))
+	}
+	return statements, nil
+}
+
+// Enqueue is registered as an outbox handler.
+func (s *XAPIService) Enqueue(ctx context.Context, event entity.OutboxEvent) error {
+	statement, err := s.fromEvent(ctx, event)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(statement)
+	if err != nil {
+		return err
+	}
+	return s.queue.Enqueue(ctx, statement.ID, raw)
+}
+
+func (s *XAPIService) fromEvent(ctx context.Context, event entity.OutboxEvent) (xapi.Statement, error) {
+	switch event.Type {
+	case entity.EventSessionStarted, entity.EventSessionFinished:
+		var session entity.Session
+		if err := json.Unmarshal(event.Payload, &session); err != nil {
+			return xapi.Statement{}, err
+		}
+		if event.Type == entity.EventSessionStarted {
+			return s.builder.SessionStarted(session), nil
+		}
+		return s.builder.SessionFinished(session), nil
+
+	case entity.EventMatchStarted, entity.EventMatchFinished:
+		var match entity.Match
+		if err := json.Unmarshal(event.Payload, &match); err != nil {
+			return xapi.Statement{}, err
+		}
+		session, difficulty, err := s.matchContext(ctx, match)
+		if err != nil {
+			return xapi.Statement{}, err
+		}
+		if event.Type == entity.EventMatchStarted {
+			return s.builder.MatchStarted(session, match, difficulty), nil
+		}
+		kpi, err := s.kpi(ctx, match.ID)
+		if err != nil {
+			return xapi.Statement{}, err
+		}
+		return s.builder.MatchFinished(session, match, difficulty, kpi), nil
+
+	case entity.EventMoveRecorded:
+		var move entity.Move
+		if err := json.Unmarshal(event.Payload, &move); err != nil {
+			return xapi.Statement{}, err
+		}
+		match, err := s.matches.Get(ctx, move.MatchID)
+		if err != nil {
+			return xapi.Statement{}, err
+		}
+		session, difficulty, err := s.matchContext(ctx, match)
+		if err != nil {
+			return xapi.Statement{}, err
+		}
+		return s.builder.MoveRecorded(session, match, difficulty, move), nil
+
+	case entity.EventHintRecorded:
+		var hint entity.Hint
+		if err := json.Unmarshal(event.Payload, &hint); err != nil {
+			return xapi.Statement{}, err
+		}
+		match, err := s.matches.Get(ctx, hint.MatchID)
+		if err != nil {
+			return xapi.Statement{}, err
+		}
+		session, difficulty, err := s.matchContext(ctx, match)
+		if err != nil {
+			return xapi.Statement{}, err
+		}
+		return s.builder.HintShown(session, match, difficulty, hint), nil
+	}
+	return xapi.Statement{}, fmt.Errorf("no xAPI mapping for event type %q", event.Type)
+}
+
+func (s *XAPIService) matchContext(ctx context.Context, match entity.Match) (entity.Session, entity.Difficulty, error) {
+	session, err := s.sessions.Get(ctx, match.SessionID)
+	if err != nil {
+		return entity.Session{}, entity.Difficulty{}, err
+	}
+	difficulty, err := s.difficulties.GetVersion(ctx, match.DifficultyID, match.DifficultyVersion)
+	if err != nil {
+		return entity.Session{}, entity.Difficulty{}, err
+	}
+	return session, difficulty, nil
+}
+
+func (s *XAPIService) kpi(ctx context.Context, matchID string) (*entity.MatchKPI, error) {
+	kpi, err := s.stats.GetByMatch(ctx, matchID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &kpi, nil
+}
+
+func (s *XAPIService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Keep sending while full batches come back so a backlog drains
+		// without waiting for the ticker.
+		n, err := s.SendDue(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.WarnContext(ctx, "xapi delivery round failed", "error", err)
+		}
+		if err == nil && n == s.BatchSize {
+			continue
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// SendDue posts one batch of due statements and returns how many were
+// attempted.
+func (s *XAPIService) SendDue(ctx context.Context) (int, error) {
+	due, err := s.queue.ClaimDue(ctx, s.BatchSize, s.Lease)
+	if err != nil || len(due) == 0 {
+		return 0, err
+	}
+
+	batch := make([]json.RawMessage, len(due))
+	ids := make([]int64, len(due))
+	for i, st := range due {
+		batch[i] = st.Statement
+		ids[i] = st.ID
+	}
+
+	sendErr := s.lrs.SendStatements(ctx, batch)
+	if sendErr == nil {
+		return len(due), s.queue.MarkSent(ctx, ids)
+	}
+
+	slog.WarnContext(ctx, "xapi batch rejected", "statements", len(due), "error", sendErr)
+	for _, st := range due {
+		var retryAt *time.Time
+		if st.Attempts < s.MaxAttempts {
+			next := s.now().Add(exponentialBackoff(s.MinBackoff, s.MaxBackoff, st.Attempts))
+			retryAt = &next
+		}
+		if err := s.queue.MarkFailed(ctx, st.ID, sendErr.Error(), retryAt); err != nil {
+			return len(due), err
+		}
+	}
+	return len(due), nil
+}
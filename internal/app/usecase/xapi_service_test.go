@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/xapi"
+)
+
+type stubMatchStatsRepo struct{}
+
+func (stubMatchStatsRepo) GetByMatch(ctx context.Context, matchID string) (entity.MatchKPI, error) {
+	return entity.MatchKPI{}, pgx.ErrNoRows
+}
+
+type stubXAPIQueue struct {
+	queued map[string]json.RawMessage
+	due    []entity.XAPIStatement
+	sent   []int64
+	failed map[int64]*time.Time
+}
+
+func (q *stubXAPIQueue) Enqueue(ctx context.Context, statementID string, statement json.RawMessage) error {
+	if q.queued == nil {
+		q.queued = make(map[string]json.RawMessage)
+	}
+	q.queued[statementID] = statement
+	return nil
+}
+
+func (q *stubXAPIQueue) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entity.XAPIStatement, error) {
+	due := q.due
+	q.due = nil
+	return due, nil
+}
+
+func (q *stubXAPIQueue) MarkSent(ctx context.Context, ids []int64) error {
+	q.sent = append(q.sent, ids...)
+	return nil
+}
+
+func (q *stubXAPIQueue) MarkFailed(ctx context.Context, id int64, cause string, retryAt *time.Time) error {
+	if q.failed == nil {
+		q.failed = make(map[int64]*time.Time)
+	}
+	q.failed[id] = retryAt
+	return nil
+}
+
+type stubLRS struct {
+	err     error
+	batches [][]json.RawMessage
+}
+
+func (l *stubLRS) SendStatements(ctx context.Context, statements []json.RawMessage) error {
+	l.batches = append(l.batches, statements)
+	return l.err
+}
+
+func newTestXAPIService(queue *stubXAPIQueue, lrs *stubLRS) *XAPIService {
+	player := "p-1"
+	sessions := stubSessionRepo{getFn: func(ctx context.Context, id string) (entity.Session, error) {
+		return entity.Session{ID: id, PlayerID: &player}, nil
+	}}
+	matches := stubMatchRepo{matches: map[string]entity.Match{
+		"m-1": {ID: "m-1", SessionID: "s-1", DifficultyID: 1, LevelN: 1, IsActive: true},
+	}}
+	moves := stubMoveRepo{moves: []entity.Move{{ID: "mv-1", MatchID: "m-1", Seq: 1, IsCorrect: true}}}
+	hints := &stubHintRepo{created: []entity.Hint{
+		{ID: "h-1", MatchID: "m-1", Seq: 0, HintType: entity.HintTypeStrategy, Source: entity.HintSourcePlayer},
+		{ID: "h-2", MatchID: "m-1", Seq: 1, HintType: entity.HintTypeFrog, Source: entity.HintSourceAgent},
+	}}
+	difficulties := stubDifficultyRepo{difficulties: []entity.Difficulty{{ID: 1, Name: "easy", NumberOfBlocks: 7}}}
+	return NewXAPIService(sessions, matches, moves, hints, difficulties, stubMatchStatsRepo{}, queue, lrs,
+		xapi.NewBuilder("https://lab.example.org", "https://lab.example.org/xapi/ranas"))
+}
+
+func TestXAPIServiceEnqueueMove(t *testing.T) {
+	queue := &stubXAPIQueue{}
+	svc := newTestXAPIService(queue, &stubLRS{})
+
+	payload, _ := json.Marshal(entity.Move{ID: "mv-1", MatchID: "m-1", Seq: 1, ElapsedMs: 1500, IsCorrect: true})
+	require.NoError(t, svc.Enqueue(context.Background(), entity.OutboxEvent{ID: 9, Type: entity.EventMoveRecorded, Payload: payload}))
+
+	require.Len(t, queue.queued, 1)
+	for id, raw := range queue.queued {
+		var st xapi.Statement
+		require.NoError(t, json.Unmarshal(raw, &st))
+		require.Equal(t, id, st.ID)
+		require.Equal(t, xapi.VerbInteracted.ID, st.Verb.ID)
+		require.Equal(t, "p-1", st.Actor.Account.Name)
+		require.Equal(t, "PT1.50S", st.Result.Duration)
+	}
+
+	err := svc.Enqueue(context.Background(), entity.OutboxEvent{Type: "Unknown", Payload: payload})
+	require.Error(t, err)
+}
+
+func TestXAPIServiceEnqueueHint(t *testing.T) {
+	queue := &stubXAPIQueue{}
+	svc := newTestXAPIService(queue, &stubLRS{})
+	require.Contains(t, XAPIEventTypes, entity.EventHintRecorded)
+
+	from := 4
+	payload, _ := json.Marshal(entity.Hint{ID: "h-9", MatchID: "m-1", Seq: 1, HintType: entity.HintTypeFrog, Source: entity.HintSourceAgent, FromIdx: &from})
+	require.NoError(t, svc.Enqueue(context.Background(), entity.OutboxEvent{ID: 10, Type: entity.EventHintRecorded, Payload: payload}))
+
+	require.Len(t, queue.queued, 1)
+	for _, raw := range queue.queued {
+		var st xapi.Statement
+		require.NoError(t, json.Unmarshal(raw, &st))
+		require.Equal(t, xapi.VerbExperienced.ID, st.Verb.ID)
+		require.Equal(t, "p-1", st.Actor.Account.Name)
+		require.Equal(t, entity.HintSourceAgent, st.Result.Extensions["https://lab.example.org/xapi/ranas/extensions/hint-source"])
+	}
+}
+
+func TestXAPIServicePreview(t *testing.T) {
+	statements, err := newTestXAPIService(&stubXAPIQueue{}, nil).Preview(context.Background(), "m-1")
+	require.NoError(t, err)
+	// The match is still active, so there is no completion statement yet.
+	// Each hint follows the move it was shown after.
+	require.Len(t, statements, 4)
+	require.Equal(t, xapi.VerbAttempted.ID, statements[0].Verb.ID)
+	require.Equal(t, xapi.VerbExperienced.ID, statements[1].Verb.ID)
+	require.Equal(t, xapi.VerbInteracted.ID, statements[2].Verb.ID)
+	require.Equal(t, xapi.VerbExperienced.ID, statements[3].Verb.ID)
+}
+
+func TestXAPIServiceSendDue(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	queue := &stubXAPIQueue{due: []entity.XAPIStatement{
+		{ID: 1, Statement: json.RawMessage(`{}`), Attempts: 1},
+		{ID: 2, Statement: json.RawMessage(`{}`), Attempts: 2},
+	}}
+	lrs := &stubLRS{}
+	svc := newTestXAPIService(queue, lrs)
+	svc.now = func() time.Time { return now }
+
+	n, err := svc.SendDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, []int64{1, 2}, queue.sent)
+	require.Len(t, lrs.batches, 1)
+
+	lrs.err = errors.New("lrs responded 503 Service Unavailable")
+	svc.MaxAttempts = 2
+	queue.due = []entity.XAPIStatement{{ID: 3, Attempts: 1}, {ID: 4, Attempts: 2}}
+	_, err = svc.SendDue(context.Background())
+	require.NoError(t, err)
+
+	retryAt := now.Add(30 * time.Second)
+	require.Equal(t, map[int64]*time.Time{3: &retryAt, 4: nil}, queue.failed)
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// MatchArchive records the moves of a match moved to the object store. While
+// RestoredAt is nil the moves are only in the archive; match_stats is kept
+// either way.
+type MatchArchive struct {
+	MatchID    string     `json:"match_id"`
+	ObjectKey  string     `json:"object_key"`
+	Moves      int        `json:"moves"`
+	LastSeq    int        `json:"last_seq"`
+	ArchivedAt time.Time  `json:"archived_at"`
+	RestoredAt *time.Time `json:"restored_at"`
+}
@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Actors recorded when no admin is behind a change: game clients calling the
+// public API and background workers.
+const (
+	AuditActorClient = "client"
+	AuditActorSystem = "system"
+)
+
+// Actions recorded by the repository hooks.
+const (
+	AuditMatchUpdate   = "match.update"
+	AuditSessionUpdate = "session.update"
+)
+
+// AuditEntry records who changed what. Changes maps each modified field to
+// its before and after values, e.g. {"outcome":{"before":null,"after":"win"}};
+// it is null for admin requests, which are recorded by route.
+type AuditEntry struct {
+	ID         int64           `json:"id"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   *string         `json:"entity_id"`
+	Changes    json.RawMessage `json:"changes"`
+	RequestID  *string         `json:"request_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditFilter narrows the audit log; nil fields match everything.
+type AuditFilter struct {
+	Actor      *string
+	Action     *string
+	EntityType *string
+	EntityID   *string
+	RequestID  *string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+}
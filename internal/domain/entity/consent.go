@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// What happens to a player's history when they withdraw consent.
+const (
+	WithdrawalDelete    = PrivacyDelete
+	WithdrawalAnonymize = PrivacyAnonymize
+)
+
+// Consent is one consent form signed for a player. Records are kept as a
+// history; the latest one is in force.
+type Consent struct {
+	ID          int64  `json:"id"`
+	PlayerID    string `json:"player_id"`
+	FormVersion string `json:"form_version"`
+	// ResearchConsent allows storing detailed move data (the boards) for
+	// research. Players are minors, so it also needs GuardianConsent.
+	ResearchConsent  bool       `json:"research_consent"`
+	GuardianConsent  bool       `json:"guardian_consent"`
+	GuardianName     *string    `json:"guardian_name"`
+	GivenAt          time.Time  `json:"given_at"`
+	WithdrawnAt      *time.Time `json:"withdrawn_at"`
+	WithdrawalAction *string    `json:"withdrawal_action"`
+}
+
+// AllowsResearch reports whether detailed move data may be stored under c.
+func (c Consent) AllowsResearch() bool {
+	return c.WithdrawnAt == nil && c.ResearchConsent && c.GuardianConsent
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Curriculum is an ordered sequence of levels a session goes through.
+// A session moves up a level after AdvanceAfter wins on it. Losses and
+// timeouts repeat the level, and after StepBackAfter of them in a row the
+// session goes back one level; nil StepBackAfter means always repeat.
+type Curriculum struct {
+	ID            int               `json:"id"`
+	Name          string            `json:"name"`
+	AdvanceAfter  int               `json:"advance_after"`
+	StepBackAfter *int              `json:"step_back_after"`
+	IsDefault     bool              `json:"is_default"`
+	Levels        []CurriculumLevel `json:"levels"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+// CurriculumLevel is one step of a curriculum. LevelN starts at 1 and is the
+// value stored in matches.level_n.
+type CurriculumLevel struct {
+	LevelN       int `json:"level_n"`
+	DifficultyID int `json:"difficulty_id"`
+}
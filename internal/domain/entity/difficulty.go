@@ -1,8 +1,22 @@
 package entity
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // Difficulty represents the difficulty table described in seed.sql.
+// TimeLimitMs is the time budget of a match, excluding pauses; nil means no
+// limit. Layout is the starting board as an array of cells; nil means the
+// standard board for NumberOfBlocks. Every change bumps Version, and matches
+// keep the version they were played on. Retired difficulties take no new
+// matches.
 type Difficulty struct {
-	ID             int    `json:"id"`
-	Name           string `json:"name"`
-	NumberOfBlocks int    `json:"number_of_blocks"`
+	ID             int             `json:"id"`
+	Name           string          `json:"name"`
+	NumberOfBlocks int             `json:"number_of_blocks"`
+	TimeLimitMs    *int            `json:"time_limit_ms"`
+	Layout         json.RawMessage `json:"layout"`
+	Version        int             `json:"version"`
+	RetiredAt      *time.Time      `json:"retired_at"`
 }
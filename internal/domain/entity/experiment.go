@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Experiment units: what gets assigned to an arm. Player experiments keep a
+// player in the same arm across sessions; sessions without a player fall
+// back to the session.
+const (
+	ExperimentUnitPlayer  = "player"
+	ExperimentUnitSession = "session"
+)
+
+// Experiment compares tutoring conditions. Units are split between the arms
+// in proportion to their weights. Inactive experiments assign nobody new.
+type Experiment struct {
+	ID          int             `json:"id"`
+	Key         string          `json:"key"`
+	Description *string         `json:"description"`
+	Unit        string          `json:"unit"`
+	IsActive    bool            `json:"is_active"`
+	Arms        []ExperimentArm `json:"arms"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// ExperimentArm is one condition of an experiment. Config carries the
+// parameters clients apply for it, e.g. {"hints":"agent"}.
+type ExperimentArm struct {
+	Name   string          `json:"name"`
+	Weight int             `json:"weight"`
+	Config json.RawMessage `json:"config"`
+}
+
+// ExperimentAssignment records the arm a unit was assigned to.
+type ExperimentAssignment struct {
+	ExperimentID int             `json:"experiment_id"`
+	Experiment   string          `json:"experiment"`
+	UnitID       string          `json:"unit_id"`
+	Arm          string          `json:"arm"`
+	Config       json.RawMessage `json:"config"`
+	AssignedAt   time.Time       `json:"assigned_at"`
+}
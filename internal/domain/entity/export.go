@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// ExportFilter selects the rows of a data export. Nil fields are not
+// filtered on. The date range applies to matches.started_at (sessions.started_at
+// for the sessions export) and To is exclusive. Experiment and Arm select
+// matches by their experiment tag as in ReportFilter.
+type ExportFilter struct {
+	From         *time.Time
+	To           *time.Time
+	PlayerID     *string
+	DifficultyID *int
+	Experiment   *string
+	Arm          *string
+}
@@ -0,0 +1,62 @@
+package entity
+
+import "time"
+
+// Hint types. A next_move hint suggests a whole move, a frog hint points at
+// the frog to move and a strategy hint is free advice with no target.
+const (
+	HintTypeNextMove = "next_move"
+	HintTypeFrog     = "frog"
+	HintTypeStrategy = "strategy"
+)
+
+// Who asked for the hint.
+const (
+	HintSourcePlayer = "player"
+	HintSourceAgent  = "agent"
+)
+
+// Hint mirrors the hints table. Seq is the seq of the last move when the hint
+// was shown, so the hint applies to move Seq+1. Followed stays nil until that
+// move is recorded.
+type Hint struct {
+	ID         string     `json:"id"`
+	MatchID    string     `json:"match_id"`
+	Seq        int        `json:"seq"`
+	HintType   string     `json:"hint_type"`
+	Source     string     `json:"source"`
+	FromIdx    *int       `json:"from_idx"`
+	ToIdx      *int       `json:"to_idx"`
+	Dismissed  bool       `json:"dismissed"`
+	Followed   *bool      `json:"followed"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+}
+
+// FollowedBy reports whether mv, the move played right after the hint,
+// followed it. Undos and restarts never do. Hints without a target are
+// followed by a correct move; otherwise the move must use the suggested frog
+// and, when given, the suggested block.
+func (h Hint) FollowedBy(mv Move) bool {
+	switch {
+	case mv.MoveKind != MoveKindStep && mv.MoveKind != MoveKindJump:
+		return false
+	case h.FromIdx == nil:
+		return mv.IsCorrect
+	default:
+		return *h.FromIdx == mv.FromIdx && (h.ToIdx == nil || *h.ToIdx == mv.ToIdx)
+	}
+}
+
+// HintSummary counts the hints of a match. ErrorsAfterHint is the number of
+// incorrect moves played right after a hint, so they can be told apart from
+// independent errors.
+type HintSummary struct {
+	Total           int `json:"total"`
+	PlayerRequested int `json:"player_requested"`
+	AgentInitiated  int `json:"agent_initiated"`
+	Dismissed       int `json:"dismissed"`
+	Followed        int `json:"followed"`
+	NotFollowed     int `json:"not_followed"`
+	ErrorsAfterHint int `json:"errors_after_hint"`
+}
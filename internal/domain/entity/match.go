@@ -5,15 +5,25 @@ import (
 	"time"
 )
 
-// Match mirrors the matches table.
+// Match outcomes. Clients finish a match as won or lost; OutcomeTimeout is
+// set on matches finished automatically because they ran out of time.
+const (
+	OutcomeWin     = "win"
+	OutcomeLose    = "lose"
+	OutcomeTimeout = "timeout"
+)
+
+// Match mirrors the matches table. DifficultyVersion is the version of the
+// difficulty the match was started on; the database sets it on insert.
 type Match struct {
-	ID           string          `json:"id"`
-	SessionID    string          `json:"session_id"`
-	DifficultyID int             `json:"difficulty_id"`
-	LevelN       int             `json:"level_n"`
-	IsActive     bool            `json:"is_active"`
-	StartedAt    time.Time       `json:"started_at"`
-	EndedAt      *time.Time      `json:"ended_at"`
-	Outcome      *string         `json:"outcome"`
-	Meta         json.RawMessage `json:"meta"`
+	ID                string          `json:"id"`
+	SessionID         string          `json:"session_id"`
+	DifficultyID      int             `json:"difficulty_id"`
+	DifficultyVersion int             `json:"difficulty_version"`
+	LevelN            int             `json:"level_n"`
+	IsActive          bool            `json:"is_active"`
+	StartedAt         time.Time       `json:"started_at"`
+	EndedAt           *time.Time      `json:"ended_at"`
+	Outcome           *string         `json:"outcome"`
+	Meta              json.RawMessage `json:"meta"`
 }
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// Match event types.
+const (
+	MatchEventMoveRecorded  = "move_recorded"
+	MatchEventMatchFinished = "match_finished"
+)
+
+// MatchEvent notifies observers that a match changed. Move or Match is set
+// depending on Type.
+type MatchEvent struct {
+	Type       string    `json:"type"`
+	MatchID    string    `json:"match_id"`
+	Seq        int       `json:"seq"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Move       *Move     `json:"move,omitempty"`
+	Match      *Match    `json:"match,omitempty"`
+}
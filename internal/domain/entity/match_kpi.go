@@ -10,5 +10,7 @@ type MatchKPI struct {
 	AvgTimeMs       int       `json:"avg_time_ms"`
 	BuclicidadAvg   float64   `json:"buclicidad_avg"`
 	BranchFactorAvg float64   `json:"branch_factor_avg"`
+	Undos           int       `json:"undos"`
+	Restarts        int       `json:"restarts"`
 	ComputedAt      time.Time `json:"computed_at"`
 }
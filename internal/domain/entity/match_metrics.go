@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// MatchMetrics mirrors the match_metrics table: cognitive indicators derived
+// from the move sequence of a finished match. Nullable fields are nil when
+// the match has too few moves to compute them.
+type MatchMetrics struct {
+	MatchID                 string    `json:"match_id"`
+	ThinkOutliers           int       `json:"think_outliers"`
+	ThinkOutlierThresholdMs *int      `json:"think_outlier_threshold_ms"`
+	OptimalMoves            int       `json:"optimal_moves"`
+	LongestOptimalStreak    int       `json:"longest_optimal_streak"`
+	AvgRecoveryMs           *float64  `json:"avg_recovery_ms"`
+	FirstMoveLatencyMs      *int      `json:"first_move_latency_ms"`
+	Restarts                int       `json:"restarts"`
+	Jumps                   int       `json:"jumps"`
+	Steps                   int       `json:"steps"`
+	JumpRatio               *float64  `json:"jump_ratio"`
+	ComputedAt              time.Time `json:"computed_at"`
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// MatchPause mirrors the match_pauses table. ResumedAt is nil while the
+// match is paused.
+type MatchPause struct {
+	ID        int64      `json:"id"`
+	MatchID   string     `json:"match_id"`
+	PausedAt  time.Time  `json:"paused_at"`
+	ResumedAt *time.Time `json:"resumed_at"`
+}
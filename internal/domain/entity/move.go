@@ -5,6 +5,15 @@ import (
 	"time"
 )
 
+// Values of moves.move_kind. Undo and restart rows are actions recorded in
+// the move log rather than frog moves; they never count as errors.
+const (
+	MoveKindStep    int16 = 1
+	MoveKindJump    int16 = 2
+	MoveKindUndo    int16 = 3
+	MoveKindRestart int16 = 4
+)
+
 // Move mirrors the moves table.
 type Move struct {
 	ID              string          `json:"id"`
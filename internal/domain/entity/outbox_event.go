@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Domain event types written to the outbox.
+const (
+	EventSessionStarted  = "SessionStarted"
+	EventMatchStarted    = "MatchStarted"
+	EventMoveRecorded    = "MoveRecorded"
+	EventMatchFinished   = "MatchFinished"
+	EventSessionFinished = "SessionFinished"
+	EventHintRecorded    = "HintRecorded"
+)
+
+// OutboxEvent mirrors the outbox_events table. Payload is the JSON of the
+// entity that changed (Match, Move, Hint or Session).
+type OutboxEvent struct {
+	ID          int64           `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID string          `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Attempts    int             `json:"attempts"`
+}
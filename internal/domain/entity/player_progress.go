@@ -0,0 +1,52 @@
+package entity
+
+import "time"
+
+// ProgressFilter narrows a player's progress to the matches of one
+// experiment (Experiment) or one of its arms (Arm, which needs Experiment).
+// Nil fields are not filtered on.
+type ProgressFilter struct {
+	Experiment *string
+	Arm        *string
+}
+
+// ProgressPoint is one finished match of a player with its KPIs and the
+// moving averages over the player's previous attempts at the same difficulty.
+type ProgressPoint struct {
+	MatchID          string    `json:"match_id"`
+	SessionID        string    `json:"session_id"`
+	DifficultyID     int       `json:"difficulty_id"`
+	Attempt          int       `json:"attempt"`
+	StartedAt        time.Time `json:"started_at"`
+	Outcome          *string   `json:"outcome"`
+	TotalMoves       int       `json:"total_moves"`
+	Errors           int       `json:"errors"`
+	AvgTimeMs        int       `json:"avg_time_ms"`
+	ErrorsMovingAvg  float64   `json:"errors_moving_avg"`
+	AvgTimeMovingAvg float64   `json:"avg_time_moving_avg"`
+}
+
+// DifficultyProgress summarises a player's finished matches at one
+// difficulty. The slopes are least-squares fits of errors and avg_time_ms
+// against the attempt number; negative values mean the player is improving.
+// They are nil until there are at least two attempts.
+type DifficultyProgress struct {
+	DifficultyID     int      `json:"difficulty_id"`
+	DifficultyName   string   `json:"difficulty_name"`
+	Matches          int      `json:"matches"`
+	Wins             int      `json:"wins"`
+	WinRate          float64  `json:"win_rate"`
+	BestSolveMoves   *int     `json:"best_solve_moves"`
+	BestSolveMatchID *string  `json:"best_solve_match_id"`
+	ErrorsSlope      *float64 `json:"errors_slope"`
+	AvgTimeSlope     *float64 `json:"avg_time_slope"`
+}
+
+// PlayerProgress is the learning curve of a player across all their sessions.
+type PlayerProgress struct {
+	PlayerID     string               `json:"player_id"`
+	Sessions     int                  `json:"sessions"`
+	Matches      int                  `json:"matches"`
+	Difficulties []DifficultyProgress `json:"difficulties"`
+	Trend        []ProgressPoint      `json:"trend"`
+}
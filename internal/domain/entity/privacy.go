@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// Data subject operations recorded in the privacy audit.
+const (
+	PrivacyExport    = "export"
+	PrivacyDelete    = "delete"
+	PrivacyAnonymize = "anonymize"
+)
+
+// Why a data subject operation was run.
+const (
+	PrivacyReasonAdminRequest      = "admin_request"
+	PrivacyReasonConsentWithdrawal = "consent_withdrawal"
+)
+
+// PrivacyAudit records an operation on the data of a player. PlayerID is the
+// ID the operation was asked for, which no longer matches any session once
+// the player is deleted or anonymized.
+type PrivacyAudit struct {
+	ID               int64     `json:"id"`
+	PlayerID         string    `json:"player_id"`
+	Action           string    `json:"action"`
+	Reason           string    `json:"reason"`
+	Actor            string    `json:"actor"`
+	RequestID        *string   `json:"request_id"`
+	SessionsAffected int       `json:"sessions_affected"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// PlayerData is everything stored about a player, as handed out for a data
+// access request.
+type PlayerData struct {
+	PlayerID    string     `json:"player_id"`
+	GeneratedAt time.Time  `json:"generated_at"`
+	Sessions    []Session  `json:"sessions"`
+	Matches     []Match    `json:"matches"`
+	Moves       []Move     `json:"moves"`
+	Stats       []MatchKPI `json:"stats"`
+	Cohorts     []string   `json:"cohorts"`
+	Consents    []Consent  `json:"consents"`
+}
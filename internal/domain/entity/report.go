@@ -0,0 +1,60 @@
+package entity
+
+import "time"
+
+// ReportFilter narrows the finished matches included in a report. Nil fields
+// are not filtered on. The date range applies to matches.started_at and To is
+// exclusive. Experiment keeps the matches tagged with an arm of that
+// experiment, and Arm, which needs Experiment, those of a single arm.
+type ReportFilter struct {
+	From         *time.Time
+	To           *time.Time
+	Device       *string
+	Outcome      *string
+	DifficultyID *int
+	Experiment   *string
+	Arm          *string
+}
+
+// Distribution describes one KPI over a group of matches. StdDev is nil when
+// the group has a single match.
+type Distribution struct {
+	Mean   float64  `json:"mean"`
+	Median float64  `json:"median"`
+	P90    float64  `json:"p90"`
+	StdDev *float64 `json:"stddev"`
+}
+
+// KPIDistributions holds a distribution per match_stats column.
+type KPIDistributions struct {
+	TotalMoves      Distribution `json:"total_moves"`
+	Errors          Distribution `json:"errors"`
+	AvgTimeMs       Distribution `json:"avg_time_ms"`
+	BuclicidadAvg   Distribution `json:"buclicidad_avg"`
+	BranchFactorAvg Distribution `json:"branch_factor_avg"`
+}
+
+type DifficultyReport struct {
+	DifficultyID   int              `json:"difficulty_id"`
+	DifficultyName string           `json:"difficulty_name"`
+	Matches        int              `json:"matches"`
+	KPIs           KPIDistributions `json:"kpis"`
+}
+
+// ArmReport groups the matches of an experiment by the arm they were played
+// under.
+type ArmReport struct {
+	Arm     string           `json:"arm"`
+	Players int              `json:"players"`
+	Matches int              `json:"matches"`
+	KPIs    KPIDistributions `json:"kpis"`
+}
+
+// CohortReport groups matches by the cohorts their players belong to. A
+// player in several cohorts counts towards each of them.
+type CohortReport struct {
+	Cohort  string           `json:"cohort"`
+	Players int              `json:"players"`
+	Matches int              `json:"matches"`
+	KPIs    KPIDistributions `json:"kpis"`
+}
@@ -2,12 +2,14 @@ package entity
 
 import "time"
 
-// Session mirrors the sessions table.
+// Session mirrors the sessions table. A nil CurriculumID means the session
+// follows the default curriculum.
 type Session struct {
-	ID         string     `json:"id"`
-	PlayerID   *string    `json:"player_id"`
-	Device     *string    `json:"device"`
-	IsFinished bool       `json:"is_finished"`
-	StartedAt  time.Time  `json:"started_at"`
-	EndedAt    *time.Time `json:"ended_at"`
+	ID           string     `json:"id"`
+	PlayerID     *string    `json:"player_id"`
+	Device       *string    `json:"device"`
+	CurriculumID *int       `json:"curriculum_id"`
+	IsFinished   bool       `json:"is_finished"`
+	StartedAt    time.Time  `json:"started_at"`
+	EndedAt      *time.Time `json:"ended_at"`
 }
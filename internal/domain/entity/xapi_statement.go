@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// xAPI statement delivery statuses.
+const (
+	XAPIStatementPending = "pending"
+	XAPIStatementSent    = "sent"
+	XAPIStatementFailed  = "failed"
+)
+
+// XAPIStatement mirrors the xapi_statements table, the queue of statements
+// waiting to be sent to the learning record store.
+type XAPIStatement struct {
+	ID            int64           `json:"id"`
+	StatementID   string          `json:"statement_id"`
+	Statement     json.RawMessage `json:"statement"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	LastError     *string         `json:"last_error"`
+	CreatedAt     time.Time       `json:"created_at"`
+	SentAt        *time.Time      `json:"sent_at"`
+}
@@ -0,0 +1,163 @@
+// Package game models the frog puzzle played in each match: a row of blocks
+// with left frogs on one side, right frogs on the other and a single empty
+// block in the middle. Custom layouts may change the frog counts, the empty
+// blocks and add obstacles.
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Cell is the content of a block. The frog values match moves.frog_side.
+type Cell int8
+
+const (
+	Empty Cell = 0
+	Left  Cell = 1 // moves towards higher indexes
+	Right Cell = 2 // moves towards lower indexes
+	// Obstacle blocks can neither be moved to nor jumped over.
+	Obstacle Cell = 3
+)
+
+var (
+	ErrInvalidSize = errors.New("board size must be odd and at least 3")
+	ErrOutOfBounds = errors.New("block index out of bounds")
+	ErrNoFrog      = errors.New("no frog on the source block")
+	ErrOccupied    = errors.New("target block is occupied")
+	ErrNoEmpty     = errors.New("board needs at least one empty block")
+	ErrUnsolvable  = errors.New("board cannot be solved")
+	ErrSolved      = errors.New("board is already solved")
+)
+
+// Board is the state of every block, left to right. It encodes to JSON as an
+// array of cell values, e.g. [1,1,1,0,2,2,2].
+type Board []Cell
+
+// NewBoard returns the starting position for a difficulty with the given
+// number of blocks.
+func NewBoard(blocks int) (Board, error) {
+	if blocks < 3 || blocks%2 == 0 {
+		return nil, ErrInvalidSize
+	}
+	b := make(Board, blocks)
+	half := blocks / 2
+	for i := range half {
+		b[i] = Left
+		b[blocks-1-i] = Right
+	}
+	return b, nil
+}
+
+// FromLayout returns the starting position of a difficulty: its layout when
+// it defines one, the standard board for the number of blocks otherwise.
+func FromLayout(layout json.RawMessage, blocks int) (Board, error) {
+	if len(layout) == 0 || string(layout) == "null" {
+		return NewBoard(blocks)
+	}
+	b, err := ParseBoard(layout)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != blocks {
+		return nil, fmt.Errorf("layout has %d blocks, want %d", len(b), blocks)
+	}
+	return b, nil
+}
+
+// ParseBoard decodes a board stored in moves.board_before/board_after or in
+// a difficulty layout.
+func ParseBoard(raw json.RawMessage) (Board, error) {
+	var b Board
+	if err := json.Unmarshal(raw, &b); err != nil {
+		return nil, err
+	}
+	for i, c := range b {
+		if c != Empty && c != Left && c != Right && c != Obstacle {
+			return nil, fmt.Errorf("invalid cell %d at index %d", c, i)
+		}
+	}
+	return b, nil
+}
+
+func (b Board) Clone() Board {
+	return append(Board(nil), b...)
+}
+
+// Apply returns the board after moving the frog on from to to. It only checks
+// that the move is physically possible, so recorded history can always be
+// replayed.
+func (b Board) Apply(from, to int) (Board, error) {
+	if from < 0 || from >= len(b) || to < 0 || to >= len(b) {
+		return nil, ErrOutOfBounds
+	}
+	if !b.frog(from) {
+		return nil, ErrNoFrog
+	}
+	if b[to] != Empty {
+		return nil, ErrOccupied
+	}
+	next := b.Clone()
+	next[to], next[from] = next[from], Empty
+	return next, nil
+}
+
+// Solved reports whether every frog has crossed to the opposite side.
+func (b Board) Solved() bool {
+	return slices.Equal(b, b.Goal())
+}
+
+// Goal is the solved position of b: obstacles stay in place, right frogs
+// fill the free blocks from the left and left frogs from the right. On the
+// standard board that leaves the empty block in the middle.
+func (b Board) Goal() Board {
+	var left, right int
+	for _, c := range b {
+		switch c {
+		case Left:
+			left++
+		case Right:
+			right++
+		}
+	}
+	goal := b.Clone()
+	for i, c := range goal {
+		if c == Obstacle {
+			continue
+		}
+		goal[i] = Empty
+		if right > 0 {
+			goal[i], right = Right, right-1
+		}
+	}
+	for i := len(goal) - 1; i >= 0 && left > 0; i-- {
+		if goal[i] == Empty {
+			goal[i], left = Left, left-1
+		}
+	}
+	return goal
+}
+
+// Validate checks that b can be used as a starting position: an odd number
+// of blocks, room to move and a solution that has not been reached yet.
+func (b Board) Validate() error {
+	if len(b) < 3 || len(b)%2 == 0 {
+		return ErrInvalidSize
+	}
+	if !slices.Contains(b, Empty) {
+		return ErrNoEmpty
+	}
+	switch NewSolver().Distance(b) {
+	case -1:
+		return ErrUnsolvable
+	case 0:
+		return ErrSolved
+	}
+	return nil
+}
+
+func (b Board) frog(i int) bool {
+	return b[i] == Left || b[i] == Right
+}
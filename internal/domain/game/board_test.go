@@ -0,0 +1,74 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBoard(t *testing.T) {
+	b, err := NewBoard(7)
+	require.NoError(t, err)
+	require.Equal(t, Board{Left, Left, Left, Empty, Right, Right, Right}, b)
+
+	_, err = NewBoard(8)
+	require.ErrorIs(t, err, ErrInvalidSize)
+}
+
+func TestBoardApply(t *testing.T) {
+	b, _ := NewBoard(3)
+
+	next, err := b.Apply(0, 1)
+	require.NoError(t, err)
+	require.Equal(t, Board{Empty, Left, Right}, next)
+	require.Equal(t, Board{Left, Empty, Right}, b, "apply must not mutate the receiver")
+
+	_, err = b.Apply(1, 0)
+	require.ErrorIs(t, err, ErrNoFrog)
+	_, err = b.Apply(0, 2)
+	require.ErrorIs(t, err, ErrOccupied)
+	_, err = b.Apply(0, 3)
+	require.ErrorIs(t, err, ErrOutOfBounds)
+}
+
+func TestBoardSolvedAndJSON(t *testing.T) {
+	require.True(t, Board{Right, Empty, Left}.Solved())
+	require.False(t, Board{Left, Empty, Right}.Solved())
+
+	raw, err := json.Marshal(Board{Left, Empty, Right})
+	require.NoError(t, err)
+	require.JSONEq(t, `[1,0,2]`, string(raw))
+
+	parsed, err := ParseBoard(raw)
+	require.NoError(t, err)
+	require.Equal(t, Board{Left, Empty, Right}, parsed)
+
+	_, err = ParseBoard(json.RawMessage(`[1,4,2]`))
+	require.Error(t, err)
+}
+
+func TestBoardLayouts(t *testing.T) {
+	b, err := FromLayout(json.RawMessage(`[1,1,1,0,2]`), 5)
+	require.NoError(t, err)
+	require.Equal(t, Board{Right, Empty, Left, Left, Left}, b.Goal(), "asymmetric frog counts")
+	require.NoError(t, b.Validate())
+
+	b, err = FromLayout(nil, 5)
+	require.NoError(t, err)
+	require.Equal(t, Board{Left, Left, Empty, Right, Right}, b)
+	_, err = FromLayout(json.RawMessage(`[1,0,2]`), 5)
+	require.Error(t, err)
+
+	withObstacle := Board{Left, Empty, Obstacle, Empty, Right}
+	require.Equal(t, Board{Right, Empty, Obstacle, Empty, Left}, withObstacle.Goal())
+	require.ErrorIs(t, withObstacle.Validate(), ErrUnsolvable, "frogs cannot pass the obstacle")
+	_, err = withObstacle.Apply(2, 1)
+	require.ErrorIs(t, err, ErrNoFrog)
+	require.ErrorIs(t, Board{Left, Obstacle, Empty}.Legal(0, 2), ErrNothingToJump)
+
+	require.NoError(t, Board{Obstacle, Left, Empty, Right, Obstacle}.Validate())
+	require.ErrorIs(t, Board{Left, Right, Left}.Validate(), ErrNoEmpty)
+	require.ErrorIs(t, Board{Right, Empty, Left}.Validate(), ErrSolved)
+	require.ErrorIs(t, Board{Left, Empty, Empty, Right}.Validate(), ErrInvalidSize)
+}
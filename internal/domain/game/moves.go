@@ -0,0 +1,10 @@
+package game
+
+// MinimumMoves is the length of the shortest solution for a board with the
+// given number of blocks. With n frogs per side every frog travels n+1
+// blocks and each of the n² encounters between opposite frogs costs one
+// jump, which covers two blocks: 2n(n+1) - n² = n(n+2) moves.
+func MinimumMoves(blocks int) int {
+	n := blocks / 2
+	return n * (n + 2)
+}
@@ -0,0 +1,123 @@
+package game
+
+import "errors"
+
+var (
+	ErrWrongDirection = errors.New("frog cannot move backwards")
+	ErrTooFar         = errors.New("frogs move one block or jump over one frog")
+	ErrNothingToJump  = errors.New("jumps must go over a frog")
+)
+
+// Move is a frog moving from one block to another.
+type Move struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// Legal checks the rules of the puzzle on top of Apply: left frogs only move
+// right and right frogs only move left, either one block into the empty one
+// or jumping over a single frog. Obstacles block both.
+func (b Board) Legal(from, to int) error {
+	if from < 0 || from >= len(b) || to < 0 || to >= len(b) {
+		return ErrOutOfBounds
+	}
+	if !b.frog(from) {
+		return ErrNoFrog
+	}
+	if b[to] != Empty {
+		return ErrOccupied
+	}
+	step := to - from
+	if b[from] == Right {
+		step = -step
+	}
+	switch step {
+	case 1:
+		return nil
+	case 2:
+		if !b.frog((from + to) / 2) {
+			return ErrNothingToJump
+		}
+		return nil
+	}
+	if step <= 0 {
+		return ErrWrongDirection
+	}
+	return ErrTooFar
+}
+
+// LegalMoves lists every legal move on the board.
+func (b Board) LegalMoves() []Move {
+	var moves []Move
+	for from, c := range b {
+		if !b.frog(from) {
+			continue
+		}
+		dir := 1
+		if c == Right {
+			dir = -1
+		}
+		for _, to := range []int{from + dir, from + 2*dir} {
+			if b.Legal(from, to) == nil {
+				moves = append(moves, Move{From: from, To: to})
+			}
+		}
+	}
+	return moves
+}
+
+// Solver computes the shortest distance to the solution. Frogs never move
+// backwards, so the positions form a DAG and distances are memoized per
+// position. A Solver is not safe for concurrent use.
+type Solver struct {
+	memo map[string]int
+}
+
+func NewSolver() *Solver {
+	return &Solver{memo: make(map[string]int)}
+}
+
+// Distance returns the number of moves of the shortest solution from b, or
+// -1 when the solution can no longer be reached.
+func (s *Solver) Distance(b Board) int {
+	if b.Solved() {
+		return 0
+	}
+	key := b.Key()
+	if d, ok := s.memo[key]; ok {
+		return d
+	}
+	best := -1
+	for _, mv := range b.LegalMoves() {
+		next, _ := b.Apply(mv.From, mv.To)
+		if d := s.Distance(next); d >= 0 && (best < 0 || d+1 < best) {
+			best = d + 1
+		}
+	}
+	s.memo[key] = best
+	return best
+}
+
+// Optimal reports whether moving from to to on b is legal and keeps the
+// player on a shortest solution.
+func (s *Solver) Optimal(b Board, from, to int) bool {
+	if b.Legal(from, to) != nil {
+		return false
+	}
+	d := s.Distance(b)
+	if d <= 0 {
+		return false
+	}
+	next, _ := b.Apply(from, to)
+	return s.Distance(next) == d-1
+}
+
+// Key identifies the position of b, e.g. to tell whether it was seen
+// before.
+func (b Board) Key() string {
+	out := make([]byte, len(b))
+	for i, c := range b {
+		out[i] = byte(c)
+	}
+	return string(out)
+}
@@ -0,0 +1,47 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoardLegal(t *testing.T) {
+	b := Board{Left, Left, Empty, Right, Right}
+
+	require.NoError(t, b.Legal(1, 2))
+	require.NoError(t, b.Legal(0, 2))
+	require.NoError(t, b.Legal(3, 2))
+	require.NoError(t, b.Legal(4, 2))
+	require.ErrorIs(t, Board{Empty, Right, Left}.Legal(2, 0), ErrWrongDirection)
+	require.ErrorIs(t, Board{Left, Empty, Empty, Empty}.Legal(0, 3), ErrTooFar)
+	require.ErrorIs(t, Board{Left, Empty, Empty}.Legal(0, 2), ErrNothingToJump)
+	require.ErrorIs(t, b.Legal(0, 1), ErrOccupied)
+
+	require.ElementsMatch(t, []Move{{0, 2}, {1, 2}, {3, 2}, {4, 2}}, b.LegalMoves())
+}
+
+func TestSolverDistance(t *testing.T) {
+	s := NewSolver()
+	for _, blocks := range []int{3, 5, 7, 9, 11} {
+		b, _ := NewBoard(blocks)
+		require.Equal(t, MinimumMoves(blocks), s.Distance(b), "blocks=%d", blocks)
+	}
+
+	// Two left frogs next to each other in front of a right frog: stuck.
+	require.Equal(t, -1, s.Distance(Board{Empty, Left, Left, Right, Right}))
+}
+
+func TestSolverOptimal(t *testing.T) {
+	s := NewSolver()
+	b, _ := NewBoard(5)
+
+	require.True(t, s.Optimal(b, 1, 2))
+	// Moving the inner left frog then the outer one leaves two left frogs
+	// blocking each other.
+	next, _ := b.Apply(1, 2)
+	require.False(t, s.Optimal(next, 0, 1))
+	require.True(t, s.Optimal(next, 3, 1))
+	require.False(t, s.Optimal(b, 0, 2), "jumping over a frog of the same side wastes the jump")
+	require.False(t, s.Optimal(b, 3, 4), "illegal moves are never optimal")
+}
@@ -0,0 +1,32 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+// AnalyticsRepo is a read model over sessions, matches and match_stats. Only
+// finished matches are taken into account.
+type AnalyticsRepo interface {
+	// PlayerTotals counts the player's sessions and the finished matches
+	// that pass the filter.
+	PlayerTotals(ctx context.Context, playerID string, filter entity.ProgressFilter) (sessions int, matches int, err error)
+	PlayerTrend(ctx context.Context, playerID string, filter entity.ProgressFilter) ([]entity.ProgressPoint, error)
+	PlayerDifficulties(ctx context.Context, playerID string, filter entity.ProgressFilter) ([]entity.DifficultyProgress, error)
+}
+
+type ReportRepo interface {
+	DifficultyReport(ctx context.Context, filter entity.ReportFilter) ([]entity.DifficultyReport, error)
+	CohortReport(ctx context.Context, filter entity.ReportFilter) ([]entity.CohortReport, error)
+	// ArmReport groups by the arm of filter.Experiment, which must be set.
+	ArmReport(ctx context.Context, filter entity.ReportFilter) ([]entity.ArmReport, error)
+}
+
+// CohortRepo stores the research groups (classroom, age band...) each player
+// belongs to.
+type CohortRepo interface {
+	// SetPlayerCohorts replaces the player's cohorts with the given set.
+	SetPlayerCohorts(ctx context.Context, playerID string, cohorts []string) error
+	PlayerCohorts(ctx context.Context, playerID string) ([]string, error)
+}
@@ -0,0 +1,43 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+// ObjectStore keeps archive files by key, e.g. "moves/<match id>.jsonl.gz".
+type ObjectStore interface {
+	// Put stores body under key, replacing any previous object.
+	Put(ctx context.Context, key string, body io.Reader) error
+	// Get opens the object under key. Missing objects give an error
+	// matching fs.ErrNotExist.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object under key; missing objects are not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// ArchiveRepo tracks the matches whose moves were archived.
+type ArchiveRepo interface {
+	// ListArchivable returns finished matches with moves that ended before
+	// the cutoff, oldest first. Matches restored after the cutoff are left
+	// alone.
+	ListArchivable(ctx context.Context, before time.Time, limit int) ([]string, error)
+	// Archive records the archive and deletes the moves it holds, returning
+	// how many were deleted. match_stats is not recomputed.
+	Archive(ctx context.Context, archive entity.MatchArchive) (int, error)
+	// Get returns the archive of a match still archived, or pgx.ErrNoRows.
+	Get(ctx context.Context, matchID string) (entity.MatchArchive, error)
+	// Restore inserts the archived moves back and marks the archive restored,
+	// returning how many moves were inserted.
+	Restore(ctx context.Context, matchID string, moves []entity.Move) (int, error)
+	// ListOrphaned returns archives whose match was deleted.
+	ListOrphaned(ctx context.Context, limit int) ([]entity.MatchArchive, error)
+	Delete(ctx context.Context, matchID string) error
+	// ListBoardsToClear returns archives whose boards were cleared in the
+	// database, by anonymization, but are still in the object.
+	ListBoardsToClear(ctx context.Context, limit int) ([]entity.MatchArchive, error)
+	MarkBoardsCleared(ctx context.Context, matchID string) error
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+// AuditRepo stores the audit log. Updates of matches and sessions write their
+// own entries; Record is for operations recorded from outside a repository.
+type AuditRepo interface {
+	Record(ctx context.Context, entry entity.AuditEntry) (entity.AuditEntry, error)
+	// List returns the matching entries, latest first.
+	List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error)
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event entity.MatchEvent) error
+}
+
+type EventSubscriber interface {
+	// Subscribe delivers events for matchID until ctx is done. The channel is
+	// also closed early when the subscriber falls too far behind.
+	Subscribe(ctx context.Context, matchID string) (<-chan entity.MatchEvent, error)
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+// ExportRepo streams rows for data exports. Each method calls fn once per row
+// while iterating the result set, so exports never hold a whole table in
+// memory; an error from fn stops the iteration and is returned.
+type ExportRepo interface {
+	EachSession(ctx context.Context, filter entity.ExportFilter, fn func(entity.Session) error) error
+	EachMatch(ctx context.Context, filter entity.ExportFilter, fn func(entity.Match) error) error
+	EachMove(ctx context.Context, filter entity.ExportFilter, fn func(entity.Move) error) error
+	EachMatchKPI(ctx context.Context, filter entity.ExportFilter, fn func(entity.MatchKPI) error) error
+}
@@ -0,0 +1,20 @@
+package ports
+
+import "context"
+
+type DatabaseProbe interface {
+	Ping(ctx context.Context) error
+	Stats(ctx context.Context) (PoolStats, error)
+	MissingRelations(ctx context.Context) ([]string, error)
+	// MissingColumns lists required columns, as table.column, absent from
+	// tables that exist.
+	MissingColumns(ctx context.Context) ([]string, error)
+}
+
+// PoolStats is a snapshot of the database connection pool.
+type PoolStats struct {
+	TotalConns    int32 `json:"total_conns"`
+	AcquiredConns int32 `json:"acquired_conns"`
+	IdleConns     int32 `json:"idle_conns"`
+	MaxConns      int32 `json:"max_conns"`
+}
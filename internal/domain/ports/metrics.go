@@ -0,0 +1,8 @@
+package ports
+
+// GameMetrics receives domain counters from the use cases.
+type GameMetrics interface {
+	MoveRecorded(correct bool)
+	MoveRejected(reason string)
+	MatchFinished(difficultyID int, outcome string)
+}
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/org/ranas-bdi-backend/internal/domain/entity"
 )
@@ -16,16 +17,144 @@ type MatchRepo interface {
 	Create(ctx context.Context, match entity.Match) (entity.Match, error)
 	Get(ctx context.Context, id string) (entity.Match, error)
 	Update(ctx context.Context, match entity.Match) (entity.Match, error)
+	// Finish closes the match with match.EndedAt and match.Outcome if it is
+	// still active, and returns pgx.ErrNoRows otherwise, so of several
+	// callers racing to finish a match only the first one does.
+	Finish(ctx context.Context, match entity.Match) (entity.Match, error)
 	GetActiveBySession(ctx context.Context, sessionID string) (entity.Match, error)
+	// ListBySession returns the matches of a session in the order they were
+	// started.
+	ListBySession(ctx context.Context, sessionID string) ([]entity.Match, error)
 }
 
 type MoveRepo interface {
 	Create(ctx context.Context, move entity.Move) (entity.Move, error)
 	GetByMatch(ctx context.Context, matchID string) ([]entity.Move, error)
 	GetLastByMatch(ctx context.Context, matchID string) (entity.Move, error)
+	GetBySeq(ctx context.Context, matchID string, seq int) (entity.Move, error)
 }
 
 type DifficultyRepo interface {
 	GetByID(ctx context.Context, id int) (entity.Difficulty, error)
 	GetAll(ctx context.Context) ([]entity.Difficulty, error)
+	// GetVersion returns a difficulty as it was at the given version.
+	GetVersion(ctx context.Context, id, version int) (entity.Difficulty, error)
+	ListVersions(ctx context.Context, id int) ([]entity.Difficulty, error)
+	Create(ctx context.Context, difficulty entity.Difficulty) (entity.Difficulty, error)
+	// Update returns pgx.ErrNoRows when the difficulty is missing or retired.
+	Update(ctx context.Context, difficulty entity.Difficulty) (entity.Difficulty, error)
+	Retire(ctx context.Context, id int) (entity.Difficulty, error)
+}
+
+type CurriculumRepo interface {
+	Create(ctx context.Context, curriculum entity.Curriculum) (entity.Curriculum, error)
+	Get(ctx context.Context, id int) (entity.Curriculum, error)
+	GetDefault(ctx context.Context) (entity.Curriculum, error)
+	List(ctx context.Context) ([]entity.Curriculum, error)
+}
+
+// ConsentRepo keeps the consent history of players. Moves of players
+// without research consent in force are stored without boards.
+type ConsentRepo interface {
+	Create(ctx context.Context, consent entity.Consent) (entity.Consent, error)
+	// ListByPlayer returns the player's consents, latest first.
+	ListByPlayer(ctx context.Context, playerID string) ([]entity.Consent, error)
+	// Withdraw withdraws the consent in force of audit.PlayerID and deletes
+	// or anonymizes the player's data as audit.Action says, like
+	// PrivacyRepo, clearing the recorded boards when anonymizing. It returns
+	// pgx.ErrNoRows when no consent is in force.
+	Withdraw(ctx context.Context, audit entity.PrivacyAudit) (entity.Consent, entity.PrivacyAudit, error)
+}
+
+// PrivacyRepo runs data subject operations. Each one stores its audit
+// record in the same statement.
+type PrivacyRepo interface {
+	// Delete removes the player's sessions, and with them every match, move
+	// and stat, together with their cohorts and experiment assignments. The
+	// copies of their data in outbox events, webhook deliveries, xAPI
+	// statements and audited session changes go too.
+	Delete(ctx context.Context, audit entity.PrivacyAudit) (entity.PrivacyAudit, error)
+	// Anonymize replaces the player ID and the devices with random tokens
+	// that are not stored, also in those copies, keeping the data for
+	// analytics.
+	Anonymize(ctx context.Context, audit entity.PrivacyAudit) (entity.PrivacyAudit, error)
+	RecordAudit(ctx context.Context, audit entity.PrivacyAudit) (entity.PrivacyAudit, error)
+	// ListAudit returns the latest records first, only those of playerID
+	// when it is set.
+	ListAudit(ctx context.Context, playerID *string, limit int) ([]entity.PrivacyAudit, error)
+}
+
+type ExperimentRepo interface {
+	Create(ctx context.Context, experiment entity.Experiment) (entity.Experiment, error)
+	List(ctx context.Context) ([]entity.Experiment, error)
+	ListActive(ctx context.Context) ([]entity.Experiment, error)
+	// Deactivate returns pgx.ErrNoRows when the experiment does not exist.
+	Deactivate(ctx context.Context, id int) (entity.Experiment, error)
+	// Assign stores the assignment unless the unit already has one, and
+	// returns the stored assignment either way.
+	Assign(ctx context.Context, assignment entity.ExperimentAssignment) (entity.ExperimentAssignment, error)
+}
+
+type MatchStatsRepo interface {
+	GetByMatch(ctx context.Context, matchID string) (entity.MatchKPI, error)
+}
+
+type MatchMetricsRepo interface {
+	Upsert(ctx context.Context, metrics entity.MatchMetrics) (entity.MatchMetrics, error)
+	GetByMatch(ctx context.Context, matchID string) (entity.MatchMetrics, error)
+}
+
+type MatchPauseRepo interface {
+	// Pause opens a pause at the given time. It returns pgx.ErrNoRows when
+	// the match is already paused.
+	Pause(ctx context.Context, matchID string, at time.Time) (entity.MatchPause, error)
+	// Resume closes the open pause. It returns pgx.ErrNoRows when the match
+	// is not paused.
+	Resume(ctx context.Context, matchID string, at time.Time) (entity.MatchPause, error)
+	ListByMatch(ctx context.Context, matchID string) ([]entity.MatchPause, error)
+	// ListTimedOut returns active matches whose time, excluding pauses, is
+	// over the time limit of their difficulty.
+	ListTimedOut(ctx context.Context, limit int) ([]entity.Match, error)
+}
+
+type HintRepo interface {
+	// Create stores a hint at the current seq of the match.
+	Create(ctx context.Context, hint entity.Hint) (entity.Hint, error)
+	ListByMatch(ctx context.Context, matchID string) ([]entity.Hint, error)
+	Dismiss(ctx context.Context, matchID, id string) (entity.Hint, error)
+	// ListUnresolved returns the hints shown at seq that have no answer yet.
+	ListUnresolved(ctx context.Context, matchID string, seq int) ([]entity.Hint, error)
+	// Resolve records whether the hint was followed. Hints already resolved
+	// are left untouched.
+	Resolve(ctx context.Context, matchID, id string, followed bool) error
+	SummaryByMatch(ctx context.Context, matchID string) (entity.HintSummary, error)
+}
+
+type OutboxRepo interface {
+	// ClaimPending leases up to limit due events so that no other relay picks
+	// them up until the lease expires.
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxEvent, error)
+	MarkDelivered(ctx context.Context, id int64) error
+	// MarkFailed records a failed attempt. A nil retryAt gives up on the
+	// event, which stays in the outbox as a dead letter.
+	MarkFailed(ctx context.Context, id int64, retryAt *time.Time, cause string) error
+	// PruneDelivered deletes up to limit events delivered before the cutoff
+	// and returns how many were deleted.
+	PruneDelivered(ctx context.Context, before time.Time, limit int) (int, error)
+}
+
+type WebhookRepo interface {
+	Create(ctx context.Context, webhook entity.Webhook) (entity.Webhook, error)
+	Get(ctx context.Context, id string) (entity.Webhook, error)
+	List(ctx context.Context) ([]entity.Webhook, error)
+	Deactivate(ctx context.Context, id string) (entity.Webhook, error)
+	// EnqueueDeliveries creates one pending delivery per active webhook
+	// subscribed to the event type; repeated calls for an event are no-ops.
+	EnqueueDeliveries(ctx context.Context, event entity.OutboxEvent) (int, error)
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id int64, statusCode int) error
+	// MarkFailed records a failed attempt. A nil retryAt gives up on the
+	// delivery.
+	MarkFailed(ctx context.Context, id int64, statusCode *int, cause string, retryAt *time.Time) error
+	ListDeliveries(ctx context.Context, webhookID string, limit int) ([]entity.WebhookDelivery, error)
 }
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+type WebhookSender interface {
+	// Send posts the delivery to the webhook and returns the HTTP status code,
+	// or 0 when no response was received.
+	Send(ctx context.Context, webhook entity.Webhook, delivery entity.WebhookDelivery) (int, error)
+}
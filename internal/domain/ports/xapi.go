@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+type XAPIQueue interface {
+	// Enqueue stores a statement for delivery; statements already queued
+	// under the same ID are ignored.
+	Enqueue(ctx context.Context, statementID string, statement json.RawMessage) error
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entity.XAPIStatement, error)
+	MarkSent(ctx context.Context, ids []int64) error
+	// MarkFailed records a failed attempt. A nil retryAt gives up on the
+	// statement.
+	MarkFailed(ctx context.Context, id int64, cause string, retryAt *time.Time) error
+}
+
+// LRSClient posts statements to a learning record store.
+type LRSClient interface {
+	SendStatements(ctx context.Context, statements []json.RawMessage) error
+}
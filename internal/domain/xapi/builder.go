@@ -0,0 +1,240 @@
+package xapi
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+	"github.com/org/ranas-bdi-backend/internal/domain/game"
+)
+
+const (
+	activityTypeGame  = "http://activitystrea.ms/schema/1.0/game"
+	activityTypeLevel = "http://adlnet.gov/expapi/activities/assessment"
+)
+
+// Builder turns game entities into statements. Statement IDs are derived
+// from the entity they describe, so building the same statement twice yields
+// the same ID and the LRS can discard duplicates.
+type Builder struct {
+	// HomePage identifies the system players have accounts in.
+	HomePage string
+	// ActivityBase is the IRI prefix of the game, its levels and extensions.
+	ActivityBase string
+}
+
+func NewBuilder(homePage, activityBase string) Builder {
+	return Builder{
+		HomePage:     strings.TrimRight(homePage, "/"),
+		ActivityBase: strings.TrimRight(activityBase, "/"),
+	}
+}
+
+func (b Builder) SessionStarted(s entity.Session) Statement {
+	return Statement{
+		ID:        b.statementID("session-started", s.ID),
+		Actor:     b.actor(s),
+		Verb:      VerbInitialized,
+		Object:    b.game(),
+		Context:   b.context(s, nil),
+		Timestamp: s.StartedAt,
+	}
+}
+
+func (b Builder) SessionFinished(s entity.Session) Statement {
+	st := Statement{
+		ID:        b.statementID("session-finished", s.ID),
+		Actor:     b.actor(s),
+		Verb:      VerbTerminated,
+		Object:    b.game(),
+		Context:   b.context(s, nil),
+		Timestamp: s.StartedAt,
+	}
+	if s.EndedAt != nil {
+		st.Timestamp = *s.EndedAt
+		st.Result = &Result{Duration: duration(s.EndedAt.Sub(s.StartedAt))}
+	}
+	return st
+}
+
+func (b Builder) MatchStarted(s entity.Session, m entity.Match, d entity.Difficulty) Statement {
+	return Statement{
+		ID:        b.statementID("match-started", m.ID),
+		Actor:     b.actor(s),
+		Verb:      VerbAttempted,
+		Object:    b.level(m, d),
+		Context:   b.context(s, &m),
+		Timestamp: m.StartedAt,
+	}
+}
+
+func (b Builder) MoveRecorded(s entity.Session, m entity.Match, d entity.Difficulty, mv entity.Move) Statement {
+	success := mv.IsCorrect
+	return Statement{
+		ID:     b.statementID("move", mv.ID),
+		Actor:  b.actor(s),
+		Verb:   VerbInteracted,
+		Object: b.level(m, d),
+		Result: &Result{
+			Success:  &success,
+			Duration: duration(time.Duration(mv.ElapsedMs) * time.Millisecond),
+			Extensions: map[string]any{
+				b.extension("seq"):          mv.Seq,
+				b.extension("from"):         mv.FromIdx,
+				b.extension("to"):           mv.ToIdx,
+				b.extension("move-kind"):    mv.MoveKind,
+				b.extension("interruption"): mv.Interruption,
+			},
+		},
+		Context:   b.context(s, &m),
+		Timestamp: mv.OccurredAt,
+	}
+}
+
+// HintShown reports a hint the player saw. The hint-source extension tells
+// hints the player asked for from those the agent offered on its own.
+func (b Builder) HintShown(s entity.Session, m entity.Match, d entity.Difficulty, h entity.Hint) Statement {
+	extensions := map[string]any{
+		b.extension("seq"):         h.Seq,
+		b.extension("hint-type"):   h.HintType,
+		b.extension("hint-source"): h.Source,
+		b.extension("dismissed"):   h.Dismissed,
+	}
+	if h.FromIdx != nil {
+		extensions[b.extension("from")] = *h.FromIdx
+	}
+	if h.ToIdx != nil {
+		extensions[b.extension("to")] = *h.ToIdx
+	}
+	return Statement{
+		ID:        b.statementID("hint", h.ID),
+		Actor:     b.actor(s),
+		Verb:      VerbExperienced,
+		Object:    b.level(m, d),
+		Result:    &Result{Extensions: extensions},
+		Context:   b.context(s, &m),
+		Timestamp: h.CreatedAt,
+	}
+}
+
+// MatchFinished reports how the level ended. A won match is passed and
+// scored by how close it came to the shortest solution; a lost or timed out
+// one is failed and anything else (aborted) is terminated. kpi may be nil.
+func (b Builder) MatchFinished(s entity.Session, m entity.Match, d entity.Difficulty, kpi *entity.MatchKPI) Statement {
+	verb := VerbTerminated
+	outcome := ""
+	if m.Outcome != nil {
+		outcome = *m.Outcome
+	}
+	switch outcome {
+	case entity.OutcomeWin:
+		verb = VerbPassed
+	case entity.OutcomeLose, entity.OutcomeTimeout:
+		verb = VerbFailed
+	}
+
+	success := outcome == entity.OutcomeWin
+	completion := outcome == entity.OutcomeWin || outcome == entity.OutcomeLose || outcome == entity.OutcomeTimeout
+	result := &Result{Success: &success, Completion: &completion}
+	ts := m.StartedAt
+	if m.EndedAt != nil {
+		ts = *m.EndedAt
+		result.Duration = duration(m.EndedAt.Sub(m.StartedAt))
+	}
+	if kpi != nil {
+		result.Extensions = map[string]any{
+			b.extension("total-moves"): kpi.TotalMoves,
+			b.extension("errors"):      kpi.Errors,
+			b.extension("avg-time-ms"): kpi.AvgTimeMs,
+			b.extension("undos"):       kpi.Undos,
+			b.extension("restarts"):    kpi.Restarts,
+		}
+		if shortest := shortestSolution(d); success && kpi.TotalMoves > 0 && shortest > 0 {
+			scaled := min(1, float64(shortest)/float64(kpi.TotalMoves))
+			result.Score = &Score{Scaled: scaled, Raw: scaled * 100, Min: 0, Max: 100}
+		}
+	}
+
+	return Statement{
+		ID:        b.statementID("match-finished", m.ID),
+		Actor:     b.actor(s),
+		Verb:      verb,
+		Object:    b.level(m, d),
+		Result:    result,
+		Context:   b.context(s, &m),
+		Timestamp: ts,
+	}
+}
+
+// actor identifies the player by account. Sessions without a player are
+// reported under a per-session account.
+func (b Builder) actor(s entity.Session) Actor {
+	name := "session:" + s.ID
+	if s.PlayerID != nil {
+		name = *s.PlayerID
+	}
+	return Actor{ObjectType: "Agent", Account: Account{HomePage: b.HomePage, Name: name}}
+}
+
+func (b Builder) game() Activity {
+	return Activity{
+		ObjectType: "Activity",
+		ID:         b.ActivityBase,
+		Definition: &ActivityDefinition{Name: LangMap{"en-US": "Ranas"}, Type: activityTypeGame},
+	}
+}
+
+func (b Builder) level(m entity.Match, d entity.Difficulty) Activity {
+	return Activity{
+		ObjectType: "Activity",
+		ID:         fmt.Sprintf("%s/levels/%s/%d", b.ActivityBase, d.Name, m.LevelN),
+		Definition: &ActivityDefinition{
+			Name: LangMap{"en-US": fmt.Sprintf("Ranas %s level %d (%d blocks)", d.Name, m.LevelN, d.NumberOfBlocks)},
+			Type: activityTypeLevel,
+		},
+	}
+}
+
+func (b Builder) context(s entity.Session, m *entity.Match) *Context {
+	ctx := &Context{
+		Registration:      s.ID,
+		ContextActivities: &ContextActivities{Parent: []Activity{b.game()}},
+	}
+	if s.Device != nil {
+		ctx.Platform = *s.Device
+	}
+	if m != nil {
+		ctx.Extensions = map[string]any{b.extension("match-id"): m.ID}
+	}
+	return ctx
+}
+
+func (b Builder) extension(name string) string {
+	return b.ActivityBase + "/extensions/" + name
+}
+
+func (b Builder) statementID(kind, id string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(b.ActivityBase+"/statements/"+kind+"/"+id)).String()
+}
+
+// duration formats d as an ISO 8601 duration with centisecond precision, as
+// recommended by the xAPI spec.
+func duration(d time.Duration) string {
+	return fmt.Sprintf("PT%.2fS", d.Seconds())
+}
+
+// shortestSolution is the number of moves of the shortest solution of the
+// difficulty's starting board, or -1 when it cannot be built.
+func shortestSolution(d entity.Difficulty) int {
+	if len(d.Layout) == 0 {
+		return game.MinimumMoves(d.NumberOfBlocks)
+	}
+	initial, err := game.FromLayout(d.Layout, d.NumberOfBlocks)
+	if err != nil {
+		return -1
+	}
+	return game.NewSolver().Distance(initial)
+}
@@ -0,0 +1,79 @@
+package xapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/entity"
+)
+
+func TestMatchFinishedStatement(t *testing.T) {
+	b := NewBuilder("https://lab.example.org/", "https://lab.example.org/xapi/ranas/")
+	player := "7d3c3b1e-0000-4000-8000-000000000001"
+	device := "Meta Quest 3"
+	outcome := "win"
+	started := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ended := started.Add(90 * time.Second)
+
+	session := entity.Session{ID: "s-1", PlayerID: &player, Device: &device}
+	match := entity.Match{ID: "m-1", SessionID: "s-1", LevelN: 1, StartedAt: started, EndedAt: &ended, Outcome: &outcome}
+	difficulty := entity.Difficulty{ID: 1, Name: "easy", NumberOfBlocks: 7}
+	kpi := &entity.MatchKPI{TotalMoves: 20, Errors: 2}
+
+	st := b.MatchFinished(session, match, difficulty, kpi)
+	require.Equal(t, VerbPassed, st.Verb)
+	require.Equal(t, Account{HomePage: "https://lab.example.org", Name: player}, st.Actor.Account)
+	require.Equal(t, "https://lab.example.org/xapi/ranas/levels/easy/1", st.Object.ID)
+	require.Equal(t, ended, st.Timestamp)
+	require.Equal(t, "PT90.00S", st.Result.Duration)
+	require.True(t, *st.Result.Success)
+	// 15 moves is the shortest solution with 7 blocks.
+	require.InDelta(t, 0.75, st.Result.Score.Scaled, 1e-9)
+	require.Equal(t, "s-1", st.Context.Registration)
+	require.Equal(t, device, st.Context.Platform)
+
+	require.Equal(t, st.ID, b.MatchFinished(session, match, difficulty, nil).ID, "IDs must be deterministic")
+	require.NotEqual(t, st.ID, b.MatchStarted(session, match, difficulty).ID)
+}
+
+func TestStatementsWithoutPlayer(t *testing.T) {
+	b := NewBuilder("https://lab.example.org", "https://lab.example.org/xapi/ranas")
+	lose := "lose"
+	session := entity.Session{ID: "s-2"}
+	match := entity.Match{ID: "m-2", Outcome: &lose}
+
+	st := b.MatchFinished(session, match, entity.Difficulty{NumberOfBlocks: 7}, &entity.MatchKPI{TotalMoves: 30})
+	require.Equal(t, VerbFailed, st.Verb)
+	require.Equal(t, "session:s-2", st.Actor.Account.Name)
+	require.Nil(t, st.Result.Score)
+	require.True(t, *st.Result.Completion)
+}
+
+func TestHintShownStatement(t *testing.T) {
+	b := NewBuilder("https://lab.example.org", "https://lab.example.org/xapi/ranas")
+	player := "7d3c3b1e-0000-4000-8000-000000000001"
+	shown := time.Date(2025, 1, 1, 12, 0, 5, 0, time.UTC)
+	from, to := 2, 3
+	session := entity.Session{ID: "s-1", PlayerID: &player}
+	match := entity.Match{ID: "m-1", LevelN: 2}
+	hint := entity.Hint{ID: "h-1", MatchID: "m-1", Seq: 4, HintType: entity.HintTypeNextMove, Source: entity.HintSourcePlayer, FromIdx: &from, ToIdx: &to, CreatedAt: shown}
+
+	st := b.HintShown(session, match, entity.Difficulty{Name: "easy", NumberOfBlocks: 7}, hint)
+	require.Equal(t, VerbExperienced, st.Verb)
+	require.Equal(t, player, st.Actor.Account.Name)
+	require.Equal(t, "https://lab.example.org/xapi/ranas/levels/easy/2", st.Object.ID)
+	require.Equal(t, shown, st.Timestamp)
+	ext := func(name string) any {
+		return st.Result.Extensions["https://lab.example.org/xapi/ranas/extensions/"+name]
+	}
+	require.Equal(t, entity.HintSourcePlayer, ext("hint-source"))
+	require.Equal(t, entity.HintTypeNextMove, ext("hint-type"))
+	require.Equal(t, 2, ext("from"))
+	require.Equal(t, 3, ext("to"))
+
+	hint.FromIdx, hint.ToIdx = nil, nil
+	st = b.HintShown(session, match, entity.Difficulty{Name: "easy", NumberOfBlocks: 7}, hint)
+	require.NotContains(t, st.Result.Extensions, "https://lab.example.org/xapi/ranas/extensions/from")
+}
@@ -0,0 +1,82 @@
+// Package xapi maps game activity to Experience API (xAPI 1.0.3) statements
+// for learning record stores.
+package xapi
+
+import "time"
+
+const Version = "1.0.3"
+
+// Verbs used by the game, from the ADL vocabulary.
+var (
+	VerbInitialized = Verb{ID: "http://adlnet.gov/expapi/verbs/initialized", Display: LangMap{"en-US": "initialized"}}
+	VerbAttempted   = Verb{ID: "http://adlnet.gov/expapi/verbs/attempted", Display: LangMap{"en-US": "attempted"}}
+	VerbInteracted  = Verb{ID: "http://adlnet.gov/expapi/verbs/interacted", Display: LangMap{"en-US": "interacted"}}
+	VerbExperienced = Verb{ID: "http://adlnet.gov/expapi/verbs/experienced", Display: LangMap{"en-US": "experienced"}}
+	VerbPassed      = Verb{ID: "http://adlnet.gov/expapi/verbs/passed", Display: LangMap{"en-US": "passed"}}
+	VerbFailed      = Verb{ID: "http://adlnet.gov/expapi/verbs/failed", Display: LangMap{"en-US": "failed"}}
+	VerbTerminated  = Verb{ID: "http://adlnet.gov/expapi/verbs/terminated", Display: LangMap{"en-US": "terminated"}}
+)
+
+type LangMap map[string]string
+
+type Statement struct {
+	ID        string    `json:"id"`
+	Actor     Actor     `json:"actor"`
+	Verb      Verb      `json:"verb"`
+	Object    Activity  `json:"object"`
+	Result    *Result   `json:"result,omitempty"`
+	Context   *Context  `json:"context,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type Actor struct {
+	ObjectType string  `json:"objectType"`
+	Account    Account `json:"account"`
+}
+
+type Account struct {
+	HomePage string `json:"homePage"`
+	Name     string `json:"name"`
+}
+
+type Verb struct {
+	ID      string  `json:"id"`
+	Display LangMap `json:"display"`
+}
+
+type Activity struct {
+	ObjectType string              `json:"objectType"`
+	ID         string              `json:"id"`
+	Definition *ActivityDefinition `json:"definition,omitempty"`
+}
+
+type ActivityDefinition struct {
+	Name LangMap `json:"name,omitempty"`
+	Type string  `json:"type,omitempty"`
+}
+
+type Result struct {
+	Score      *Score         `json:"score,omitempty"`
+	Success    *bool          `json:"success,omitempty"`
+	Completion *bool          `json:"completion,omitempty"`
+	Duration   string         `json:"duration,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+type Score struct {
+	Scaled float64 `json:"scaled"`
+	Raw    float64 `json:"raw"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+type Context struct {
+	Registration      string             `json:"registration,omitempty"`
+	Platform          string             `json:"platform,omitempty"`
+	ContextActivities *ContextActivities `json:"contextActivities,omitempty"`
+	Extensions        map[string]any     `json:"extensions,omitempty"`
+}
+
+type ContextActivities struct {
+	Parent []Activity `json:"parent,omitempty"`
+}
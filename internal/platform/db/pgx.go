@@ -85,6 +85,7 @@ func Init(ctx context.Context, cfg Config) error {
 		if cfg.MaxConnIdleTime > 0 {
 			pc.MaxConnIdleTime = cfg.MaxConnIdleTime
 		}
+		pc.ConnConfig.Tracer = queryLogger{}
 
 		pool, initErr = pgxpool.NewWithConfig(ctx, pc)
 		if initErr != nil {
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	sql     string
+	startAt time.Time
+}
+
+// queryLogger logs every statement at debug level and failures at warn level.
+// Records carry the request ID and other attributes found in ctx.
+type queryLogger struct{}
+
+func (queryLogger) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{sql: data.SQL, startAt: time.Now()})
+}
+
+func (queryLogger) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	qs, _ := ctx.Value(queryStartKey{}).(queryStart)
+	attrs := []any{
+		"sql", strings.Join(strings.Fields(qs.sql), " "),
+		"duration_ms", time.Since(qs.startAt).Milliseconds(),
+	}
+
+	if data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows) {
+		slog.WarnContext(ctx, "query failed", append(attrs, "error", data.Err)...)
+		return
+	}
+	slog.DebugContext(ctx, "query", append(attrs, "rows_affected", data.CommandTag.RowsAffected())...)
+}
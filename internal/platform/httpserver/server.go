@@ -0,0 +1,93 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+type Config struct {
+	Addr              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+}
+
+// DefaultConfig returns the timeouts used when no override is configured.
+func DefaultConfig() Config {
+	return Config{
+		Addr:              ":8080",
+		ReadTimeout:       15 * time.Second,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      15 * time.Second,
+		IdleTimeout:       60 * time.Second,
+		ShutdownTimeout:   20 * time.Second,
+	}
+}
+
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+
+	if port := os.Getenv("PORT"); port != "" {
+		cfg.Addr = ":" + port
+	}
+	durationFromEnv("HTTP_READ_TIMEOUT", &cfg.ReadTimeout)
+	durationFromEnv("HTTP_READ_HEADER_TIMEOUT", &cfg.ReadHeaderTimeout)
+	durationFromEnv("HTTP_WRITE_TIMEOUT", &cfg.WriteTimeout)
+	durationFromEnv("HTTP_IDLE_TIMEOUT", &cfg.IdleTimeout)
+	durationFromEnv("HTTP_SHUTDOWN_TIMEOUT", &cfg.ShutdownTimeout)
+
+	return cfg
+}
+
+func durationFromEnv(key string, dst *time.Duration) {
+	if raw := os.Getenv(key); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil {
+			*dst = d
+		}
+	}
+}
+
+func New(cfg Config, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+// Run serves until ctx is cancelled and then drains in-flight requests,
+// giving up after shutdownTimeout.
+func Run(ctx context.Context, srv *http.Server, shutdownTimeout time.Duration) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("httpserver.Run: shutdown: %w", err)
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package httpserver
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+	return addr
+}
+
+func TestRunDrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		_, _ = io.WriteString(w, "done")
+	})
+
+	cfg := DefaultConfig()
+	cfg.Addr = freeAddr(t)
+	srv := New(cfg, handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- Run(ctx, srv, time.Second) }()
+
+	type result struct {
+		resp *http.Response
+		err  error
+	}
+	respCh := make(chan result, 1)
+	go func() {
+		var res result
+		for i := 0; i < 50; i++ {
+			res.resp, res.err = http.Get("http://" + cfg.Addr)
+			if res.err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		respCh <- res
+	}()
+
+	<-started
+	cancel()
+	res := <-respCh
+
+	require.NoError(t, res.err)
+	body, err := io.ReadAll(res.resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, res.resp.Body.Close())
+	require.Equal(t, "done", string(body))
+	require.NoError(t, <-runErr)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("PORT", "9090")
+	t.Setenv("HTTP_WRITE_TIMEOUT", "45s")
+	t.Setenv("HTTP_IDLE_TIMEOUT", "not-a-duration")
+
+	cfg := ConfigFromEnv()
+	require.Equal(t, ":9090", cfg.Addr)
+	require.Equal(t, 45*time.Second, cfg.WriteTimeout)
+	require.Equal(t, DefaultConfig().IdleTimeout, cfg.IdleTimeout)
+}
@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ctxKey int
+
+const (
+	requestIDKey ctxKey = iota
+	actorKey
+	attrsKey
+)
+
+// New returns a JSON logger that also emits the request ID, the active trace ID
+// and any attributes attached to the context with With.
+func New(w io.Writer, level slog.Leveler) *slog.Logger {
+	return slog.New(contextHandler{slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})})
+}
+
+// LevelFromEnv reads LOG_LEVEL (debug, info, warn, error); it defaults to info.
+func LevelFromEnv() slog.Level {
+	switch strings.ToLower(os.Getenv("LOG_LEVEL")) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// WithActor records who the work in ctx is done for, e.g. the name of an admin
+// token, so audit records can name it.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+func Actor(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey).(string)
+	return actor
+}
+
+// With attaches attributes, such as session_id or match_id, to every record
+// logged with ctx from here on.
+func With(ctx context.Context, args ...any) context.Context {
+	prev, _ := ctx.Value(attrsKey).([]slog.Attr)
+	attrs := append([]slog.Attr(nil), prev...)
+
+	var r slog.Record
+	r.Add(args...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return context.WithValue(ctx, attrsKey, attrs)
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()))
+	}
+	if attrs, ok := ctx.Value(attrsKey).([]slog.Attr); ok {
+		// Attributes passed explicitly at the call site win over context ones.
+		seen := make(map[string]bool, r.NumAttrs())
+		r.Attrs(func(a slog.Attr) bool {
+			seen[a.Key] = true
+			return true
+		})
+		for _, a := range attrs {
+			if !seen[a.Key] {
+				seen[a.Key] = true
+				r.AddAttrs(a)
+			}
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
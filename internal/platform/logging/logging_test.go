@@ -0,0 +1,28 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerAddsContextAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = With(ctx, "session_id", "s-1", "match_id", "m-1")
+	logger.InfoContext(ctx, "move recorded", "match_id", "m-2", "seq", 3)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	require.Equal(t, "move recorded", record["msg"])
+	require.Equal(t, "req-1", record["request_id"])
+	require.Equal(t, "s-1", record["session_id"])
+	require.Equal(t, "m-2", record["match_id"])
+	require.EqualValues(t, 3, record["seq"])
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/org/ranas-bdi-backend/internal/domain/ports"
+)
+
+const namespace = "ranas"
+
+// Registry owns every collector exposed on /metrics. It is not the global
+// Prometheus registry so tests can build independent instances.
+type Registry struct {
+	reg *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	movesRecorded   *prometheus.CounterVec
+	movesRejected   *prometheus.CounterVec
+	matchesFinished *prometheus.CounterVec
+}
+
+var _ ports.GameMetrics = (*Registry)(nil)
+
+func New() *Registry {
+	r := &Registry{
+		reg: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by method and route template.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		movesRecorded: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "game",
+			Name:      "moves_recorded_total",
+			Help:      "Moves persisted, split by correctness.",
+		}, []string{"correct"}),
+		movesRejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "game",
+			Name:      "moves_rejected_total",
+			Help:      "Move submissions rejected before being persisted, by reason.",
+		}, []string{"reason"}),
+		matchesFinished: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "game",
+			Name:      "matches_finished_total",
+			Help:      "Matches closed, by difficulty and outcome.",
+		}, []string{"difficulty_id", "outcome"}),
+	}
+
+	r.reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		r.httpRequests,
+		r.httpDuration,
+		r.movesRecorded,
+		r.movesRejected,
+		r.matchesFinished,
+	)
+	return r
+}
+
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.reg, promhttp.HandlerOpts{Registry: r.reg})
+}
+
+// RegisterPool exposes pgxpool statistics, read on every scrape.
+func (r *Registry) RegisterPool(getPool func() (*pgxpool.Pool, error)) {
+	r.reg.MustRegister(newPoolCollector(getPool))
+}
+
+func (r *Registry) ObserveHTTP(method, route string, status int, elapsed time.Duration) {
+	r.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	r.httpDuration.WithLabelValues(method, route).Observe(elapsed.Seconds())
+}
+
+func (r *Registry) MoveRecorded(correct bool) {
+	r.movesRecorded.WithLabelValues(strconv.FormatBool(correct)).Inc()
+}
+
+func (r *Registry) MoveRejected(reason string) {
+	r.movesRejected.WithLabelValues(reason).Inc()
+}
+
+func (r *Registry) MatchFinished(difficultyID int, outcome string) {
+	if outcome == "" {
+		outcome = "none"
+	}
+	r.matchesFinished.WithLabelValues(strconv.Itoa(difficultyID), outcome).Inc()
+}
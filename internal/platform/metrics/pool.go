@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type poolCollector struct {
+	getPool func() (*pgxpool.Pool, error)
+
+	totalConns      *prometheus.Desc
+	acquiredConns   *prometheus.Desc
+	idleConns       *prometheus.Desc
+	maxConns        *prometheus.Desc
+	acquireCount    *prometheus.Desc
+	acquireDuration *prometheus.Desc
+	emptyAcquire    *prometheus.Desc
+	canceledAcquire *prometheus.Desc
+}
+
+func newPoolCollector(getPool func() (*pgxpool.Pool, error)) *poolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+	return &poolCollector{
+		getPool:         getPool,
+		totalConns:      desc("total_conns", "Connections currently open in the pool."),
+		acquiredConns:   desc("acquired_conns", "Connections currently checked out."),
+		idleConns:       desc("idle_conns", "Connections currently idle."),
+		maxConns:        desc("max_conns", "Maximum size of the pool."),
+		acquireCount:    desc("acquire_total", "Successful connection acquisitions."),
+		acquireDuration: desc("acquire_duration_seconds_total", "Time spent waiting to acquire connections."),
+		emptyAcquire:    desc("empty_acquire_total", "Acquisitions that had to wait because the pool was empty."),
+		canceledAcquire: desc("canceled_acquire_total", "Acquisitions cancelled by their context."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.totalConns
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.maxConns
+	ch <- c.acquireCount
+	ch <- c.acquireDuration
+	ch <- c.emptyAcquire
+	ch <- c.canceledAcquire
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	pool, err := c.getPool()
+	if err != nil {
+		return
+	}
+	stat := pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquire, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquire, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
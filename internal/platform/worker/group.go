@@ -0,0 +1,47 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Group runs long-lived background loops that share a single cancellation
+// signal, so they can be stopped together during shutdown.
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewGroup(parent context.Context) *Group {
+	ctx, cancel := context.WithCancel(parent)
+	return &Group{ctx: ctx, cancel: cancel}
+}
+
+// Go starts fn in its own goroutine. fn must return once ctx is done.
+func (g *Group) Go(fn func(ctx context.Context)) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		fn(g.ctx)
+	}()
+}
+
+// Stop cancels every worker and waits for them to return or for ctx to expire.
+func (g *Group) Stop(ctx context.Context) error {
+	g.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("worker.Stop: %w", ctx.Err())
+	}
+}